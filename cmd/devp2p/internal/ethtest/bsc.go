@@ -0,0 +1,511 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/internal/utesting"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// bscVoteBudget is the number of votes a node accepts from a peer within a
+// period, from eth/protocols/bsc (30 seconds at 68 votes per second).
+const bscVoteBudget = 30 * 68
+
+func (s *Suite) BscTests() []utesting.Test {
+	return []utesting.Test{
+		// handshake
+		{Name: "Status", Fn: s.TestBscStatus},
+		{Name: "BscWithoutEth", Fn: s.TestBscWithoutEth},
+		{Name: "MissingUpgradeStatus", Fn: s.TestBscMissingUpgradeStatus},
+		{Name: "InvalidUpgradeStatus", Fn: s.TestBscInvalidUpgradeStatus},
+		// block ranges
+		{Name: "GetBlocksByRange", Fn: s.TestBscGetBlocksByRange},
+		{Name: "GetBlocksByRangeBounds", Fn: s.TestBscGetBlocksByRangeBounds},
+		{Name: "GetBlocksByRangeInvalid", Fn: s.TestBscGetBlocksByRangeInvalid},
+		{Name: "UnsolicitedBlocksByRange", Fn: s.TestBscUnsolicitedBlocksByRange},
+		{Name: "BlockBodiesSidecars", Fn: s.TestBscBlockBodiesSidecars},
+		// votes
+		{Name: "VoteLimit", Fn: s.TestBscVoteLimit},
+		{Name: "InvalidVotes", Fn: s.TestBscInvalidVotes},
+		// witnesses
+		{Name: "GetWitnessesLimit", Fn: s.TestBscGetWitnessesLimit},
+	}
+}
+
+// dialAndPeerBsc creates a peer connection running the bsc protocol.
+func (s *Suite) dialAndPeerBsc() (*Conn, error) {
+	conn, err := s.dialBsc()
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.peer(s.chain, nil); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("peering failed: %v", err)
+	}
+	return conn, nil
+}
+
+// bscStatusOnly performs the protocol handshake and sends the eth status, but
+// leaves the upgrade status to the caller.
+func (s *Suite) bscStatusOnly() (*Conn, error) {
+	conn, err := s.dialBsc()
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.handshake(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("handshake failed: %v", err)
+	}
+	status := new(eth.StatusPacket68)
+	if err := conn.ReadMsg(ethProto, eth.StatusMsg, status); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read status: %v", err)
+	}
+	if err := conn.Write(ethProto, eth.StatusMsg, conn.defaultStatus(s.chain)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to write status: %v", err)
+	}
+	return conn, nil
+}
+
+// expectDisconnect waits for the node to drop the connection.
+func (c *Conn) expectDisconnect() error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		code, _, err := c.Read()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return nil // dropped without disconnect message
+		}
+		switch code {
+		case discMsg:
+			return nil
+		case pingMsg:
+			c.Write(baseProto, pongMsg, []byte{})
+		}
+	}
+	return errors.New("node did not disconnect")
+}
+
+// getBlocksByRange requests a range of blocks, returning the response. The
+// node relaying any votes in the meantime is reported as an error.
+func (c *Conn) getBlocksByRange(req *bsc.GetBlocksByRangePacket) (*bsc.BlocksByRangePacket, error) {
+	if err := c.Write(bscProto, bsc.GetBlocksByRangeMsg, req); err != nil {
+		return nil, fmt.Errorf("could not write to connection: %v", err)
+	}
+	for {
+		code, data, err := c.Read()
+		if err != nil {
+			return nil, fmt.Errorf("error reading blocks: %v", err)
+		}
+		switch code {
+		case c.protoOffset(bscProto) + bsc.BlocksByRangeMsg:
+			res := new(bsc.BlocksByRangePacket)
+			if err := rlp.DecodeBytes(data, res); err != nil {
+				return nil, fmt.Errorf("invalid blocks response: %v", err)
+			}
+			if res.RequestId != req.RequestId {
+				return nil, fmt.Errorf("unexpected request id: have %d, want %d", res.RequestId, req.RequestId)
+			}
+			return res, nil
+		case c.protoOffset(bscProto) + bsc.VotesMsg:
+			return nil, errors.New("node relayed votes back to their sender")
+		case discMsg:
+			return nil, errDisc
+		case pingMsg:
+			c.Write(baseProto, pongMsg, []byte{})
+		}
+	}
+}
+
+// checkBlockRange checks that the blocks of a range response are the expected
+// ones of the test chain, from the start block towards genesis.
+func (s *Suite) checkBlockRange(res *bsc.BlocksByRangePacket, start uint64, count int) error {
+	if len(res.Blocks) != count {
+		return fmt.Errorf("wrong number of blocks: have %d, want %d", len(res.Blocks), count)
+	}
+	for i, data := range res.Blocks {
+		want := s.chain.GetBlock(int(start) - i)
+		if data.Header == nil || data.Header.Hash() != want.Hash() {
+			return fmt.Errorf("block %d mismatch: want %d (%x)", i, want.NumberU64(), want.Hash())
+		}
+		if len(data.Txs) != len(want.Transactions()) {
+			return fmt.Errorf("block %d transaction count mismatch: have %d, want %d", i, len(data.Txs), len(want.Transactions()))
+		}
+		for _, sidecar := range data.Sidecars {
+			if sidecar.BlockHash != want.Hash() {
+				return fmt.Errorf("block %d has sidecar of block %x", i, sidecar.BlockHash)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Suite) TestBscStatus(t *utesting.T) {
+	t.Log(`This test performs the eth handshake with the bsc protocol, checking the
+upgrade status and the bsc capability sent by the node.`)
+	conn, err := s.dialAndPeerBsc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if conn.bscCap == nil {
+		conn.bscCap = new(bsc.BscCapPacket)
+		if err := conn.ReadMsg(bscProto, bsc.BscCapMsg, conn.bscCap); err != nil {
+			t.Fatalf("no bsc capability received: %v", err)
+		}
+	}
+	if have, want := conn.bscCap.ProtocolVersion, conn.negotiatedBscProtoVersion; have != want {
+		t.Fatalf("wrong bsc protocol version: have %d, want %d", have, want)
+	}
+}
+
+func (s *Suite) TestBscWithoutEth(t *utesting.T) {
+	t.Log(`This test connects with the bsc protocol only, which the node must reject
+as bsc is only meaningful alongside eth.`)
+	conn, err := s.dial()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	hello := &protoHandshake{
+		Version: 5,
+		Caps:    []p2p.Cap{{Name: bsc.ProtocolName, Version: bsc.Bsc4}},
+		ID:      crypto.FromECDSAPub(&conn.ourKey.PublicKey)[1:],
+	}
+	if err := conn.Write(baseProto, handshakeMsg, hello); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	remote := new(protoHandshake)
+	if err := conn.ReadMsg(baseProto, handshakeMsg, remote); err != nil {
+		t.Fatalf("could not read handshake: %v", err)
+	}
+	if remote.Version >= 5 {
+		conn.SetSnappy(true)
+	}
+	if err := conn.expectDisconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *Suite) TestBscMissingUpgradeStatus(t *utesting.T) {
+	t.Log(`This test skips the upgrade status after the eth status, which the node
+must treat as a failed handshake.`)
+	conn, err := s.bscStatusOnly()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	req := &eth.GetBlockHeadersPacket{
+		RequestId:              1,
+		GetBlockHeadersRequest: &eth.GetBlockHeadersRequest{Origin: eth.HashOrNumber{Number: 1}, Amount: 1},
+	}
+	if err := conn.Write(ethProto, eth.GetBlockHeadersMsg, req); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	if err := conn.expectDisconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *Suite) TestBscInvalidUpgradeStatus(t *utesting.T) {
+	t.Log(`This test sends an upgrade status with an undecodable extension, which
+the node must reject.`)
+	conn, err := s.bscStatusOnly()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	extension := rlp.RawValue{0x83, 0x01, 0x02, 0x03} // a string, not an extension list
+	if err := conn.Write(ethProto, eth.UpgradeStatusMsg, &eth.UpgradeStatusPacket{Extension: &extension}); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	if err := conn.expectDisconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *Suite) TestBscGetBlocksByRange(t *utesting.T) {
+	t.Log(`This test requests block ranges by height and by hash, checking the
+blocks are served from the start block towards genesis.`)
+	conn, err := s.dialAndPeerBsc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	head := s.chain.Head().NumberU64()
+	res, err := conn.getBlocksByRange(&bsc.GetBlocksByRangePacket{RequestId: 1, StartBlockHeight: head, Count: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.checkBlockRange(res, head, 5); err != nil {
+		t.Fatalf("range by height: %v", err)
+	}
+	// The hash takes precedence over the height
+	start := s.chain.GetBlock(int(head) / 2)
+	res, err = conn.getBlocksByRange(&bsc.GetBlocksByRangePacket{RequestId: 2, StartBlockHeight: head, StartBlockHash: start.Hash(), Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.checkBlockRange(res, start.NumberU64(), 3); err != nil {
+		t.Fatalf("range by hash: %v", err)
+	}
+}
+
+func (s *Suite) TestBscGetBlocksByRangeBounds(t *utesting.T) {
+	t.Log(`This test requests block ranges reaching past genesis and of the maximum
+size, which the node must cut to the available blocks.`)
+	conn, err := s.dialAndPeerBsc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	res, err := conn.getBlocksByRange(&bsc.GetBlocksByRangePacket{RequestId: 1, StartBlockHeight: 3, Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.checkBlockRange(res, 3, 4); err != nil {
+		t.Fatalf("range past genesis: %v", err)
+	}
+	head := s.chain.Head().NumberU64()
+	res, err = conn.getBlocksByRange(&bsc.GetBlocksByRangePacket{RequestId: 2, StartBlockHeight: head, Count: bsc.MaxRequestRangeBlocksCount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.checkBlockRange(res, head, int(min(head+1, bsc.MaxRequestRangeBlocksCount))); err != nil {
+		t.Fatalf("maximum range: %v", err)
+	}
+}
+
+func (s *Suite) TestBscGetBlocksByRangeInvalid(t *utesting.T) {
+	t.Log(`This test sends invalid block range requests, each of which must get the
+requesting peer disconnected.`)
+	var unknown common.Hash
+	rand.Read(unknown[:])
+
+	head := s.chain.Head().NumberU64()
+	for _, test := range []struct {
+		desc string
+		req  *bsc.GetBlocksByRangePacket
+	}{
+		{"zero count", &bsc.GetBlocksByRangePacket{RequestId: 1, StartBlockHeight: head, Count: 0}},
+		{"count over limit", &bsc.GetBlocksByRangePacket{RequestId: 2, StartBlockHeight: head, Count: bsc.MaxRequestRangeBlocksCount + 1}},
+		{"unknown start hash", &bsc.GetBlocksByRangePacket{RequestId: 3, StartBlockHash: unknown, Count: 1}},
+		{"start beyond head", &bsc.GetBlocksByRangePacket{RequestId: 4, StartBlockHeight: head + 1000, Count: 1}},
+	} {
+		conn, err := s.dialAndPeerBsc()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.Write(bscProto, bsc.GetBlocksByRangeMsg, test.req); err != nil {
+			t.Fatalf("%s: could not write to connection: %v", test.desc, err)
+		}
+		if err := conn.expectDisconnect(); err != nil {
+			t.Fatalf("%s: %v", test.desc, err)
+		}
+		conn.Close()
+	}
+}
+
+func (s *Suite) TestBscUnsolicitedBlocksByRange(t *utesting.T) {
+	t.Log(`This test sends a block range response nobody asked for, which the node
+must ignore without dropping the peer.`)
+	conn, err := s.dialAndPeerBsc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	unsolicited := &bsc.BlocksByRangePacket{RequestId: 1234, Blocks: []*bsc.BlockData{bsc.NewBlockData(s.chain.Head())}}
+	if err := conn.Write(bscProto, bsc.BlocksByRangeMsg, unsolicited); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	head := s.chain.Head().NumberU64()
+	res, err := conn.getBlocksByRange(&bsc.GetBlocksByRangePacket{RequestId: 1, StartBlockHeight: head, Count: 1})
+	if err != nil {
+		t.Fatalf("peer dropped after unsolicited response: %v", err)
+	}
+	if err := s.checkBlockRange(res, head, 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *Suite) TestBscBlockBodiesSidecars(t *utesting.T) {
+	t.Log(`This test requests block bodies over eth with the bsc protocol running,
+checking the bodies decode with the blob sidecars of the bsc body encoding.`)
+	conn, err := s.dialAndPeerBsc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var blocks []*types.Block
+	for i := s.chain.Len() - 1; i > 0 && len(blocks) < 4; i-- {
+		if block := s.chain.GetBlock(i); len(block.Transactions()) > 0 {
+			blocks = append(blocks, block)
+		}
+	}
+	req := &eth.GetBlockBodiesPacket{RequestId: 66}
+	for _, block := range blocks {
+		req.GetBlockBodiesRequest = append(req.GetBlockBodiesRequest, block.Hash())
+	}
+	if err := conn.Write(ethProto, eth.GetBlockBodiesMsg, req); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	resp := new(eth.BlockBodiesPacket)
+	if err := conn.ReadMsg(ethProto, eth.BlockBodiesMsg, resp); err != nil {
+		t.Fatalf("error reading block bodies msg: %v", err)
+	}
+	bodies, err := resp.List.Items()
+	if err != nil {
+		t.Fatalf("invalid block bodies: %v", err)
+	}
+	if len(bodies) != len(blocks) {
+		t.Fatalf("wrong number of bodies: have %d, want %d", len(bodies), len(blocks))
+	}
+	for i, body := range bodies {
+		txs, err := body.Transactions.Items()
+		if err != nil {
+			t.Fatalf("body %d: invalid transactions: %v", i, err)
+		}
+		if root := types.DeriveSha(types.Transactions(txs), trie.NewStackTrie(nil)); root != blocks[i].TxHash() {
+			t.Fatalf("body %d: transaction root mismatch", i)
+		}
+		if body.Sidecars == nil {
+			continue
+		}
+		sidecars, err := body.Sidecars.Items()
+		if err != nil {
+			t.Fatalf("body %d: invalid sidecars: %v", i, err)
+		}
+		for _, sidecar := range sidecars {
+			if sidecar.BlockHash != blocks[i].Hash() {
+				t.Fatalf("body %d: sidecar of block %x", i, sidecar.BlockHash)
+			}
+		}
+	}
+}
+
+// randomVotes creates votes with random content, which the node can't verify.
+func randomVotes(n int) []*types.VoteEnvelope {
+	votes := make([]*types.VoteEnvelope, n)
+	for i := range votes {
+		vote := &types.VoteEnvelope{Data: new(types.VoteData)}
+		rand.Read(vote.VoteAddress[:])
+		rand.Read(vote.Signature[:])
+		rand.Read(vote.Data.SourceHash[:])
+		rand.Read(vote.Data.TargetHash[:])
+		vote.Data.TargetNumber = uint64(i + 1)
+		votes[i] = vote
+	}
+	return votes
+}
+
+func (s *Suite) TestBscVoteLimit(t *utesting.T) {
+	t.Log(`This test floods the node with more votes than the per peer budget. The
+excess must be dropped without disconnecting, and the votes never relayed back.`)
+	conn, err := s.dialAndPeerBsc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	const batch = 256
+	for sent := 0; sent <= bscVoteBudget; sent += batch {
+		if err := conn.Write(bscProto, bsc.VotesMsg, &bsc.VotesPacket{Votes: randomVotes(batch)}); err != nil {
+			t.Fatalf("could not write votes: %v", err)
+		}
+	}
+	head := s.chain.Head().NumberU64()
+	if _, err := conn.getBlocksByRange(&bsc.GetBlocksByRangePacket{RequestId: 1, StartBlockHeight: head, Count: 1}); err != nil {
+		t.Fatalf("peer unusable after vote flood: %v", err)
+	}
+}
+
+func (s *Suite) TestBscInvalidVotes(t *utesting.T) {
+	t.Log(`This test sends an undecodable votes message, which must get the peer
+disconnected.`)
+	conn, err := s.dialAndPeerBsc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.Write(bscProto, bsc.VotesMsg, []uint{1, 2, 3}); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	if err := conn.expectDisconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *Suite) TestBscGetWitnessesLimit(t *utesting.T) {
+	t.Log(`This test requests block witnesses, checking that one entry is returned
+per block and that oversized requests get the peer disconnected.`)
+	conn, err := s.dialAndPeerBsc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if conn.negotiatedBscProtoVersion < bsc.Bsc4 {
+		t.Logf("witnesses not supported by bsc/%d, skipping", conn.negotiatedBscProtoVersion)
+		return
+	}
+	head := s.chain.Head()
+	req := &bsc.GetWitnessesPacket{RequestId: 1, Hashes: []common.Hash{head.Hash(), head.ParentHash()}}
+	if err := conn.Write(bscProto, bsc.GetWitnessesMsg, req); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	res := new(bsc.WitnessesPacket)
+	if err := conn.ReadMsg(bscProto, bsc.WitnessesMsg, res); err != nil {
+		t.Fatalf("error reading witnesses: %v", err)
+	}
+	if res.RequestId != req.RequestId || len(res.Witnesses) != len(req.Hashes) {
+		t.Fatalf("wrong witnesses response: request id %d, %d witnesses", res.RequestId, len(res.Witnesses))
+	}
+	// Requests over the limit are rejected
+	req = &bsc.GetWitnessesPacket{RequestId: 2, Hashes: make([]common.Hash, bsc.MaxRequestWitnessesCount+1)}
+	if err := conn.Write(bscProto, bsc.GetWitnessesMsg, req); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	if err := conn.expectDisconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
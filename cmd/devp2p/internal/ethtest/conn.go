@@ -26,6 +26,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -84,6 +85,20 @@ func (s *Suite) dialSnap() (*Conn, error) {
 	return conn, nil
 }
 
+// dialBsc creates a connection with all the bsc protocol versions, so the
+// latest one supported by the node is negotiated.
+func (s *Suite) dialBsc() (*Conn, error) {
+	conn, err := s.dial()
+	if err != nil {
+		return nil, fmt.Errorf("dial failed: %v", err)
+	}
+	for _, version := range bsc.ProtocolVersions {
+		conn.caps = append(conn.caps, p2p.Cap{Name: bsc.ProtocolName, Version: version})
+		conn.ourHighestBscProtoVersion = max(conn.ourHighestBscProtoVersion, version)
+	}
+	return conn, nil
+}
+
 // Conn represents an individual connection with a peer
 type Conn struct {
 	*rlpx.Conn
@@ -93,6 +108,10 @@ type Conn struct {
 	ourHighestProtoVersion     uint
 	ourHighestSnapProtoVersion uint
 	caps                       []p2p.Cap
+
+	negotiatedBscProtoVersion uint
+	ourHighestBscProtoVersion uint
+	bscCap                    *bsc.BscCapPacket // received during the status exchange
 }
 
 // Read reads a packet from the connection.
@@ -113,7 +132,7 @@ func (c *Conn) ReadMsg(proto Proto, code uint64, msg any) error {
 		if err != nil {
 			return err
 		}
-		if protoOffset(proto)+code == got {
+		if c.protoOffset(proto)+code == got {
 			return rlp.DecodeBytes(data, msg)
 		}
 	}
@@ -126,7 +145,7 @@ func (c *Conn) Write(proto Proto, code uint64, msg any) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Conn.Write(protoOffset(proto)+code, payload)
+	_, err = c.Conn.Write(c.protoOffset(proto)+code, payload)
 	return err
 }
 
@@ -147,11 +166,11 @@ func (c *Conn) ReadEth() (any, error) {
 			c.Write(baseProto, pongMsg, []byte{})
 			continue
 		}
-		if getProto(code) != ethProto {
+		if c.getProto(code) != ethProto {
 			// Read until eth message.
 			continue
 		}
-		code -= baseProtoLen
+		code -= c.protoOffset(ethProto)
 
 		var msg any
 		switch int(code) {
@@ -195,11 +214,11 @@ func (c *Conn) ReadSnap() (any, error) {
 		if err != nil {
 			return nil, err
 		}
-		if getProto(code) != snapProto {
+		if c.getProto(code) != snapProto {
 			// Read until snap message.
 			continue
 		}
-		code -= baseProtoLen + ethProtoLen
+		code -= c.protoOffset(snapProto)
 
 		var msg any
 		switch int(code) {
@@ -288,6 +307,10 @@ func (c *Conn) handshake() error {
 		if c.ourHighestSnapProtoVersion != c.negotiatedSnapProtoVersion {
 			return fmt.Errorf("could not negotiate snap protocol (remote caps: %v, local snap version: %v)", msg.Caps, c.ourHighestSnapProtoVersion)
 		}
+		// If we require bsc, verify that it was negotiated.
+		if c.ourHighestBscProtoVersion != c.negotiatedBscProtoVersion {
+			return fmt.Errorf("could not negotiate bsc protocol (remote caps: %v, local bsc version: %v)", msg.Caps, c.ourHighestBscProtoVersion)
+		}
 		return nil
 	default:
 		return fmt.Errorf("bad handshake: got msg code %d", code)
@@ -299,6 +322,7 @@ func (c *Conn) handshake() error {
 func (c *Conn) negotiateEthProtocol(caps []p2p.Cap) {
 	var highestEthVersion uint
 	var highestSnapVersion uint
+	var highestBscVersion uint
 	for _, capability := range caps {
 		switch capability.Name {
 		case "eth":
@@ -309,10 +333,27 @@ func (c *Conn) negotiateEthProtocol(caps []p2p.Cap) {
 			if capability.Version > highestSnapVersion && capability.Version <= c.ourHighestSnapProtoVersion {
 				highestSnapVersion = capability.Version
 			}
+		case "bsc":
+			if capability.Version > highestBscVersion && capability.Version <= c.ourHighestBscProtoVersion {
+				highestBscVersion = capability.Version
+			}
 		}
 	}
 	c.negotiatedProtoVersion = highestEthVersion
 	c.negotiatedSnapProtoVersion = highestSnapVersion
+	c.negotiatedBscProtoVersion = highestBscVersion
+}
+
+// defaultStatus creates the status message matching the given chain.
+func (c *Conn) defaultStatus(chain *Chain) *eth.StatusPacket68 {
+	return &eth.StatusPacket68{
+		ProtocolVersion: uint32(c.negotiatedProtoVersion),
+		NetworkID:       chain.config.ChainID.Uint64(),
+		TD:              chain.TD(),
+		Head:            chain.blocks[chain.Len()-1].Hash(),
+		Genesis:         chain.blocks[0].Hash(),
+		ForkID:          chain.ForkID(),
+	}
 }
 
 // statusExchange performs a `Status` message exchange with the given node.
@@ -323,8 +364,16 @@ loop:
 		if err != nil {
 			return fmt.Errorf("failed to read from connection: %w", err)
 		}
+		// The bsc capability is sent concurrently with the eth status
+		if c.negotiatedBscProtoVersion > 0 && code == c.protoOffset(bscProto)+bsc.BscCapMsg {
+			c.bscCap = new(bsc.BscCapPacket)
+			if err := rlp.DecodeBytes(data, c.bscCap); err != nil {
+				return fmt.Errorf("error decoding bsc capability: %w", err)
+			}
+			continue
+		}
 		switch code {
-		case eth.StatusMsg + protoOffset(ethProto):
+		case eth.StatusMsg + c.protoOffset(ethProto):
 			msg := new(eth.StatusPacket68)
 			if err := rlp.DecodeBytes(data, &msg); err != nil {
 				return fmt.Errorf("error decoding status packet: %w", err)
@@ -344,20 +393,12 @@ loop:
 				return errors.New("eth protocol version must be set in Conn")
 			}
 			if status == nil {
-				// default status message
-				status = &eth.StatusPacket68{
-					ProtocolVersion: uint32(c.negotiatedProtoVersion),
-					NetworkID:       chain.config.ChainID.Uint64(),
-					TD:              chain.TD(),
-					Head:            chain.blocks[chain.Len()-1].Hash(),
-					Genesis:         chain.blocks[0].Hash(),
-					ForkID:          chain.ForkID(),
-				}
+				status = c.defaultStatus(chain)
 			}
 			if err := c.Write(ethProto, eth.StatusMsg, status); err != nil {
 				return fmt.Errorf("write to connection failed: %v", err)
 			}
-		case eth.UpgradeStatusMsg + protoOffset(ethProto):
+		case eth.UpgradeStatusMsg + c.protoOffset(ethProto):
 			msg := new(eth.UpgradeStatusPacket)
 			if err := rlp.DecodeBytes(data, &msg); err != nil {
 				return fmt.Errorf("error decoding status packet: %w", err)
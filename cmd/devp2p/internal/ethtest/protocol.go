@@ -36,6 +36,9 @@ const (
 	snapProtoLen = 8
 )
 
+// Unexported bsc protocol lengths from eth/protocols/bsc, by version.
+var bscProtoLens = map[uint]uint64{1: 2, 2: 4, 3: 5, 4: 7}
+
 // Unexported handshake structure from p2p/peer.go.
 type protoHandshake struct {
 	Version    uint64
@@ -55,17 +58,20 @@ const (
 	baseProto Proto = iota
 	ethProto
 	snapProto
+	bscProto
 )
 
 // getProto returns the protocol a certain message code is associated with
-// (assuming the negotiated capabilities are exactly {eth,snap})
-func getProto(code uint64) Proto {
+// (assuming the negotiated capabilities are {eth,snap}, optionally with bsc)
+func (c *Conn) getProto(code uint64) Proto {
 	switch {
 	case code < baseProtoLen:
 		return baseProto
-	case code < baseProtoLen+ethProtoLen:
+	case code < c.protoOffset(ethProto):
+		return bscProto
+	case code < c.protoOffset(snapProto):
 		return ethProto
-	case code < baseProtoLen+ethProtoLen+snapProtoLen:
+	case code < c.protoOffset(snapProto)+snapProtoLen:
 		return snapProto
 	default:
 		panic("unhandled msg code beyond last protocol")
@@ -73,15 +79,18 @@ func getProto(code uint64) Proto {
 }
 
 // protoOffset will return the offset at which the specified protocol's messages
-// begin.
-func protoOffset(proto Proto) uint64 {
+// begin. The bsc protocol sorts before the others, shifting them if negotiated.
+func (c *Conn) protoOffset(proto Proto) uint64 {
+	bscLen := bscProtoLens[c.negotiatedBscProtoVersion]
 	switch proto {
 	case baseProto:
 		return 0
-	case ethProto:
+	case bscProto:
 		return baseProtoLen
+	case ethProto:
+		return baseProtoLen + bscLen
 	case snapProto:
-		return baseProtoLen + ethProtoLen
+		return baseProtoLen + bscLen + ethProtoLen
 	default:
 		panic("unhandled protocol")
 	}
@@ -0,0 +1,263 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/devp2p/internal/ethtest"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/rlpx"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	replayCommand = &cli.Command{
+		Name:      "replay",
+		Usage:     "Replays the messages received from a peer in a p2p message capture against a node",
+		ArgsUsage: "<capture> <node>",
+		Action:    replay,
+		Flags: []cli.Flag{
+			replayPeerFlag,
+			replaySpeedFlag,
+			replayLingerFlag,
+		},
+	}
+	replayPeerFlag = &cli.StringFlag{
+		Name:  "peer",
+		Usage: "ID (or ID prefix) of the captured peer to impersonate, defaults to the first captured peer",
+	}
+	replaySpeedFlag = &cli.Float64Flag{
+		Name:  "speed",
+		Usage: "Replay speed relative to the captured timing, 0 to send the messages without delay",
+		Value: 1,
+	}
+	replayLingerFlag = &cli.DurationFlag{
+		Name:  "linger",
+		Usage: "Time to wait for the responses of the node after the last message",
+		Value: 2 * time.Second,
+	}
+)
+
+const (
+	replayBaseProtocolLength = 16 // Number of message codes reserved for devp2p
+	replayDiscMsg            = 0x01
+	replayPingMsg            = 0x02
+	replayPongMsg            = 0x03
+)
+
+// replay connects to a node with the protocols of a captured session, and sends
+// it the messages received from the peer in that session.
+func replay(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return errors.New("need capture file and node as arguments")
+	}
+	records, err := p2p.ReadCapture(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	node, err := parseNode(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	session, msgs, err := replaySession(records, ctx.String(replayPeerFlag.Name))
+	if err != nil {
+		return err
+	}
+	log.Info("Replaying captured session", "peer", session.Peer, "started", session.Time, "messages", len(msgs))
+
+	conn, offsets, err := replayDial(node, session.Protocols)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Answer the pings of the node and count its messages in the background
+	var (
+		wlock    sync.Mutex
+		received atomic.Uint64
+		closed   = make(chan error, 1)
+	)
+	write := func(code uint64, data []byte) error {
+		wlock.Lock()
+		defer wlock.Unlock()
+		_, err := conn.Write(code, data)
+		return err
+	}
+	go func() {
+		for {
+			code, data, _, err := conn.Read()
+			if err != nil {
+				closed <- err
+				return
+			}
+			switch code {
+			case replayPingMsg:
+				pong, _ := rlp.EncodeToBytes([]uint{})
+				write(replayPongMsg, pong)
+			case replayDiscMsg:
+				var reason []p2p.DiscReason
+				rlp.DecodeBytes(data, &reason)
+				if len(reason) == 0 {
+					closed <- errors.New("disconnected by node")
+				} else {
+					closed <- fmt.Errorf("disconnected by node: %v", reason[0])
+				}
+				return
+			default:
+				received.Add(1)
+			}
+		}
+	}()
+	// Send the captured messages with their original spacing
+	var (
+		speed   = ctx.Float64(replaySpeedFlag.Name)
+		start   = time.Now()
+		sent    int
+		skipped int
+	)
+	for i, msg := range msgs {
+		if i > 0 && speed > 0 {
+			wait := time.Duration(float64(msg.Time.Sub(msgs[i-1].Time)) / speed)
+			select {
+			case <-time.After(wait):
+			case err := <-closed:
+				return fmt.Errorf("replay aborted after %d messages: %v", sent, err)
+			}
+		}
+		offset, ok := offsets[msg.Protocol]
+		if !ok {
+			skipped++
+			continue
+		}
+		if err := write(offset+msg.Code, msg.Payload); err != nil {
+			return fmt.Errorf("replay aborted after %d messages: %v", sent, err)
+		}
+		log.Debug("Replayed message", "proto", msg.Protocol, "code", msg.Code, "size", len(msg.Payload))
+		sent++
+	}
+	select {
+	case <-time.After(ctx.Duration(replayLingerFlag.Name)):
+		reason, _ := rlp.EncodeToBytes([]p2p.DiscReason{p2p.DiscRequested})
+		write(replayDiscMsg, reason)
+	case err = <-closed:
+	}
+	log.Info("Replay finished", "sent", sent, "skipped", skipped, "received", received.Load(), "elapsed", time.Since(start))
+	return err
+}
+
+// replaySession selects the session of a peer in a capture, returning its
+// start record and the messages received from the peer.
+func replaySession(records []*p2p.CaptureRecord, peer string) (*p2p.CaptureRecord, []*p2p.CaptureRecord, error) {
+	var session *p2p.CaptureRecord
+	for _, record := range records {
+		if len(record.Protocols) > 0 && strings.HasPrefix(record.Peer.String(), peer) {
+			session = record
+			break
+		}
+	}
+	if session == nil {
+		return nil, nil, errors.New("no captured session of the peer")
+	}
+	var msgs []*p2p.CaptureRecord
+	for _, record := range records[slices.Index(records, session)+1:] {
+		if record.Peer != session.Peer {
+			continue
+		}
+		if len(record.Protocols) > 0 {
+			break // next session
+		}
+		if record.Direction != p2p.CaptureInbound {
+			continue
+		}
+		if record.Payload == nil && record.Size > 0 {
+			return nil, nil, errors.New("capture has no payloads, record with --p2p.capture.payloads")
+		}
+		msgs = append(msgs, record)
+	}
+	return session, msgs, nil
+}
+
+// replayDial connects to the node, advertising the protocols of the captured
+// session. It returns the message code offsets of the protocols negotiated.
+func replayDial(node *enode.Node, protocols []p2p.CaptureProtocol) (*rlpx.Conn, map[string]uint64, error) {
+	tcpEndpoint, ok := node.TCPEndpoint()
+	if !ok {
+		return nil, nil, errors.New("node has no TCP endpoint")
+	}
+	fd, err := net.Dial("tcp", tcpEndpoint.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	conn := rlpx.NewConn(fd, node.Pubkey())
+	key, _ := crypto.GenerateKey()
+	if _, err := conn.Handshake(key); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	ours := &ethtest.Hello{Version: 5, Name: "devp2p-replay", ID: crypto.FromECDSAPub(&key.PublicKey)[1:]}
+	for _, proto := range protocols {
+		ours.Caps = append(ours.Caps, p2p.Cap{Name: proto.Name, Version: proto.Version})
+	}
+	hello, _ := rlp.EncodeToBytes(ours)
+	if _, err := conn.Write(0, hello); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	code, data, _, err := conn.Read()
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if code != 0 {
+		conn.Close()
+		return nil, nil, fmt.Errorf("bad handshake: got msg code %d", code)
+	}
+	var theirs ethtest.Hello
+	if err := rlp.DecodeBytes(data, &theirs); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("invalid handshake: %v", err)
+	}
+	if theirs.Version >= 5 {
+		conn.SetSnappy(true)
+	}
+	// Lay out the shared protocols as the node does, ordered by name
+	shared := slices.DeleteFunc(slices.Clone(protocols), func(proto p2p.CaptureProtocol) bool {
+		return !slices.Contains(theirs.Caps, p2p.Cap{Name: proto.Name, Version: proto.Version})
+	})
+	slices.SortFunc(shared, func(a, b p2p.CaptureProtocol) int { return strings.Compare(a.Name, b.Name) })
+
+	offsets := make(map[string]uint64)
+	offset := uint64(replayBaseProtocolLength)
+	for _, proto := range shared {
+		offsets[p2p.Cap{Name: proto.Name, Version: proto.Version}.String()] = offset
+		offset += proto.Length
+	}
+	log.Info("Connected to node", "name", theirs.Name, "protocols", len(shared))
+	return conn, offsets, nil
+}
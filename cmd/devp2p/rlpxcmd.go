@@ -38,6 +38,7 @@ var (
 			rlpxPingCommand,
 			rlpxEthTestCommand,
 			rlpxSnapTestCommand,
+			rlpxBscTestCommand,
 		},
 	}
 	rlpxPingCommand = &cli.Command{
@@ -73,6 +74,20 @@ var (
 			testNodeEngineFlag,
 		},
 	}
+	rlpxBscTestCommand = &cli.Command{
+		Name:      "bsc-test",
+		Usage:     "Runs bsc protocol tests against a node",
+		ArgsUsage: "",
+		Action:    rlpxBscTest,
+		Flags: []cli.Flag{
+			testPatternFlag,
+			testTAPFlag,
+			testChainDirFlag,
+			testNodeFlag,
+			testNodeJWTFlag,
+			testNodeEngineFlag,
+		},
+	}
 )
 
 func rlpxPing(ctx *cli.Context) error {
@@ -134,6 +149,16 @@ func rlpxSnapTest(ctx *cli.Context) error {
 	return runTests(ctx, suite.SnapTests())
 }
 
+// rlpxBscTest runs the bsc protocol test suite.
+func rlpxBscTest(ctx *cli.Context) error {
+	p := cliTestParams(ctx)
+	suite, err := ethtest.NewSuite(p.node, p.chainDir, p.engineAPI, p.jwt)
+	if err != nil {
+		exit(err)
+	}
+	return runTests(ctx, suite.BscTests())
+}
+
 type testParams struct {
 	node      *enode.Node
 	engineAPI string
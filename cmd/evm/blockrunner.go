@@ -25,6 +25,7 @@ import (
 	"regexp"
 	"slices"
 
+	"github.com/ethereum/go-ethereum/cmd/evm/internal/t8ntool"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/tests"
@@ -41,6 +42,7 @@ var blockTestCommand = &cli.Command{
 		HumanReadableFlag,
 		RunFlag,
 		WitnessCrossCheckFlag,
+		t8ntool.ParliaFlag,
 	}, traceFlags),
 }
 
@@ -69,8 +71,8 @@ func runBlockTest(ctx *cli.Context, fname string) ([]testResult, error) {
 	if err != nil {
 		return nil, err
 	}
-	var tests map[string]*tests.BlockTest
-	if err = json.Unmarshal(src, &tests); err != nil {
+	var blockTests map[string]*tests.BlockTest
+	if err = json.Unmarshal(src, &blockTests); err != nil {
 		return nil, err
 	}
 	re, err := regexp.Compile(ctx.String(RunFlag.Name))
@@ -80,7 +82,7 @@ func runBlockTest(ctx *cli.Context, fname string) ([]testResult, error) {
 	tracer := tracerFromFlags(ctx)
 
 	// Pull out keys to sort and ensure tests are run in order.
-	keys := slices.Sorted(maps.Keys(tests))
+	keys := slices.Sorted(maps.Keys(blockTests))
 
 	// Run all the tests.
 	var results []testResult
@@ -89,7 +91,10 @@ func runBlockTest(ctx *cli.Context, fname string) ([]testResult, error) {
 			continue
 		}
 		result := &testResult{Name: name, Pass: true}
-		if err := tests[name].Run(false, rawdb.PathScheme, ctx.Bool(WitnessCrossCheckFlag.Name), tracer, func(res error, chain *core.BlockChain) {
+		if ctx.Bool(t8ntool.ParliaFlag.Name) {
+			blockTests[name].SetSealEngine(tests.ParliaSealEngine)
+		}
+		if err := blockTests[name].Run(false, rawdb.PathScheme, ctx.Bool(WitnessCrossCheckFlag.Name), tracer, func(res error, chain *core.BlockChain) {
 			if ctx.Bool(DumpFlag.Name) {
 				if s, _ := chain.State(); s != nil {
 					result.State = dump(s)
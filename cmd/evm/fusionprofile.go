@@ -0,0 +1,226 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/evm/internal/fusionprofile"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	FusionFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block of the range to replay from the datadir",
+	}
+	FusionToFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block of the range to replay from the datadir (defaults to --from)",
+	}
+	FusionStateCodeFlag = &cli.BoolFlag{
+		Name:  "state",
+		Usage: "Statically profile all contract code stored in the datadir",
+	}
+	FusionMinLenFlag = &cli.IntFlag{
+		Name:  "ngram.min",
+		Usage: "Minimum length of the opcode sequences to collect",
+		Value: 2,
+	}
+	FusionMaxLenFlag = &cli.IntFlag{
+		Name:  "ngram.max",
+		Usage: "Maximum length of the opcode sequences to collect",
+		Value: 6,
+	}
+	FusionTopFlag = &cli.IntFlag{
+		Name:  "top",
+		Usage: "Number of candidate sequences to report (0 = all)",
+		Value: 50,
+	}
+	FusionDispatchFlag = &cli.DurationFlag{
+		Name:  "dispatch",
+		Usage: "Estimated interpreter cost of a single instruction dispatch",
+		Value: 3 * time.Nanosecond,
+	}
+	FusionOutputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "Write the report as JSON into the given file instead of printing a table",
+	}
+)
+
+var fusionProfileCommand = &cli.Command{
+	Action:    fusionProfileCmd,
+	Name:      "fusion-profile",
+	Usage:     "Ranks opcode sequences by how much fusing them would save",
+	ArgsUsage: "[<bytecode file>...]",
+	Description: `
+The fusion-profile command collects opcode n-gram statistics and ranks the
+sequences by the number of interpreter dispatches a super-instruction would
+save, flagging the ones the opcode optimizer already fuses.
+
+Sequences are collected either dynamically, by replaying the --from/--to block
+range of the chain in --datadir, or statically from a bytecode corpus: the hex
+encoded files given as arguments, or all contract code in --datadir (--state).`,
+	Flags: []cli.Flag{
+		FusionFromFlag,
+		FusionToFlag,
+		FusionStateCodeFlag,
+		FusionMinLenFlag,
+		FusionMaxLenFlag,
+		FusionTopFlag,
+		FusionDispatchFlag,
+		FusionOutputFlag,
+		utils.DataDirFlag,
+		utils.GCModeFlag,
+		utils.StateSchemeFlag,
+	},
+}
+
+func fusionProfileCmd(ctx *cli.Context) error {
+	profiler := fusionprofile.New(ctx.Int(FusionMinLenFlag.Name), ctx.Int(FusionMaxLenFlag.Name))
+
+	switch {
+	case ctx.Args().Present():
+		for _, path := range ctx.Args().Slice() {
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			code := common.FromHex(string(bytes.TrimSpace(src)))
+			if len(code) == 0 {
+				return fmt.Errorf("%s: no bytecode", path)
+			}
+			profiler.AddCode(code)
+		}
+
+	case ctx.IsSet(FusionFromFlag.Name), ctx.Bool(FusionStateCodeFlag.Name):
+		stack, err := node.New(&node.Config{Name: "geth", DataDir: ctx.String(utils.DataDirFlag.Name)})
+		if err != nil {
+			return err
+		}
+		defer stack.Close()
+
+		chain, db := utils.MakeChain(ctx, stack, true)
+		defer chain.Stop()
+
+		if ctx.Bool(FusionStateCodeFlag.Name) {
+			profileStateCode(db, profiler)
+		} else if err := profileBlocks(ctx, chain, profiler); err != nil {
+			return err
+		}
+
+	default:
+		return errors.New("bytecode files, a block range or --state required")
+	}
+	report := profiler.Report(ctx.Int(FusionTopFlag.Name), ctx.Duration(FusionDispatchFlag.Name))
+	if path := ctx.String(FusionOutputFlag.Name); path != "" {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, out, 0644)
+	}
+	printFusionReport(report)
+	return nil
+}
+
+// profileBlocks replays the configured block range with the profiler attached.
+func profileBlocks(ctx *cli.Context, chain *core.BlockChain, profiler *fusionprofile.Profiler) error {
+	from := ctx.Uint64(FusionFromFlag.Name)
+	to := from
+	if ctx.IsSet(FusionToFlag.Name) {
+		to = ctx.Uint64(FusionToFlag.Name)
+	}
+	if from == 0 || to < from {
+		return fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	var (
+		config = vm.Config{Tracer: profiler.Hooks()}
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for number := from; number <= to; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return fmt.Errorf("block %d not found", number)
+		}
+		parent := chain.GetHeader(block.ParentHash(), number-1)
+		if parent == nil {
+			return fmt.Errorf("parent of block %d not found", number)
+		}
+		statedb, err := chain.StateAt(parent.Root)
+		if err != nil {
+			return fmt.Errorf("state of block %d unavailable: %v", number-1, err)
+		}
+		if _, err := chain.Processor().Process(block, statedb, config); err != nil {
+			return fmt.Errorf("failed to replay block %d: %v", number, err)
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Profiling blocks", "number", number, "remaining", to-number, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	log.Info("Profiled blocks", "from", from, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// profileStateCode statically profiles all contract code in the database.
+func profileStateCode(db ethdb.Database, profiler *fusionprofile.Profiler) {
+	it := db.NewIterator(rawdb.CodePrefix, nil)
+	defer it.Release()
+
+	var contracts int
+	for it.Next() {
+		if len(it.Key()) != len(rawdb.CodePrefix)+common.HashLength {
+			continue
+		}
+		profiler.AddCode(it.Value())
+		contracts++
+	}
+	log.Info("Profiled contract code", "contracts", contracts)
+}
+
+// printFusionReport prints the report as a human readable table.
+func printFusionReport(report *fusionprofile.Report) {
+	fmt.Printf("instructions: %d, distinct sequences: %d\n\n", report.Instructions, report.Sequences)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQUENCE\tCOUNT\tGAS\tDISPATCHES SAVED\tEST. TIME SAVED\tFUSED")
+	for _, c := range report.Candidates {
+		fused := ""
+		if c.Fused {
+			fused = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%v\t%s\n", strings.Join(c.Sequence, " "), c.Count, c.Gas, c.Dispatches, c.Saved, fused)
+	}
+	w.Flush()
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// Package fusionprofile collects opcode n-gram statistics from executed and
+// static EVM bytecode, ranking the sequences that would pay off the most if
+// fused into a super-instruction by the opcode optimizer.
+package fusionprofile
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Profiler counts contiguous opcode sequences of a configurable length range.
+//
+// A sequence only counts if it could be fused statically: its instructions must
+// be adjacent in the bytecode and no instruction but the first may be a jump
+// destination, since a jump into the middle of a fused sequence is impossible.
+type Profiler struct {
+	minLen int
+	maxLen int
+
+	grams  map[string]*gram
+	frames []*window // Sliding windows per call depth
+	ops    uint64    // Total number of instructions observed
+	lock   sync.Mutex
+}
+
+// gram is the aggregated statistics of a single opcode sequence.
+type gram struct {
+	count uint64
+	gas   uint64
+}
+
+// window is the sliding window of the most recently executed instructions
+// within a single call frame.
+type window struct {
+	addr common.Address
+	next uint64 // Program counter of the instruction following the window
+	ops  []vm.OpCode
+	gas  []uint64
+}
+
+// New creates a profiler collecting sequences of minLen to maxLen opcodes.
+func New(minLen, maxLen int) *Profiler {
+	return &Profiler{
+		minLen: max(minLen, 2),
+		maxLen: max(maxLen, minLen, 2),
+		grams:  make(map[string]*gram),
+	}
+}
+
+// Hooks returns the tracing hooks collecting the dynamic sequence frequencies
+// of executed code.
+func (p *Profiler) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnOpcode: p.onOpcode,
+	}
+}
+
+func (p *Profiler) onOpcode(pc uint64, opcode byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for len(p.frames) <= depth {
+		p.frames = append(p.frames, new(window))
+	}
+	w, op := p.frames[depth], vm.OpCode(opcode)
+
+	// Restart the window if control flow jumped or a different contract runs
+	// at this depth, the sequence is not contiguous in any code then.
+	if addr := scope.Address(); w.addr != addr || w.next != pc || op == vm.JUMPDEST {
+		w.reset(addr)
+	}
+	w.next = pc + instructionSize(op)
+	p.push(w, op, cost)
+}
+
+// AddCode statically counts all sequences in the given bytecode, counting each
+// occurrence once.
+func (p *Profiler) AddCode(code []byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	w := new(window)
+	for pc := uint64(0); pc < uint64(len(code)); {
+		op := vm.OpCode(code[pc])
+		if op == vm.JUMPDEST {
+			w.reset(common.Address{})
+		}
+		p.push(w, op, 0)
+
+		// Nothing can be fused across an instruction halting the control flow
+		switch op {
+		case vm.JUMP, vm.STOP, vm.RETURN, vm.REVERT, vm.INVALID, vm.SELFDESTRUCT:
+			w.reset(common.Address{})
+		}
+		pc += instructionSize(op)
+	}
+}
+
+// push appends an instruction to the window and counts all sequences ending at
+// the instruction.
+//
+// Note, this method assumes the profiler lock is held!
+func (p *Profiler) push(w *window, op vm.OpCode, cost uint64) {
+	p.ops++
+	if len(w.ops) == p.maxLen {
+		w.ops, w.gas = w.ops[1:], w.gas[1:]
+	}
+	w.ops, w.gas = append(w.ops, op), append(w.gas, cost)
+
+	var gas uint64
+	for n := 1; n <= len(w.ops); n++ {
+		gas += w.gas[len(w.gas)-n]
+		if n < p.minLen {
+			continue
+		}
+		key := opsKey(w.ops[len(w.ops)-n:])
+		g, ok := p.grams[key]
+		if !ok {
+			g = new(gram)
+			p.grams[key] = g
+		}
+		g.count++
+		g.gas += gas
+	}
+}
+
+// reset empties the window, starting a new one in the given contract.
+func (w *window) reset(addr common.Address) {
+	w.addr, w.next = addr, 0
+	w.ops, w.gas = w.ops[:0], w.gas[:0]
+}
+
+// Candidate is a ranked opcode sequence.
+type Candidate struct {
+	Sequence   []string      `json:"sequence"`
+	Count      uint64        `json:"count"`      // Number of times the sequence was observed
+	Gas        uint64        `json:"gas"`        // Gas charged by all observed instances of the sequence
+	Dispatches uint64        `json:"dispatches"` // Interpreter dispatches saved by fusing the sequence
+	Saved      time.Duration `json:"saved"`      // Estimated execution time saved by fusing the sequence
+	Fused      bool          `json:"fused"`      // Whether the sequence is already a super-instruction
+}
+
+// Report is the outcome of a profiling run.
+type Report struct {
+	Instructions uint64       `json:"instructions"` // Total number of instructions observed
+	Sequences    int          `json:"sequences"`    // Number of distinct sequences observed
+	Candidates   []*Candidate `json:"candidates"`
+}
+
+// Report ranks the collected sequences by the number of interpreter dispatches
+// fusing them would save, returning at most limit candidates. Fusion does not
+// change the gas charged, so the savings are estimated from the given cost of
+// a single dispatch.
+func (p *Profiler) Report(limit int, dispatch time.Duration) *Report {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	fused := superInstructions()
+	candidates := make([]*Candidate, 0, len(p.grams))
+	for key, g := range p.grams {
+		var (
+			seq        = make([]string, len(key))
+			dispatches = uint64(len(key)-1) * g.count
+		)
+		for i := range key {
+			seq[i] = vm.OpCode(key[i]).String()
+		}
+		candidates = append(candidates, &Candidate{
+			Sequence:   seq,
+			Count:      g.count,
+			Gas:        g.gas,
+			Dispatches: dispatches,
+			Saved:      time.Duration(dispatches) * dispatch,
+			Fused:      fused[key],
+		})
+	}
+	slices.SortFunc(candidates, func(a, b *Candidate) int {
+		if a.Dispatches != b.Dispatches {
+			if a.Dispatches > b.Dispatches {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(strings.Join(a.Sequence, ","), strings.Join(b.Sequence, ","))
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return &Report{
+		Instructions: p.ops,
+		Sequences:    len(p.grams),
+		Candidates:   candidates,
+	}
+}
+
+// superInstructions returns the set of sequences already fused by the opcode
+// optimizer, keyed the same way as the collected sequences.
+func superInstructions() map[string]bool {
+	fused := make(map[string]bool)
+	for op := 0; op < 256; op++ {
+		if seq, ok := vm.DecomposeSuperInstruction(vm.OpCode(op)); ok {
+			fused[opsKey(seq)] = true
+		}
+	}
+	return fused
+}
+
+// opsKey encodes an opcode sequence into a map key.
+func opsKey(ops []vm.OpCode) string {
+	key := make([]byte, len(ops))
+	for i, op := range ops {
+		key[i] = byte(op)
+	}
+	return string(key)
+}
+
+// instructionSize returns the size of the instruction in the bytecode,
+// including any immediate.
+func instructionSize(op vm.OpCode) uint64 {
+	if op.IsPush() {
+		return uint64(op-vm.PUSH0) + 1
+	}
+	return 1
+}
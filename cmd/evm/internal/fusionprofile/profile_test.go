@@ -0,0 +1,119 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package fusionprofile
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+)
+
+// counts flattens a report into a sequence -> count map.
+func counts(report *Report) map[string]uint64 {
+	out := make(map[string]uint64)
+	for _, c := range report.Candidates {
+		out[strings.Join(c.Sequence, " ")] = c.Count
+	}
+	return out
+}
+
+// Tests that static profiling never counts sequences across jump destinations
+// or instructions halting the control flow.
+func TestStaticProfile(t *testing.T) {
+	code := []byte{
+		byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x02, byte(vm.ADD),
+		byte(vm.JUMPDEST), byte(vm.PUSH1), 0x00, byte(vm.ADD), byte(vm.STOP),
+		byte(vm.PUSH1), 0x00,
+	}
+	p := New(2, 3)
+	p.AddCode(code)
+
+	report := p.Report(0, time.Nanosecond)
+	have := counts(report)
+	want := map[string]uint64{
+		"PUSH1 PUSH1":        1,
+		"PUSH1 ADD":          2,
+		"PUSH1 PUSH1 ADD":    1,
+		"JUMPDEST PUSH1":     1,
+		"JUMPDEST PUSH1 ADD": 1,
+		"ADD STOP":           1,
+		"PUSH1 ADD STOP":     1,
+	}
+	if len(have) != len(want) {
+		t.Fatalf("sequence count mismatch: have %v, want %v", have, want)
+	}
+	for seq, n := range want {
+		if have[seq] != n {
+			t.Errorf("sequence %q: count mismatch: have %d, want %d", seq, have[seq], n)
+		}
+	}
+	if report.Instructions != 8 {
+		t.Errorf("instruction count mismatch: have %d, want 8", report.Instructions)
+	}
+	// Longer sequences save more dispatches and must be ranked first
+	if top := report.Candidates[0]; len(top.Sequence) != 3 || top.Dispatches != 2 || top.Saved != 2*time.Nanosecond {
+		t.Errorf("top candidate mismatch: have %+v", top)
+	}
+}
+
+// Tests that dynamic profiling follows the executed control flow and flags
+// already fused sequences.
+func TestDynamicProfile(t *testing.T) {
+	// Loop three times: PUSH1 3 JUMPDEST PUSH1 1 SWAP1 SUB DUP1 PUSH2 2 JUMPI STOP
+	code := []byte{
+		byte(vm.PUSH1), 0x03,
+		byte(vm.JUMPDEST),
+		byte(vm.PUSH1), 0x01, byte(vm.SWAP1), byte(vm.SUB),
+		byte(vm.DUP1), byte(vm.PUSH2), 0x00, 0x02, byte(vm.JUMPI),
+		byte(vm.STOP),
+	}
+	p := New(2, 2)
+	if _, _, err := runtime.Execute(code, nil, &runtime.Config{
+		EVMConfig: vm.Config{Tracer: p.Hooks()},
+		Origin:    common.HexToAddress("0xaa"),
+	}); err != nil {
+		t.Fatalf("failed to execute code: %v", err)
+	}
+	report := p.Report(0, time.Nanosecond)
+	have := counts(report)
+	want := map[string]uint64{
+		"JUMPDEST PUSH1": 3,
+		"PUSH1 SWAP1":    3,
+		"SWAP1 SUB":      3,
+		"SUB DUP1":       3,
+		"DUP1 PUSH2":     3,
+		"PUSH2 JUMPI":    3,
+		"JUMPI STOP":     1,
+	}
+	if len(have) != len(want) {
+		t.Fatalf("sequence count mismatch: have %v, want %v", have, want)
+	}
+	for seq, n := range want {
+		if have[seq] != n {
+			t.Errorf("sequence %q: count mismatch: have %d, want %d", seq, have[seq], n)
+		}
+	}
+	for _, c := range report.Candidates {
+		if want := strings.Join(c.Sequence, " ") == "PUSH2 JUMPI"; c.Fused != want {
+			t.Errorf("sequence %v: fused flag mismatch: have %v, want %v", c.Sequence, c.Fused, want)
+		}
+	}
+}
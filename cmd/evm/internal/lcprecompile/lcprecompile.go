@@ -0,0 +1,288 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// Package lcprecompile records the invocations of the BSC light client
+// precompiles while replaying chain blocks, and verifies that the precompile
+// implementations still reproduce the recorded outputs and gas usage.
+package lcprecompile
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Addresses are the light client precompiles: the tendermint header
+// validation, the iavl merkle proof validation and the cometBFT light block
+// validation.
+var Addresses = []common.Address{
+	common.BytesToAddress([]byte{0x64}),
+	common.BytesToAddress([]byte{0x65}),
+	common.BytesToAddress([]byte{0x67}),
+}
+
+// forkPrecompiles are the precompile sets of the forks which introduced a
+// light client precompile version, in activation order.
+var forkPrecompiles = []vm.PrecompiledContracts{
+	vm.PrecompiledContractsIstanbul,
+	vm.PrecompiledContractsNano,
+	vm.PrecompiledContractsMoran,
+	vm.PrecompiledContractsPlanck,
+	vm.PrecompiledContractsLuban,
+	vm.PrecompiledContractsPlato,
+	vm.PrecompiledContractsHertz,
+	vm.PrecompiledContractsFeynman,
+	vm.PrecompiledContractsCancun,
+	vm.PrecompiledContractsHaber,
+	vm.PrecompiledContractsPrague,
+	vm.PrecompiledContractsOsaka,
+	vm.PrecompiledContractsPasteur,
+}
+
+// Versions returns every implementation the given precompile had over the
+// forks, in activation order.
+func Versions(addr common.Address) []vm.PrecompiledContract {
+	var (
+		versions []vm.PrecompiledContract
+		seen     = make(map[string]bool)
+	)
+	for _, set := range forkPrecompiles {
+		p, ok := set[addr]
+		if !ok || seen[p.Name()] {
+			continue
+		}
+		seen[p.Name()] = true
+		versions = append(versions, p)
+	}
+	return versions
+}
+
+// Call is a recorded invocation of a light client precompile.
+type Call struct {
+	Block   uint64         `json:"block"`
+	Time    uint64         `json:"time"`
+	Address common.Address `json:"address"`
+	Input   hexutil.Bytes  `json:"input"`
+	Gas     uint64         `json:"gas"`     // Gas supplied to the call
+	GasUsed uint64         `json:"gasUsed"` // Gas consumed by the call
+	Output  hexutil.Bytes  `json:"output"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// Corpus is a set of recorded calls along with the chain config they were
+// recorded with.
+type Corpus struct {
+	Config *params.ChainConfig `json:"config"`
+	Calls  []*Call             `json:"calls"`
+}
+
+// Recorder collects the light client precompile calls of replayed blocks.
+type Recorder struct {
+	block   uint64
+	time    uint64
+	pending []*Call // Calls entered per call depth, nil if not recorded
+	calls   []*Call
+	lock    sync.Mutex
+}
+
+// NewRecorder creates an empty recorder.
+func NewRecorder() *Recorder {
+	return new(Recorder)
+}
+
+// Hooks returns the tracing hooks recording the calls.
+func (r *Recorder) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnBlockStart: r.onBlockStart,
+		OnEnter:      r.onEnter,
+		OnExit:       r.onExit,
+	}
+}
+
+func (r *Recorder) onBlockStart(ev tracing.BlockEvent) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.block, r.time = ev.Block.NumberU64(), ev.Block.Time()
+	r.pending = r.pending[:0]
+}
+
+func (r *Recorder) onEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var call *Call
+	if op := vm.OpCode(typ); op != vm.CREATE && op != vm.CREATE2 && op != vm.SELFDESTRUCT && slices.Contains(Addresses, to) {
+		call = &Call{Block: r.block, Time: r.time, Address: to, Input: bytes.Clone(input), Gas: gas}
+	}
+	r.pending = append(r.pending, call)
+}
+
+func (r *Recorder) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.pending) == 0 {
+		return
+	}
+	call := r.pending[len(r.pending)-1]
+	r.pending = r.pending[:len(r.pending)-1]
+	if call == nil {
+		return
+	}
+	call.GasUsed, call.Output = gasUsed, bytes.Clone(output)
+	if err != nil {
+		call.Error = err.Error()
+	}
+	r.calls = append(r.calls, call)
+}
+
+// Calls returns the recorded calls.
+func (r *Recorder) Calls() []*Call {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return slices.Clone(r.calls)
+}
+
+// CheckReceipts verifies that the receipts of a replayed block match the ones
+// stored when the block was imported. Calls recorded from a replay diverging
+// from the chain history do not reflect the historical precompile results.
+func CheckReceipts(number uint64, replayed, stored types.Receipts) error {
+	if len(replayed) != len(stored) {
+		return fmt.Errorf("block %d: replayed %d receipts, stored %d", number, len(replayed), len(stored))
+	}
+	for i, have := range replayed {
+		want := stored[i]
+		if have.GasUsed != want.GasUsed || have.Status != want.Status {
+			return fmt.Errorf("block %d, tx %d (%x): replay diverges from the stored receipt: gas used %d, want %d; status %d, want %d",
+				number, i, want.TxHash, have.GasUsed, want.GasUsed, have.Status, want.Status)
+		}
+	}
+	return nil
+}
+
+// Result is the outcome of running a recorded call through a precompile.
+type Result struct {
+	Output  hexutil.Bytes `json:"output"`
+	GasUsed uint64        `json:"gasUsed"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// Run executes a call through the given precompile version, charging gas the
+// way the EVM does: a failing precompile consumes all supplied gas.
+func Run(p vm.PrecompiledContract, call *Call) *Result {
+	output, remaining, err := vm.RunPrecompiledContract(p, call.Input, call.Gas, nil)
+	if err != nil {
+		return &Result{GasUsed: call.Gas, Error: err.Error()}
+	}
+	return &Result{Output: output, GasUsed: call.Gas - remaining}
+}
+
+// matches reports whether the result reproduces the recorded call.
+func (res *Result) matches(call *Call) bool {
+	return bytes.Equal(res.Output, call.Output) && res.GasUsed == call.GasUsed && res.Error == call.Error
+}
+
+// Mismatch is a recorded call the precompile version active at its height no
+// longer reproduces.
+type Mismatch struct {
+	Call    *Call   `json:"call"`
+	Version string  `json:"version"`
+	Result  *Result `json:"result"`
+}
+
+// VersionStats counts how many recorded calls a precompile version reproduces.
+type VersionStats struct {
+	Address common.Address `json:"address"`
+	Version string         `json:"version"`
+	Active  int            `json:"active"`  // Calls recorded while the version was active
+	Matched int            `json:"matched"` // Calls, active or not, reproduced by the version
+	Total   int            `json:"total"`   // Calls run through the version
+}
+
+// Report is the outcome of verifying a corpus.
+type Report struct {
+	Calls      int             `json:"calls"`
+	Mismatches []*Mismatch     `json:"mismatches"`
+	Versions   []*VersionStats `json:"versions"`
+}
+
+// Verify runs every recorded call through the precompile version active at
+// the height it was recorded at, reporting the calls it doesn't reproduce.
+// If allVersions is set, the calls are also run through every other version
+// of the precompile to show which versions agree with each other.
+func Verify(corpus *Corpus, allVersions bool) *Report {
+	var (
+		report = &Report{Calls: len(corpus.Calls), Mismatches: []*Mismatch{}}
+		stats  = make(map[common.Address]map[string]*VersionStats)
+	)
+	stat := func(addr common.Address, version string) *VersionStats {
+		if stats[addr] == nil {
+			stats[addr] = make(map[string]*VersionStats)
+		}
+		if stats[addr][version] == nil {
+			stats[addr][version] = &VersionStats{Address: addr, Version: version}
+		}
+		return stats[addr][version]
+	}
+	for _, call := range corpus.Calls {
+		rules := corpus.Config.Rules(new(big.Int).SetUint64(call.Block), true, call.Time)
+		active, ok := vm.ActivePrecompiledContracts(rules)[call.Address]
+		if !ok {
+			report.Mismatches = append(report.Mismatches, &Mismatch{Call: call, Result: &Result{Error: "precompile not active"}})
+			continue
+		}
+		versions := []vm.PrecompiledContract{active}
+		if allVersions {
+			versions = Versions(call.Address)
+		}
+		for _, p := range versions {
+			var (
+				res = Run(p, call)
+				s   = stat(call.Address, p.Name())
+			)
+			s.Total++
+			if res.matches(call) {
+				s.Matched++
+			}
+			if p.Name() != active.Name() {
+				continue
+			}
+			s.Active++
+			if !res.matches(call) {
+				report.Mismatches = append(report.Mismatches, &Mismatch{Call: call, Version: p.Name(), Result: res})
+			}
+		}
+	}
+	for _, addr := range Addresses {
+		for _, p := range Versions(addr) {
+			if s := stats[addr][p.Name()]; s != nil {
+				report.Versions = append(report.Versions, s)
+			}
+		}
+	}
+	return report
+}
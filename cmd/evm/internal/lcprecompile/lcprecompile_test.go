@@ -0,0 +1,138 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package lcprecompile
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the recorder only collects the calls into the light client
+// precompiles, along with the height they were made at.
+func TestRecorder(t *testing.T) {
+	var (
+		r      = NewRecorder()
+		hooks  = r.Hooks()
+		caller = common.HexToAddress("0xc0ffee")
+		header = &types.Header{Number: big.NewInt(42), Time: 1000}
+	)
+	hooks.OnBlockStart(tracing.BlockEvent{Block: types.NewBlockWithHeader(header)})
+
+	hooks.OnEnter(0, byte(vm.CALL), caller, common.HexToAddress("0xdead"), nil, 100000, big.NewInt(0))
+	hooks.OnEnter(1, byte(vm.STATICCALL), caller, Addresses[1], []byte{0x01, 0x02}, 3000, nil)
+	hooks.OnExit(1, []byte{0x01}, 3000, nil, false)
+	hooks.OnEnter(1, byte(vm.CALL), caller, common.HexToAddress("0x02"), []byte{0x03}, 3000, big.NewInt(0))
+	hooks.OnExit(1, nil, 72, nil, false)
+	hooks.OnEnter(1, byte(vm.STATICCALL), caller, Addresses[2], []byte{0x04}, 5000, nil)
+	hooks.OnExit(1, nil, 5000, errors.New("invalid input"), true)
+	hooks.OnExit(0, nil, 20000, nil, false)
+
+	calls := r.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("call count mismatch: have %d, want 2", len(calls))
+	}
+	want := []Call{
+		{Block: 42, Time: 1000, Address: Addresses[1], Input: []byte{0x01, 0x02}, Gas: 3000, GasUsed: 3000, Output: []byte{0x01}},
+		{Block: 42, Time: 1000, Address: Addresses[2], Input: []byte{0x04}, Gas: 5000, GasUsed: 5000, Error: "invalid input"},
+	}
+	for i, call := range calls {
+		if call.Block != want[i].Block || call.Time != want[i].Time || call.Address != want[i].Address || call.Gas != want[i].Gas ||
+			call.GasUsed != want[i].GasUsed || string(call.Input) != string(want[i].Input) || string(call.Output) != string(want[i].Output) || call.Error != want[i].Error {
+			t.Errorf("call %d mismatch: have %+v, want %+v", i, call, want[i])
+		}
+	}
+}
+
+// Tests that verification accepts calls reproduced by the active precompile
+// version and reports the ones which are not.
+func TestVerify(t *testing.T) {
+	var (
+		config = params.BSCChainConfig
+		block  = uint64(60_000_000)
+		time   = uint64(2_000_000_000)
+		rules  = config.Rules(new(big.Int).SetUint64(block), true, time)
+		active = vm.ActivePrecompiledContracts(rules)
+	)
+	// Record the calls with the active precompiles themselves
+	var calls []*Call
+	for _, addr := range Addresses {
+		call := &Call{Block: block, Time: time, Address: addr, Input: []byte{0x00, 0x01, 0x02}, Gas: 1_000_000}
+		res := Run(active[addr], call)
+		call.Output, call.GasUsed, call.Error = res.Output, res.GasUsed, res.Error
+		calls = append(calls, call)
+	}
+	corpus := &Corpus{Config: config, Calls: calls}
+
+	report := Verify(corpus, true)
+	if len(report.Mismatches) != 0 {
+		t.Fatalf("unexpected mismatches: %+v", report.Mismatches)
+	}
+	var versions int
+	for _, addr := range Addresses {
+		versions += len(Versions(addr))
+	}
+	if len(report.Versions) != versions {
+		t.Errorf("version count mismatch: have %d, want %d", len(report.Versions), versions)
+	}
+	for _, s := range report.Versions {
+		if s.Total != 1 {
+			t.Errorf("%s: total mismatch: have %d, want 1", s.Version, s.Total)
+		}
+		if want := active[s.Address].Name() == s.Version; want != (s.Active == 1) {
+			t.Errorf("%s: active mismatch: have %d", s.Version, s.Active)
+		}
+	}
+	// Tamper with a recorded call and ensure it's caught
+	calls[1].GasUsed--
+	report = Verify(corpus, false)
+	if len(report.Mismatches) != 1 || report.Mismatches[0].Call != calls[1] {
+		t.Fatalf("mismatch not reported: %+v", report.Mismatches)
+	}
+	if report.Mismatches[0].Version != active[Addresses[1]].Name() {
+		t.Errorf("version mismatch: have %s, want %s", report.Mismatches[0].Version, active[Addresses[1]].Name())
+	}
+}
+
+// Tests that replayed receipts diverging from the stored ones are rejected.
+func TestCheckReceipts(t *testing.T) {
+	stored := types.Receipts{
+		{Status: types.ReceiptStatusSuccessful, GasUsed: 21000},
+		{Status: types.ReceiptStatusFailed, GasUsed: 50000},
+	}
+	replay := func(status, gasUsed uint64) types.Receipts {
+		return types.Receipts{stored[0], {Status: status, GasUsed: gasUsed}}
+	}
+	if err := CheckReceipts(1, replay(types.ReceiptStatusFailed, 50000), stored); err != nil {
+		t.Errorf("matching receipts rejected: %v", err)
+	}
+	if err := CheckReceipts(1, replay(types.ReceiptStatusSuccessful, 50000), stored); err == nil {
+		t.Error("status mismatch accepted")
+	}
+	if err := CheckReceipts(1, replay(types.ReceiptStatusFailed, 49000), stored); err == nil {
+		t.Error("gas mismatch accepted")
+	}
+	if err := CheckReceipts(1, stored[:1], stored); err == nil {
+		t.Error("receipt count mismatch accepted")
+	}
+}
@@ -26,9 +26,11 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -100,6 +102,7 @@ type stEnv struct {
 	ParentExcessBlobGas   *uint64                             `json:"parentExcessBlobGas,omitempty"`
 	ParentBlobGasUsed     *uint64                             `json:"parentBlobGasUsed,omitempty"`
 	ParentBeaconBlockRoot *common.Hash                        `json:"parentBeaconBlockRoot"`
+	SpoiledValidator      *common.Address                     `json:"spoiledValidator,omitempty"`
 }
 
 type stEnvMarshaling struct {
@@ -125,8 +128,11 @@ type rejectedTx struct {
 	Err   string `json:"error"`
 }
 
-// Apply applies a set of transactions to a pre-state
-func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig, txIt txIterator, miningReward int64) (*state.StateDB, *ExecutionResult, []byte, error) {
+// Apply applies a set of transactions to a pre-state. If an engine is given, the
+// block is processed with the Parlia rules: the built-in system contracts are
+// upgraded, system transactions are set aside and the block end processing of
+// the engine is applied instead of the mining reward.
+func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig, txIt txIterator, miningReward int64, engine *parlia.Parlia) (*state.StateDB, *ExecutionResult, []byte, error) {
 	// Capture errors for BLOCKHASH operation, if we haven't been supplied the
 	// required blockhashes
 	var hashError error
@@ -208,6 +214,9 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		chainConfig.DAOForkBlock.Cmp(new(big.Int).SetUint64(pre.Env.Number)) == 0 {
 		misc.ApplyDAOHardFork(statedb)
 	}
+	if engine != nil {
+		systemcontracts.TryUpdateBuildInSystemContract(chainConfig, vmContext.BlockNumber, pre.Env.ParentTimestamp, pre.Env.Timestamp, statedb, true)
+	}
 	evm := vm.NewEVM(vmContext, statedb, chainConfig, vmConfig)
 	if beaconRoot := pre.Env.ParentBeaconBlockRoot; beaconRoot != nil {
 		core.ProcessBeaconBlockRoot(*beaconRoot, evm)
@@ -219,6 +228,10 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		)
 		core.ProcessParentBlockHash(prevHash, evm)
 	}
+	var (
+		header    = pre.header(vmContext)
+		systemTxs []*types.Transaction
+	)
 	for i := 0; txIt.Next(); i++ {
 		tx, err := txIt.Tx()
 		if err != nil {
@@ -226,6 +239,24 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 			rejectedTxs = append(rejectedTxs, &rejectedTx{i, err.Error()})
 			continue
 		}
+		if engine != nil {
+			isSystemTx, err := engine.IsSystemTransaction(tx, header)
+			if err != nil {
+				log.Warn("rejected tx", "index", i, "hash", tx.Hash(), "error", err)
+				rejectedTxs = append(rejectedTxs, &rejectedTx{i, err.Error()})
+				continue
+			}
+			if isSystemTx {
+				systemTxs = append(systemTxs, tx)
+				continue
+			}
+			if len(systemTxs) > 0 && chainConfig.IsCancun(vmContext.BlockNumber, vmContext.Time) {
+				errMsg := "normal tx after system tx"
+				log.Warn("rejected tx", "index", i, "hash", tx.Hash(), "error", errMsg)
+				rejectedTxs = append(rejectedTxs, &rejectedTx{i, errMsg})
+				continue
+			}
+		}
 		if tx.Type() == types.BlobTxType && vmContext.BlobBaseFee == nil {
 			errMsg := "blob tx used but field env.ExcessBlobGas missing"
 			log.Warn("rejected tx", "index", i, "hash", tx.Hash(), "error", errMsg)
@@ -272,8 +303,20 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 
 	statedb.IntermediateRoot(chainConfig.IsEIP158(vmContext.BlockNumber))
 
-	// Add mining reward? (-1 means rewards are disabled)
-	if miningReward >= 0 {
+	// Apply the Parlia block end processing. Without system transactions in
+	// the input, the expected ones are created and included in the block.
+	if engine != nil {
+		var received *[]*types.Transaction
+		if len(systemTxs) > 0 {
+			received = &systemTxs
+		}
+		chain := &parliaChain{config: chainConfig, env: &pre.Env}
+		if err := engine.FinalizeTransition(chain, header, pre.Env.ParentTimestamp, statedb, (*[]*types.Transaction)(&includedTxs), (*[]*types.Receipt)(&receipts), received, &gasUsed, pre.Env.SpoiledValidator, vmConfig.Tracer); err != nil {
+			return nil, nil, nil, NewError(ErrorEVM, fmt.Errorf("could not finalize block: %v", err))
+		}
+	}
+	// Add mining reward? (-1 means rewards are disabled, Parlia has none)
+	if miningReward >= 0 && engine == nil {
 		// Add mining reward. The mining reward may be `0`, which only makes a difference in the cases
 		// where
 		// - the coinbase self-destructed, or
@@ -305,7 +348,7 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 
 	// Gather the execution-layer triggered requests.
 	var requests [][]byte
-	if chainConfig.IsPrague(vmContext.BlockNumber, vmContext.Time) {
+	if chainConfig.IsPrague(vmContext.BlockNumber, vmContext.Time) && chainConfig.IsNotInBSC() {
 		requests = [][]byte{}
 		// EIP-6110
 		var allLogs []*types.Log
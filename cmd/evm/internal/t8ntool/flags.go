@@ -127,6 +127,10 @@ var (
 		Name:  "seal.clique",
 		Usage: "Seal block with Clique. `stdin` or file name of where to find the Clique sealing data.",
 	}
+	ParliaFlag = &cli.BoolFlag{
+		Name:  "parlia",
+		Usage: "Process blocks with the Parlia consensus rules of BSC, including system contract upgrades, system transactions and reward distribution. Requires a BSC fork",
+	}
 	RewardFlag = &cli.Int64Flag{
 		Name:  "state.reward",
 		Usage: "Mining reward. Set to -1 to disable",
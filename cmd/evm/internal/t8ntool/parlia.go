@@ -0,0 +1,123 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package t8ntool
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// diffInTurn is the difficulty of an in-turn Parlia block, used if the env does
+// not specify one.
+var diffInTurn = big.NewInt(2)
+
+// newParlia creates the Parlia engine for processing the state transition.
+func newParlia(chainConfig *params.ChainConfig) (*parlia.Parlia, error) {
+	if chainConfig.Parlia == nil {
+		return nil, NewError(ErrorConfig, errors.New("parlia mode requires a BSC fork"))
+	}
+	return parlia.New(chainConfig, rawdb.NewMemoryDatabase(), nil, common.Hash{}), nil
+}
+
+// applyParliaChecks fills in the env defaults of a Parlia block.
+func applyParliaChecks(env *stEnv) {
+	if env.Difficulty == nil {
+		env.Difficulty = new(big.Int).Set(diffInTurn)
+	}
+}
+
+// header assembles the header of the block being built, as far as it is known
+// before executing the transactions.
+func (pre *Prestate) header(vmContext vm.BlockContext) *types.Header {
+	header := &types.Header{
+		Coinbase:      pre.Env.Coinbase,
+		Number:        new(big.Int).Set(vmContext.BlockNumber),
+		Time:          pre.Env.Timestamp,
+		GasLimit:      pre.Env.GasLimit,
+		Difficulty:    new(big.Int),
+		BaseFee:       vmContext.BaseFee,
+		ExcessBlobGas: pre.Env.ExcessBlobGas,
+	}
+	if vmContext.Difficulty != nil {
+		header.Difficulty.Set(vmContext.Difficulty)
+	}
+	if vmContext.Random != nil {
+		header.MixDigest = *vmContext.Random
+	}
+	if pre.Env.Number > 0 {
+		header.ParentHash = pre.Env.BlockHashes[math.HexOrDecimal64(pre.Env.Number-1)]
+	}
+	return header
+}
+
+// parliaChain is the chain of the state transition. Ancestors are only known by
+// the hashes given in the env, so they are represented by stub headers linking
+// them, which suffices to serve BLOCKHASH and to walk the ancestors for the
+// finality reward. The stubs carry no vote attestations.
+type parliaChain struct {
+	config *params.ChainConfig
+	env    *stEnv
+}
+
+func (c *parliaChain) Config() *params.ChainConfig                      { return c.config }
+func (c *parliaChain) GenesisHeader() *types.Header                     { return c.GetHeaderByNumber(0) }
+func (c *parliaChain) GetTd(common.Hash, uint64) *big.Int               { return nil }
+func (c *parliaChain) GetHighestVerifiedHeader() *types.Header          { return nil }
+func (c *parliaChain) GetVerifiedBlockByHash(common.Hash) *types.Header { return nil }
+func (c *parliaChain) CurrentHeader() *types.Header {
+	if c.env.Number == 0 {
+		return nil
+	}
+	return c.GetHeaderByNumber(c.env.Number - 1)
+}
+
+func (c *parliaChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if c.env.BlockHashes[math.HexOrDecimal64(number)] != hash {
+		return nil
+	}
+	return c.GetHeaderByNumber(number)
+}
+
+func (c *parliaChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	for number, h := range c.env.BlockHashes {
+		if h == hash {
+			return c.GetHeaderByNumber(uint64(number))
+		}
+	}
+	return nil
+}
+
+// GetHeaderByNumber returns a stub header of an ancestor. It only carries the
+// number and the parent hash, its own hash is the one given in the env.
+func (c *parliaChain) GetHeaderByNumber(number uint64) *types.Header {
+	if _, ok := c.env.BlockHashes[math.HexOrDecimal64(number)]; !ok || number >= c.env.Number {
+		return nil
+	}
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: new(big.Int)}
+	if number > 0 {
+		header.ParentHash = c.env.BlockHashes[math.HexOrDecimal64(number-1)]
+	}
+	return header
+}
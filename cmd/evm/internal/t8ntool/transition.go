@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -138,6 +139,13 @@ func Transition(ctx *cli.Context) error {
 	if txIt, err = loadTransactions(txStr, inputData, chainConfig); err != nil {
 		return err
 	}
+	var engine *parlia.Parlia
+	if ctx.Bool(ParliaFlag.Name) {
+		if engine, err = newParlia(chainConfig); err != nil {
+			return err
+		}
+		applyParliaChecks(&prestate.Env)
+	}
 	if err := applyLondonChecks(&prestate.Env, chainConfig); err != nil {
 		return err
 	}
@@ -177,7 +185,7 @@ func Transition(ctx *cli.Context) error {
 		}
 	}
 	// Run the test and aggregate the result
-	s, result, body, err := prestate.Apply(vmConfig, chainConfig, txIt, ctx.Int64(RewardFlag.Name))
+	s, result, body, err := prestate.Apply(vmConfig, chainConfig, txIt, ctx.Int64(RewardFlag.Name), engine)
 	if err != nil {
 		return err
 	}
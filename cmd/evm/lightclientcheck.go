@@ -0,0 +1,238 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/evm/internal/lcprecompile"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	LightClientFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block of the range to extract precompile calls from",
+	}
+	LightClientToFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block of the range to extract precompile calls from (defaults to --from)",
+	}
+	LightClientCorpusFlag = &cli.StringFlag{
+		Name:  "corpus",
+		Usage: "Write the extracted precompile calls into the given file",
+	}
+	LightClientAllVersionsFlag = &cli.BoolFlag{
+		Name:  "all-versions",
+		Usage: "Run the calls through every version of the precompiles, not only the active one",
+	}
+	LightClientOutputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "Write the report as JSON into the given file instead of printing a table",
+	}
+)
+
+var lightClientCheckCommand = &cli.Command{
+	Action:    lightClientCheckCmd,
+	Name:      "lightclient-check",
+	Usage:     "Verifies the light client precompiles against historical calls",
+	ArgsUsage: "[<corpus file>...]",
+	Description: `
+The lightclient-check command runs historical calls of the light client
+precompiles (tendermint header, iavl merkle proof and cometBFT light block
+validation) through the precompile version active at the height they were made
+at, and reports every call whose output, gas usage or error differs from the
+recorded one.
+
+The calls are either extracted by replaying the --from/--to block range of the
+chain in --datadir, optionally saving them with --corpus, or loaded from the
+corpus files given as arguments. A replay whose gas usage or status differs
+from the receipts stored in the database is rejected, as its calls would not
+reflect the historical results. With --all-versions the calls are also run
+through every other version of the precompiles, reporting how many of them
+each version reproduces.`,
+	Flags: []cli.Flag{
+		LightClientFromFlag,
+		LightClientToFlag,
+		LightClientCorpusFlag,
+		LightClientAllVersionsFlag,
+		LightClientOutputFlag,
+		utils.DataDirFlag,
+		utils.GCModeFlag,
+		utils.StateSchemeFlag,
+	},
+}
+
+func lightClientCheckCmd(ctx *cli.Context) error {
+	var corpora []*lcprecompile.Corpus
+
+	switch {
+	case ctx.Args().Present():
+		for _, path := range ctx.Args().Slice() {
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			corpus := new(lcprecompile.Corpus)
+			if err := json.Unmarshal(src, corpus); err != nil {
+				return fmt.Errorf("%s: invalid corpus: %v", path, err)
+			}
+			if corpus.Config == nil {
+				return fmt.Errorf("%s: chain config missing", path)
+			}
+			corpora = append(corpora, corpus)
+		}
+
+	case ctx.IsSet(LightClientFromFlag.Name):
+		stack, err := node.New(&node.Config{Name: "geth", DataDir: ctx.String(utils.DataDirFlag.Name)})
+		if err != nil {
+			return err
+		}
+		defer stack.Close()
+
+		chain, _ := utils.MakeChain(ctx, stack, true)
+		defer chain.Stop()
+
+		corpus, err := recordLightClientCalls(ctx, chain)
+		if err != nil {
+			return err
+		}
+		if path := ctx.String(LightClientCorpusFlag.Name); path != "" {
+			out, err := json.MarshalIndent(corpus, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, out, 0644); err != nil {
+				return err
+			}
+		}
+		corpora = append(corpora, corpus)
+
+	default:
+		return errors.New("corpus files or a block range required")
+	}
+	var (
+		allVersions = ctx.Bool(LightClientAllVersionsFlag.Name)
+		mismatches  int
+		reports     []*lcprecompile.Report
+	)
+	for _, corpus := range corpora {
+		report := lcprecompile.Verify(corpus, allVersions)
+		mismatches += len(report.Mismatches)
+		reports = append(reports, report)
+	}
+	if path := ctx.String(LightClientOutputFlag.Name); path != "" {
+		out, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, out, 0644); err != nil {
+			return err
+		}
+	} else {
+		for _, report := range reports {
+			printLightClientReport(report)
+		}
+	}
+	if mismatches > 0 {
+		return fmt.Errorf("%d precompile call(s) not reproduced", mismatches)
+	}
+	return nil
+}
+
+// recordLightClientCalls replays the configured block range, extracting the
+// light client precompile calls. The replayed receipts are checked against the
+// stored ones, so the extracted calls are anchored to the chain history rather
+// than to the precompiles of this binary.
+func recordLightClientCalls(ctx *cli.Context, chain *core.BlockChain) (*lcprecompile.Corpus, error) {
+	from := ctx.Uint64(LightClientFromFlag.Name)
+	to := from
+	if ctx.IsSet(LightClientToFlag.Name) {
+		to = ctx.Uint64(LightClientToFlag.Name)
+	}
+	if from == 0 || to < from {
+		return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	var (
+		recorder = lcprecompile.NewRecorder()
+		config   = vm.Config{Tracer: recorder.Hooks()}
+		start    = time.Now()
+		logged   = time.Now()
+	)
+	for number := from; number <= to; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		parent := chain.GetHeader(block.ParentHash(), number-1)
+		if parent == nil {
+			return nil, fmt.Errorf("parent of block %d not found", number)
+		}
+		statedb, err := chain.StateAt(parent.Root)
+		if err != nil {
+			return nil, fmt.Errorf("state of block %d unavailable: %v", number-1, err)
+		}
+		res, err := chain.Processor().Process(block, statedb, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay block %d: %v", number, err)
+		}
+		stored := chain.GetReceiptsByHash(block.Hash())
+		if stored == nil {
+			return nil, fmt.Errorf("receipts of block %d not found", number)
+		}
+		if err := lcprecompile.CheckReceipts(number, res.Receipts, stored); err != nil {
+			return nil, err
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Extracting precompile calls", "number", number, "remaining", to-number, "calls", len(recorder.Calls()), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	calls := recorder.Calls()
+	log.Info("Extracted precompile calls", "from", from, "to", to, "calls", len(calls), "elapsed", common.PrettyDuration(time.Since(start)))
+	return &lcprecompile.Corpus{Config: chain.Config(), Calls: calls}, nil
+}
+
+// printLightClientReport prints the report as a human readable table.
+func printLightClientReport(report *lcprecompile.Report) {
+	fmt.Printf("calls: %d, mismatches: %d\n\n", report.Calls, len(report.Mismatches))
+
+	for _, m := range report.Mismatches {
+		fmt.Printf("block %d, %s (%s): have gas %d, output %x, error %q; want gas %d, output %x, error %q\n",
+			m.Call.Block, m.Call.Address, m.Version, m.Result.GasUsed, m.Result.Output, m.Result.Error, m.Call.GasUsed, m.Call.Output, m.Call.Error)
+	}
+	if len(report.Mismatches) > 0 {
+		fmt.Println()
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PRECOMPILE\tVERSION\tACTIVE\tMATCHED\tTOTAL")
+	for _, s := range report.Versions {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n", s.Address, s.Version, s.Active, s.Matched, s.Total)
+	}
+	w.Flush()
+}
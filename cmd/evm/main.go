@@ -160,6 +160,7 @@ var (
 			t8ntool.ForknameFlag,
 			t8ntool.ChainIDFlag,
 			t8ntool.RewardFlag,
+			t8ntool.ParliaFlag,
 		},
 	}
 	transactionCommand = &cli.Command{
@@ -216,6 +217,9 @@ func init() {
 		stateTransitionCommand,
 		transactionCommand,
 		blockBuilderCommand,
+		fusionProfileCommand,
+		opcodeCheckCommand,
+		lightClientCheckCommand,
 	}
 	app.Before = func(ctx *cli.Context) error {
 		flags.MigrateGlobalFlags(ctx)
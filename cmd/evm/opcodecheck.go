@@ -0,0 +1,87 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/opcodeCompiler/compiler"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/urfave/cli/v2"
+)
+
+var opcodeCheckCommand = &cli.Command{
+	Action:    opcodeCheckCmd,
+	Name:      "opcode-check",
+	Usage:     "Executes bytecode with the optimized and the plain interpreter and compares the outcome",
+	ArgsUsage: "<bytecode file or directory>...",
+	Description: `
+The opcode-check command runs every given hex encoded bytecode file with both
+the optimized (vm.opcode.optimize) and the plain interpreter on identical
+state, and reports any difference in gas, errors, return data, logs or state.`,
+	Flags: []cli.Flag{
+		InputFlag,
+		GasFlag,
+	},
+}
+
+func opcodeCheckCmd(ctx *cli.Context) error {
+	if !ctx.Args().Present() {
+		return errors.New("bytecode files required")
+	}
+	var files []string
+	for _, path := range ctx.Args().Slice() {
+		err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				files = append(files, path)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	compiler.EnableOptimization()
+
+	var (
+		input      = common.FromHex(ctx.String(InputFlag.Name))
+		mismatches int
+	)
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		code := common.FromHex(string(bytes.TrimSpace(src)))
+		if mismatch := runtime.CheckOpcodeOptimization(code, input, &runtime.Config{GasLimit: ctx.Uint64(GasFlag.Name)}); mismatch != nil {
+			fmt.Printf("%s: %v\n", file, mismatch)
+			mismatches++
+			continue
+		}
+		fmt.Printf("%s: ok\n", file)
+	}
+	if mismatches > 0 {
+		return fmt.Errorf("%d of %d programs diverged", mismatches, len(files))
+	}
+	return nil
+}
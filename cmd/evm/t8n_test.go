@@ -117,6 +117,7 @@ func TestT8n(t *testing.T) {
 		base        string
 		input       t8nInput
 		output      t8nOutput
+		parlia      bool
 		expExitCode int
 		expOut      string
 	}{
@@ -307,10 +308,40 @@ func TestT8n(t *testing.T) {
 			output: t8nOutput{alloc: true, result: true},
 			expOut: "exp.json",
 		},
+		{ // Parlia test, system transactions created by the tool
+			base: "./testdata/35",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "Hertz", "",
+			},
+			output: t8nOutput{alloc: true, result: true},
+			parlia: true,
+			expOut: "exp.json",
+		},
+		{ // Parlia test, system transactions supplied in the input
+			base: "./testdata/35",
+			input: t8nInput{
+				"alloc.json", "txs_system.json", "env.json", "Hertz", "",
+			},
+			output: t8nOutput{alloc: true, result: true},
+			parlia: true,
+			expOut: "exp_system.json",
+		},
+		{ // Parlia test, requires a BSC fork
+			base: "./testdata/35",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "London", "",
+			},
+			output:      t8nOutput{alloc: true, result: true},
+			parlia:      true,
+			expExitCode: 3,
+		},
 	} {
 		args := []string{"t8n"}
 		args = append(args, tc.output.get()...)
 		args = append(args, tc.input.get(tc.base)...)
+		if tc.parlia {
+			args = append(args, "--parlia")
+		}
 		var qArgs []string // quoted args for debugging purposes
 		for _, arg := range args {
 			if len(arg) == 0 {
@@ -18,6 +18,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -47,6 +48,7 @@ import (
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/naoina/toml"
 	"github.com/urfave/cli/v2"
 )
@@ -61,6 +63,19 @@ var (
 		Description: `Export configuration values in TOML format (to stdout by default).`,
 	}
 
+	dumpOpenRPCCommand = &cli.Command{
+		Action:    dumpOpenRPC,
+		Name:      "dumpopenrpc",
+		Usage:     "Export the OpenRPC document of the RPC APIs",
+		ArgsUsage: "<dumpfile (optional)>",
+		Flags:     slices.Concat(nodeFlags, rpcFlags),
+		Description: `
+Export the OpenRPC document describing all the RPC methods of the node configured
+by the flags (to stdout by default), as served by rpc_discover. The document
+covers the methods of all namespaces, regardless of the ones exposed over HTTP,
+WebSocket or IPC.`,
+	}
+
 	configFileFlag = &cli.StringFlag{
 		Name:     "config",
 		Usage:    "TOML configuration file",
@@ -393,6 +408,34 @@ func dumpConfig(ctx *cli.Context) error {
 	return nil
 }
 
+// dumpOpenRPC is the dumpopenrpc command.
+func dumpOpenRPC(ctx *cli.Context) error {
+	stack, _ := makeFullNode(ctx)
+	defer stack.Close()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	for _, api := range stack.APIs() {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			return err
+		}
+	}
+	out, err := json.MarshalIndent(server.OpenRPCDocument(), "", "  ")
+	if err != nil {
+		return err
+	}
+	dump := os.Stdout
+	if ctx.NArg() > 0 {
+		dump, err = os.OpenFile(ctx.Args().Get(0), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer dump.Close()
+	}
+	_, err = dump.Write(append(out, '\n'))
+	return err
+}
+
 func applyMetricConfig(ctx *cli.Context, cfg *gethConfig) {
 	if ctx.IsSet(utils.MetricsEnabledFlag.Name) {
 		cfg.Metrics.Enabled = ctx.Bool(utils.MetricsEnabledFlag.Name)
@@ -100,6 +100,9 @@ var (
 		utils.TxPoolOverflowPoolSlotsFlag, // deprecated
 		utils.TxPoolLifetimeFlag,
 		utils.TxPoolReannounceTimeFlag,
+		utils.TxPoolLifecycleRetentionFlag,
+		utils.TxPoolSenderRateFlag,
+		utils.TxPoolTargetRateFlag,
 		utils.MinerTxGasLimitFlag,
 		utils.EnableBALFlag,
 		utils.BlobPoolDataDirFlag,
@@ -155,6 +158,13 @@ var (
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.PeerFilterPatternsFlag,
+		utils.MessageCaptureFlag,
+		utils.MessageCapturePayloadsFlag,
+		utils.SentryFlag,
+		utils.SentryValidatorsFlag,
+		utils.BehindSentryFlag,
+		utils.UploadBudgetFlag,
+		utils.DownloadBudgetFlag,
 		utils.DiscoveryV4Flag,
 		utils.DiscoveryV5Flag,
 		utils.InstanceFlag,
@@ -171,6 +181,8 @@ var (
 		utils.VMTraceJsonConfigFlag,
 		utils.VMWitnessStatsFlag,
 		utils.VMStatelessSelfValidationFlag,
+		utils.ServeWitnessesFlag,
+		utils.StatelessVerifyFlag,
 		utils.NetworkIdFlag,
 		utils.EthStatsURLFlag,
 		utils.GpoBlocksFlag,
@@ -182,6 +194,7 @@ var (
 		utils.EnableDoubleSignMonitorFlag,
 		utils.VotingEnabledFlag,
 		utils.DisableVoteAttestationFlag,
+		utils.VoteTargetedRelayFlag,
 		utils.EnableMaliciousVoteMonitorFlag,
 		utils.BLSPasswordFileFlag,
 		utils.BLSWalletDirFlag,
@@ -190,6 +203,9 @@ var (
 		utils.LogBacktraceAtFlag,
 		utils.BlobExtraReserveFlag,
 		utils.VMOpcodeOptimizeFlag,
+		utils.VMOpcodeOptimizeCheckFlag,
+		utils.VMOpcodeCacheFlag,
+		utils.VMOpcodeCacheWarmFlag,
 		utils.EnableIncrSnapshotFlag,
 		utils.IncrSnapshotPathFlag,
 		utils.IncrSnapshotBlockIntervalFlag,
@@ -234,10 +250,16 @@ var (
 		utils.RPCGlobalGasCapFlag,
 		utils.RPCGlobalEVMTimeoutFlag,
 		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCResponseCacheFlag,
+		utils.RPCTraceExportFlag,
 		utils.RPCGlobalLogQueryLimit,
 		utils.AllowUnprotectedTxs,
 		utils.BatchRequestLimit,
 		utils.BatchResponseMaxSize,
+		utils.RPCAPIKeysFlag,
+		utils.RPCRequestLogFlag,
+		utils.RPCRequestLogSampleRateFlag,
+		utils.RPCRequestLogSlowFlag,
 		utils.RPCTxSyncDefaultTimeoutFlag,
 		utils.RPCTxSyncMaxTimeoutFlag,
 	}
@@ -294,6 +316,7 @@ func init() {
 		licenseCommand,
 		// See config.go
 		dumpConfigCommand,
+		dumpOpenRPCCommand,
 		// see dbcmd.go
 		dbCommand,
 		// See cmd/utils/flags_legacy.go
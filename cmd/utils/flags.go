@@ -566,6 +566,24 @@ var (
 		Value:    ethconfig.Defaults.TxPool.ReannounceTime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolLifecycleRetentionFlag = &cli.DurationFlag{
+		Name:     "txpool.lifecycleretention",
+		Usage:    "Time window to retain transaction lifecycle records for eth_getTransactionLifecycle (0 = disabled)",
+		Value:    ethconfig.Defaults.TxPool.LifecycleRetention,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolSenderRateFlag = &cli.Uint64Flag{
+		Name:     "txpool.senderrate",
+		Usage:    "Maximum number of transactions admitted per minute from a well-scored sender, scaled down by its score, local submissions included (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.SenderRate,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolTargetRateFlag = &cli.Uint64Flag{
+		Name:     "txpool.targetrate",
+		Usage:    "Maximum number of transactions admitted per minute towards a single recipient, local submissions included (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.TargetRate,
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -737,6 +755,16 @@ var (
 		Usage:    "Generate execution witnesses and self-check against them (testing purpose)",
 		Category: flags.VMCategory,
 	}
+	ServeWitnessesFlag = &cli.BoolFlag{
+		Name:     "serve-witnesses",
+		Usage:    "Serve execution witnesses of recent blocks to bsc/4 peers",
+		Category: flags.VMCategory,
+	}
+	StatelessVerifyFlag = &cli.BoolFlag{
+		Name:     "stateless-verify",
+		Usage:    "Import blocks without keeping state, verifying them against witnesses fetched from peers",
+		Category: flags.VMCategory,
+	}
 	// API options.
 	RPCGlobalGasCapFlag = &cli.Uint64Flag{
 		Name:     "rpc.gascap",
@@ -756,6 +784,17 @@ var (
 		Value:    ethconfig.Defaults.RPCTxFeeCap,
 		Category: flags.APICategory,
 	}
+	RPCResponseCacheFlag = &cli.IntFlag{
+		Name:     "rpc.responsecache",
+		Usage:    "Megabytes of memory allocated to caching RPC responses of finalized blocks (0 = disabled)",
+		Value:    ethconfig.Defaults.RPCResponseCache,
+		Category: flags.APICategory,
+	}
+	RPCTraceExportFlag = &cli.BoolFlag{
+		Name:     "rpc.traceexport",
+		Usage:    "Enable the background trace export jobs on the authenticated debug API, writing into the data directory",
+		Category: flags.APICategory,
+	}
 	RPCGlobalLogQueryLimit = &cli.IntFlag{
 		Name:     "rpc.logquerylimit",
 		Usage:    "Maximum number of alternative addresses or topics allowed per search position in eth_getLogs filter criteria (0 = no cap)",
@@ -951,6 +990,28 @@ var (
 		Value:    node.DefaultConfig.BatchResponseMaxSize,
 		Category: flags.APICategory,
 	}
+	RPCAPIKeysFlag = &cli.StringFlag{
+		Name:     "rpc.apikeys",
+		Usage:    "JSON file of the API keys required on the HTTP and WS endpoints, with per-key method allowlists and quotas (reloaded on change)",
+		Category: flags.APICategory,
+	}
+	RPCRequestLogFlag = &cli.StringFlag{
+		Name:     "rpc.requestlog",
+		Usage:    "File recording the calls served on the HTTP and WS endpoints (rotated hourly)",
+		Category: flags.APICategory,
+	}
+	RPCRequestLogSampleRateFlag = &cli.Float64Flag{
+		Name:     "rpc.requestlog.samplerate",
+		Usage:    "Fraction of the calls recorded in the RPC request log",
+		Value:    node.DefaultConfig.RPCRequestLogSampleRate,
+		Category: flags.APICategory,
+	}
+	RPCRequestLogSlowFlag = &cli.DurationFlag{
+		Name:     "rpc.requestlog.slow",
+		Usage:    "Duration above which calls are always recorded in the RPC request log (0 = disabled)",
+		Value:    node.DefaultConfig.RPCRequestLogSlow,
+		Category: flags.APICategory,
+	}
 
 	// Network Settings
 	MaxPeersFlag = &cli.IntFlag{
@@ -1011,6 +1072,41 @@ var (
 		Usage:    "Disallow peers connection if peer name matches the given regular expressions",
 		Category: flags.NetworkingCategory,
 	}
+	MessageCaptureFlag = &cli.StringFlag{
+		Name:     "p2p.capture",
+		Usage:    "File to record the p2p messages exchanged with the peers into (rotated hourly)",
+		Category: flags.NetworkingCategory,
+	}
+	MessageCapturePayloadsFlag = &cli.BoolFlag{
+		Name:     "p2p.capture.payloads",
+		Usage:    "Include the message payloads in the p2p message capture, as needed to replay it",
+		Category: flags.NetworkingCategory,
+	}
+	SentryFlag = &cli.BoolFlag{
+		Name:     "sentry",
+		Usage:    "Run as a sentry of validators: they are hidden from discovery, always accepted and get blocks and votes first",
+		Category: flags.NetworkingCategory,
+	}
+	SentryValidatorsFlag = &cli.StringFlag{
+		Name:     "sentry.validators",
+		Usage:    "Comma separated node IDs of the validators protected by the sentry, added to the proxyed node IDs",
+		Category: flags.NetworkingCategory,
+	}
+	BehindSentryFlag = &cli.StringFlag{
+		Name:     "behind-sentry",
+		Usage:    "Comma separated enode URLs of the sentries to run the validator behind, disabling discovery and any other connection",
+		Category: flags.NetworkingCategory,
+	}
+	UploadBudgetFlag = &cli.Uint64Flag{
+		Name:     "p2p.budget.upload",
+		Usage:    "Upload bandwidth budget in bytes per second, cutting back propagation and peers near it (0 = no budget)",
+		Category: flags.NetworkingCategory,
+	}
+	DownloadBudgetFlag = &cli.Uint64Flag{
+		Name:     "p2p.budget.download",
+		Usage:    "Download bandwidth budget in bytes per second, cutting back propagation and peers near it (0 = no budget)",
+		Category: flags.NetworkingCategory,
+	}
 	DiscoveryV4Flag = &cli.BoolFlag{
 		Name:     "discovery.v4",
 		Aliases:  []string{"discv4"},
@@ -1246,6 +1342,22 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Usage:    "enable opcode optimization",
 		Category: flags.VMCategory,
 	}
+	VMOpcodeCacheFlag = &cli.BoolFlag{
+		Name:     "vm.opcode.cache",
+		Usage:    "Persist optimized code and jumpdest bitvecs across restarts",
+		Category: flags.VMCategory,
+	}
+	VMOpcodeCacheWarmFlag = &cli.IntFlag{
+		Name:     "vm.opcode.cache.warm",
+		Usage:    "Number of most called contracts to load from the persistent opcode cache at startup",
+		Value:    ethconfig.Defaults.OpcodeCacheWarm,
+		Category: flags.VMCategory,
+	}
+	VMOpcodeOptimizeCheckFlag = &cli.BoolFlag{
+		Name:     "vm.opcode.optimize.check",
+		Usage:    "Cross-check every optimized transaction against the plain interpreter, disabling optimization for diverging code (slow)",
+		Category: flags.VMCategory,
+	}
 
 	CheckSnapshotWithMPT = &cli.BoolFlag{
 		Name:     "check-snapshot-with-mpt",
@@ -1271,6 +1383,12 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Category: flags.FastFinalityCategory,
 	}
 
+	VoteTargetedRelayFlag = &cli.BoolFlag{
+		Name:     "vote.targetedrelay",
+		Usage:    "Send own votes to the EVN peers of the upcoming in-turn proposers before relaying them to the other peers, requires Node.EnableEVNFeatures",
+		Category: flags.FastFinalityCategory,
+	}
+
 	EnableMaliciousVoteMonitorFlag = &cli.BoolFlag{
 		Name:     "monitor.maliciousvote",
 		Usage:    "Enable malicious vote monitor to check whether any validator violates the voting rules of fast finality",
@@ -1418,6 +1536,34 @@ func setNodeUserIdent(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setSentryMode configures the sentry mode pair from the command line flags.
+func setSentryMode(ctx *cli.Context, cfg *p2p.Config) {
+	flags.CheckExclusive(ctx, SentryFlag, BehindSentryFlag)
+	if ctx.Bool(SentryFlag.Name) {
+		cfg.Sentry = true
+	}
+	for _, id := range SplitAndTrim(ctx.String(SentryValidatorsFlag.Name)) {
+		nodeID, err := enode.ParseID(id)
+		if err != nil {
+			Fatalf("Option %q: invalid node ID %q: %v", SentryValidatorsFlag.Name, id, err)
+		}
+		cfg.ProxyedNodeIds = append(cfg.ProxyedNodeIds, nodeID)
+	}
+	if ctx.IsSet(BehindSentryFlag.Name) {
+		cfg.BehindSentry = true
+		for _, url := range SplitAndTrim(ctx.String(BehindSentryFlag.Name)) {
+			node, err := enode.Parse(enode.ValidSchemes, url)
+			if err != nil {
+				Fatalf("Option %q: invalid enode %q: %v", BehindSentryFlag.Name, url, err)
+			}
+			cfg.SentryNodes = append(cfg.SentryNodes, node)
+		}
+	}
+	if cfg.BehindSentry && (ctx.IsSet(DiscoveryV4Flag.Name) || ctx.IsSet(DiscoveryV5Flag.Name)) {
+		log.Warn("Discovery is disabled behind sentries")
+	}
+}
+
 // setBootstrapNodes creates a list of bootstrap nodes from the command line
 // flags, reverting to pre-configured ones if none have been specified.
 // Priority order for bootnodes configuration:
@@ -1565,6 +1711,20 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BatchResponseMaxSize.Name) {
 		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
 	}
+
+	if ctx.IsSet(RPCAPIKeysFlag.Name) {
+		cfg.RPCAPIKeys = ctx.String(RPCAPIKeysFlag.Name)
+	}
+
+	if ctx.IsSet(RPCRequestLogFlag.Name) {
+		cfg.RPCRequestLog = ctx.String(RPCRequestLogFlag.Name)
+	}
+	if ctx.IsSet(RPCRequestLogSampleRateFlag.Name) {
+		cfg.RPCRequestLogSampleRate = ctx.Float64(RPCRequestLogSampleRateFlag.Name)
+	}
+	if ctx.IsSet(RPCRequestLogSlowFlag.Name) {
+		cfg.RPCRequestLogSlow = ctx.Duration(RPCRequestLogSlowFlag.Name)
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1749,6 +1909,20 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	if ctx.IsSet(PeerFilterPatternsFlag.Name) {
 		cfg.PeerFilterPatterns = ctx.StringSlice(PeerFilterPatternsFlag.Name)
 	}
+	if ctx.IsSet(MessageCaptureFlag.Name) {
+		cfg.MessageCapture = ctx.String(MessageCaptureFlag.Name)
+	}
+	if ctx.IsSet(MessageCapturePayloadsFlag.Name) {
+		cfg.MessageCapturePayloads = ctx.Bool(MessageCapturePayloadsFlag.Name)
+	}
+	setSentryMode(ctx, cfg)
+
+	if ctx.IsSet(UploadBudgetFlag.Name) {
+		cfg.UploadBudget = ctx.Uint64(UploadBudgetFlag.Name)
+	}
+	if ctx.IsSet(DownloadBudgetFlag.Name) {
+		cfg.DownloadBudget = ctx.Uint64(DownloadBudgetFlag.Name)
+	}
 
 	flags.CheckExclusive(ctx, DiscoveryV4Flag, NoDiscoverFlag)
 	flags.CheckExclusive(ctx, DiscoveryV5Flag, NoDiscoverFlag)
@@ -1955,6 +2129,15 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolReannounceTimeFlag.Name) {
 		cfg.ReannounceTime = ctx.Duration(TxPoolReannounceTimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolLifecycleRetentionFlag.Name) {
+		cfg.LifecycleRetention = ctx.Duration(TxPoolLifecycleRetentionFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSenderRateFlag.Name) {
+		cfg.SenderRate = ctx.Uint64(TxPoolSenderRateFlag.Name)
+	}
+	if ctx.IsSet(TxPoolTargetRateFlag.Name) {
+		cfg.TargetRate = ctx.Uint64(TxPoolTargetRateFlag.Name)
+	}
 }
 
 func setBlobPool(ctx *cli.Context, cfg *blobpool.Config) {
@@ -1998,6 +2181,9 @@ func setMiner(ctx *cli.Context, cfg *minerconfig.Config) {
 	if ctx.Bool(DisableVoteAttestationFlag.Name) {
 		cfg.DisableVoteAttestation = true
 	}
+	if ctx.Bool(VoteTargetedRelayFlag.Name) {
+		cfg.VoteTargetedRelay = true
+	}
 	if ctx.IsSet(MinerTxGasLimitFlag.Name) {
 		log.Warn("The flag --miner.txgaslimit is deprecated and has no effect; per-transaction gas limit is now enforced by EIP-7825")
 	}
@@ -2206,6 +2392,24 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			cfg.SyncMode = ethconfig.FullSync
 		}
 	}
+	if ctx.IsSet(ServeWitnessesFlag.Name) {
+		cfg.ServeWitnesses = ctx.Bool(ServeWitnessesFlag.Name)
+	}
+	if ctx.IsSet(StatelessVerifyFlag.Name) {
+		cfg.StatelessVerify = ctx.Bool(StatelessVerifyFlag.Name)
+	}
+	if cfg.StatelessVerify {
+		// A stateless node is neither able to provide snap data nor to snap sync.
+		log.Info("Automatically disables snap protocol due to stateless verification")
+		cfg.DisableSnapProtocol = true
+		if cfg.SyncMode == ethconfig.SnapSync {
+			log.Warn("Stateless verification does not support snap sync, resetting to full sync")
+			cfg.SyncMode = ethconfig.FullSync
+		}
+		if cfg.TriesVerifyMode != core.LocalVerify {
+			Fatalf("--%s is incompatible with --%s=%s", StatelessVerifyFlag.Name, TriesVerifyModeFlag.Name, cfg.TriesVerifyMode)
+		}
+	}
 	if ctx.IsSet(CacheFlag.Name) || ctx.IsSet(CacheSnapshotFlag.Name) {
 		cfg.SnapshotCache = ctx.Int(CacheFlag.Name) * ctx.Int(CacheSnapshotFlag.Name) / 100
 	}
@@ -2256,6 +2460,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			compiler.EnableOptimization()
 		}
 	}
+	if ctx.IsSet(VMOpcodeOptimizeCheckFlag.Name) {
+		cfg.CheckOpcodeOptimizing = ctx.Bool(VMOpcodeOptimizeCheckFlag.Name)
+	}
+	if ctx.IsSet(VMOpcodeCacheFlag.Name) {
+		cfg.OpcodeCachePersist = ctx.Bool(VMOpcodeCacheFlag.Name)
+	}
+	if ctx.IsSet(VMOpcodeCacheWarmFlag.Name) {
+		cfg.OpcodeCacheWarm = ctx.Int(VMOpcodeCacheWarmFlag.Name)
+	}
 
 	if ctx.IsSet(RPCGlobalGasCapFlag.Name) {
 		cfg.RPCGasCap = ctx.Uint64(RPCGlobalGasCapFlag.Name)
@@ -2271,6 +2484,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.Float64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.IsSet(RPCResponseCacheFlag.Name) {
+		cfg.RPCResponseCache = ctx.Int(RPCResponseCacheFlag.Name)
+	}
+	if ctx.IsSet(RPCTraceExportFlag.Name) {
+		cfg.TraceExport = ctx.Bool(RPCTraceExportFlag.Name)
+	}
 	if ctx.IsSet(NoDiscoverFlag.Name) {
 		cfg.EthDiscoveryURLs, cfg.SnapDiscoveryURLs, cfg.BscDiscoveryURLs = []string{}, []string{}, []string{}
 	} else if ctx.IsSet(DNSDiscoveryFlag.Name) {
@@ -2486,6 +2705,15 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 		Fatalf("Failed to register the Ethereum service: %v", err)
 	}
 	stack.RegisterAPIs(tracers.APIs(backend.APIBackend))
+	if cfg.TraceExport {
+		dir := stack.ResolvePath("traceexport")
+		if dir == "" {
+			Fatalf("Trace export requires a data directory")
+		}
+		exporter := tracers.NewExporter(backend.APIBackend, dir)
+		stack.RegisterLifecycle(exporter)
+		stack.RegisterAPIs(exporter.APIs())
+	}
 	return backend.APIBackend, backend
 }
 
@@ -2869,6 +3097,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 	vmcfg := vm.Config{
 		EnablePreimageRecording:   ctx.Bool(VMEnableDebugFlag.Name),
 		EnableOpcodeOptimizations: ctx.Bool(VMOpcodeOptimizeFlag.Name),
+		CheckOpcodeOptimizations:  ctx.Bool(VMOpcodeOptimizeCheckFlag.Name),
 	}
 
 	if vmcfg.EnableOpcodeOptimizations {
@@ -1,24 +1,19 @@
 package parlia
 
 import (
-	"context"
 	"errors"
-	"math"
 	"math/big"
 	mrand "math/rand"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/systemcontracts"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
-	"github.com/ethereum/go-ethereum/rpc"
 )
 
-func (p *Parlia) getTurnLength(chain consensus.ChainHeaderReader, header *types.Header) (*uint8, error) {
+func (p *Parlia) getTurnLength(call contractCaller, chain consensus.ChainHeaderReader, header *types.Header) (*uint8, error) {
 	parent := chain.GetHeaderByHash(header.ParentHash)
 	if parent == nil {
 		return nil, errors.New("parent not found")
@@ -26,7 +21,7 @@ func (p *Parlia) getTurnLength(chain consensus.ChainHeaderReader, header *types.
 
 	var turnLength uint8
 	if p.chainConfig.IsBohr(parent.Number, parent.Time) {
-		turnLengthFromContract, err := p.getTurnLengthFromContract(parent)
+		turnLengthFromContract, err := p.getTurnLengthFromContract(call, parent)
 		if err != nil {
 			return nil, err
 		}
@@ -42,7 +37,7 @@ func (p *Parlia) getTurnLength(chain consensus.ChainHeaderReader, header *types.
 	return &turnLength, nil
 }
 
-func (p *Parlia) getTurnLengthFromContract(header *types.Header) (turnLength *big.Int, err error) {
+func (p *Parlia) getTurnLengthFromContract(call contractCaller, header *types.Header) (turnLength *big.Int, err error) {
 	// mock to get turnLength from the contract
 	if params.FixedTurnLength >= 1 && params.FixedTurnLength <= 9 {
 		if params.FixedTurnLength == 2 {
@@ -51,26 +46,13 @@ func (p *Parlia) getTurnLengthFromContract(header *types.Header) (turnLength *bi
 		return big.NewInt(int64(params.FixedTurnLength)), nil
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	method := "getTurnLength"
-	toAddress := common.HexToAddress(systemcontracts.ValidatorContract)
-	gas := (hexutil.Uint64)(uint64(math.MaxUint64 / 2))
-
 	data, err := p.validatorSetABI.Pack(method)
 	if err != nil {
 		log.Error("Unable to pack tx for getTurnLength", "error", err)
 		return nil, err
 	}
-	msgData := (hexutil.Bytes)(data)
-
-	blockNr := rpc.BlockNumberOrHashWithHash(header.Hash(), false)
-	result, err := p.ethAPI.Call(ctx, ethapi.TransactionArgs{
-		Gas:  &gas,
-		To:   &toAddress,
-		Data: &msgData,
-	}, &blockNr, nil, nil)
+	result, err := call(header.Hash(), common.HexToAddress(systemcontracts.ValidatorContract), data)
 	if err != nil {
 		return nil, err
 	}
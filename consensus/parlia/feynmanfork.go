@@ -2,22 +2,17 @@ package parlia
 
 import (
 	"container/heap"
-	"context"
 	"errors"
-	"math"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/systemcontracts"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
-	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
-	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // the params should be two blocks' time(timestamp)
@@ -99,21 +94,13 @@ func (h *ValidatorHeap) Pop() interface{} {
 func (p *Parlia) updateValidatorSetV2(state vm.StateDB, header *types.Header, chain core.ChainContext,
 	txs *[]*types.Transaction, receipts *[]*types.Receipt, receivedTxs *[]*types.Transaction, usedGas *uint64, mode systemTxMode, tracer *tracing.Hooks,
 ) error {
-	// 1. get all validators and its voting power
-	blockNr := rpc.BlockNumberOrHashWithHash(header.ParentHash, false)
-	validatorItems, err := p.getValidatorElectionInfo(blockNr)
-	if err != nil {
-		return err
-	}
-	maxElectedValidators, err := p.getMaxElectedValidators(blockNr)
+	// 1. elect the validators from the parent state
+	eValidators, eVotingPowers, eVoteAddrs, err := p.electValidators(p.callContract, header.ParentHash)
 	if err != nil {
 		return err
 	}
 
-	// 2. sort by voting power
-	eValidators, eVotingPowers, eVoteAddrs := getTopValidatorsByVotingPower(validatorItems, maxElectedValidators)
-
-	// 3. update validator set to system contract
+	// 2. update validator set to system contract
 	method := "updateValidatorSetV2"
 	data, err := p.validatorSetABI.Pack(method, eValidators, eVotingPowers, eVoteAddrs)
 	if err != nil {
@@ -127,26 +114,32 @@ func (p *Parlia) updateValidatorSetV2(state vm.StateDB, header *types.Header, ch
 	return p.applyTransaction(msg, state, header, chain, txs, receipts, receivedTxs, usedGas, mode, tracer)
 }
 
-func (p *Parlia) getValidatorElectionInfo(blockNr rpc.BlockNumberOrHash) ([]ValidatorItem, error) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// electValidators elects the top validators by voting power from the stake hub
+// on top of the state of the given block.
+func (p *Parlia) electValidators(call contractCaller, blockHash common.Hash) ([]common.Address, []uint64, [][]byte, error) {
+	// 1. get all validators and its voting power
+	validatorItems, err := p.getValidatorElectionInfo(call, blockHash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	maxElectedValidators, err := p.getMaxElectedValidators(call, blockHash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	method := "getValidatorElectionInfo"
-	toAddress := common.HexToAddress(systemcontracts.StakeHubContract)
-	gas := (hexutil.Uint64)(uint64(math.MaxUint64 / 2))
+	// 2. sort by voting power
+	eValidators, eVotingPowers, eVoteAddrs := getTopValidatorsByVotingPower(validatorItems, maxElectedValidators)
+	return eValidators, eVotingPowers, eVoteAddrs, nil
+}
 
+func (p *Parlia) getValidatorElectionInfo(call contractCaller, blockHash common.Hash) ([]ValidatorItem, error) {
+	method := "getValidatorElectionInfo"
 	data, err := p.stakeHubABI.Pack(method, big.NewInt(0), big.NewInt(0))
 	if err != nil {
 		log.Error("Unable to pack tx for getValidatorElectionInfo", "error", err)
 		return nil, err
 	}
-	msgData := (hexutil.Bytes)(data)
-
-	result, err := p.ethAPI.Call(ctx, ethapi.TransactionArgs{
-		Gas:  &gas,
-		To:   &toAddress,
-		Data: &msgData,
-	}, &blockNr, nil, nil)
+	result, err := call(blockHash, common.HexToAddress(systemcontracts.StakeHubContract), data)
 	if err != nil {
 		return nil, err
 	}
@@ -174,26 +167,14 @@ func (p *Parlia) getValidatorElectionInfo(blockNr rpc.BlockNumberOrHash) ([]Vali
 	return validatorItems, nil
 }
 
-func (p *Parlia) getMaxElectedValidators(blockNr rpc.BlockNumberOrHash) (maxElectedValidators *big.Int, err error) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
+func (p *Parlia) getMaxElectedValidators(call contractCaller, blockHash common.Hash) (maxElectedValidators *big.Int, err error) {
 	method := "maxElectedValidators"
-	toAddress := common.HexToAddress(systemcontracts.StakeHubContract)
-	gas := (hexutil.Uint64)(uint64(math.MaxUint64 / 2))
-
 	data, err := p.stakeHubABI.Pack(method)
 	if err != nil {
 		log.Error("Unable to pack tx for maxElectedValidators", "error", err)
 		return nil, err
 	}
-	msgData := (hexutil.Bytes)(data)
-
-	result, err := p.ethAPI.Call(ctx, ethapi.TransactionArgs{
-		Gas:  &gas,
-		To:   &toAddress,
-		Data: &msgData,
-	}, &blockNr, nil, nil)
+	result, err := call(blockHash, common.HexToAddress(systemcontracts.StakeHubContract), data)
 	if err != nil {
 		return nil, err
 	}
@@ -442,6 +442,51 @@ func getVoteAttestationFromHeader(header *types.Header, chainConfig *params.Chai
 	return &attestation, nil
 }
 
+// GetVoteAttestation returns the vote attestation carried in the header, along
+// with the validators who signed it. Nil is returned if the header carries no
+// attestation.
+func (p *Parlia) GetVoteAttestation(chain consensus.ChainHeaderReader, header *types.Header) (*types.VoteAttestation, []common.Address, error) {
+	epochLength, err := p.epochLength(chain, header, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	attestation, err := getVoteAttestationFromHeader(header, chain.Config(), epochLength)
+	if err != nil || attestation == nil || attestation.Data == nil {
+		return nil, nil, err
+	}
+	// The voters are resolved against the snapshot of the target's parent, the
+	// same way the attestation was verified.
+	target := chain.GetHeader(attestation.Data.TargetHash, attestation.Data.TargetNumber)
+	if target == nil {
+		return nil, nil, consensus.ErrUnknownAncestor
+	}
+	snap, err := p.snapshot(chain, target.Number.Uint64()-1, target.ParentHash, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		validators = snap.validators()
+		bits       = bitset.From([]uint64{uint64(attestation.VoteAddressSet)})
+		voters     = make([]common.Address, 0, bits.Count())
+	)
+	for index, val := range validators {
+		if bits.Test(uint(index)) {
+			voters = append(voters, val)
+		}
+	}
+	return attestation, voters, nil
+}
+
+// GetValidatorSet returns the validator set and the turn length in effect at the
+// given block.
+func (p *Parlia) GetValidatorSet(chain consensus.ChainHeaderReader, header *types.Header) ([]common.Address, uint8, error) {
+	snap, err := p.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	return snap.validators(), snap.TurnLength, nil
+}
+
 // getParent returns the parent of a given block.
 func (p *Parlia) getParent(chain consensus.ChainHeaderReader, header *types.Header, parents []*types.Header) (*types.Header, error) {
 	var parent *types.Header
@@ -989,7 +1034,7 @@ func (p *Parlia) prepareValidators(chain consensus.ChainHeaderReader, header *ty
 		return nil
 	}
 
-	newValidators, voteAddressMap, err := p.getCurrentValidators(header.ParentHash, new(big.Int).Sub(header.Number, big.NewInt(1)))
+	newValidators, voteAddressMap, err := p.getCurrentValidators(p.callContract, header.ParentHash, new(big.Int).Sub(header.Number, big.NewInt(1)))
 	if err != nil {
 		return err
 	}
@@ -1026,7 +1071,7 @@ func (p *Parlia) prepareTurnLength(chain consensus.ChainHeaderReader, header *ty
 		return nil
 	}
 
-	turnLength, err := p.getTurnLength(chain, header)
+	turnLength, err := p.getTurnLength(p.callContract, chain, header)
 	if err != nil {
 		return err
 	}
@@ -1145,6 +1190,16 @@ func (p *Parlia) NextInTurnValidator(chain consensus.ChainHeaderReader, header *
 	return snap.inturnValidator(), nil
 }
 
+// NextInTurnValidators returns the in-turn validators of the next n turns after
+// the header, starting with the next in-turn validator.
+func (p *Parlia) NextInTurnValidators(chain consensus.ChainHeaderReader, header *types.Header, n int) ([]common.Address, error) {
+	snap, err := p.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return snap.inturnValidators(n), nil
+}
+
 // Prepare implements consensus.Engine, preparing all the consensus fields of the
 // header for running the transactions on top.
 func (p *Parlia) Prepare(chain consensus.ChainHeaderReader, header *types.Header) error {
@@ -1214,7 +1269,7 @@ func (p *Parlia) verifyValidators(chain consensus.ChainHeaderReader, header *typ
 		return nil
 	}
 
-	newValidators, voteAddressMap, err := p.getCurrentValidators(header.ParentHash, new(big.Int).Sub(header.Number, big.NewInt(1)))
+	newValidators, voteAddressMap, err := p.getCurrentValidators(p.callContract, header.ParentHash, new(big.Int).Sub(header.Number, big.NewInt(1)))
 	if err != nil {
 		return err
 	}
@@ -1265,7 +1320,7 @@ func (p *Parlia) verifyTurnLength(chain consensus.ChainHeaderReader, header *typ
 		return err
 	}
 	if turnLengthFromHeader != nil {
-		turnLength, err := p.getTurnLength(chain, header)
+		turnLength, err := p.getTurnLength(p.callContract, chain, header)
 		if err != nil {
 			return err
 		}
@@ -1293,6 +1348,9 @@ func (p *Parlia) distributeFinalityReward(chain consensus.ChainHeaderReader, sta
 		if head == nil {
 			return fmt.Errorf("header is nil at height %d", height)
 		}
+		if len(head.Extra) <= extraVanity+extraSeal {
+			continue // no vote attestation
+		}
 		epochLength, err := p.epochLength(chain, head, nil)
 		if err != nil {
 			return err
@@ -1915,36 +1973,43 @@ func (p *Parlia) Close() error {
 
 // ==========================  interaction with contract/account =========
 
-// getCurrentValidators get current validators
-func (p *Parlia) getCurrentValidators(blockHash common.Hash, blockNum *big.Int) ([]common.Address, map[common.Address]*types.BLSPublicKey, error) {
-	// block
+// contractCaller executes a read-only contract call on top of the state of the
+// given block, returning the call output.
+type contractCaller func(blockHash common.Hash, to common.Address, data []byte) ([]byte, error)
+
+// callContract is the default contractCaller, executing the call through the
+// RPC API.
+func (p *Parlia) callContract(blockHash common.Hash, to common.Address, data []byte) ([]byte, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel() // cancel when we are finished consuming integers
+
 	blockNr := rpc.BlockNumberOrHashWithHash(blockHash, false)
+	msgData := (hexutil.Bytes)(data)
+	gas := (hexutil.Uint64)(uint64(math.MaxUint64 / 2))
+	return p.ethAPI.Call(ctx, ethapi.TransactionArgs{
+		Gas:  &gas,
+		To:   &to,
+		Data: &msgData,
+	}, &blockNr, nil, nil)
+}
 
+// getCurrentValidators get current validators
+func (p *Parlia) getCurrentValidators(call contractCaller, blockHash common.Hash, blockNum *big.Int) ([]common.Address, map[common.Address]*types.BLSPublicKey, error) {
 	if !p.chainConfig.IsLuban(blockNum) {
-		validators, err := p.getCurrentValidatorsBeforeLuban(blockHash, blockNum)
+		validators, err := p.getCurrentValidatorsBeforeLuban(call, blockHash, blockNum)
 		return validators, nil, err
 	}
 
 	// method
 	method := "getMiningValidators"
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel() // cancel when we are finished consuming integers
-
 	data, err := p.validatorSetABI.Pack(method)
 	if err != nil {
 		log.Error("Unable to pack tx for getMiningValidators", "error", err)
 		return nil, nil, err
 	}
 	// call
-	msgData := (hexutil.Bytes)(data)
-	toAddress := common.HexToAddress(systemcontracts.ValidatorContract)
-	gas := (hexutil.Uint64)(uint64(math.MaxUint64 / 2))
-	result, err := p.ethAPI.Call(ctx, ethapi.TransactionArgs{
-		Gas:  &gas,
-		To:   &toAddress,
-		Data: &msgData,
-	}, &blockNr, nil, nil)
+	result, err := call(blockHash, common.HexToAddress(systemcontracts.ValidatorContract), data)
 	if err != nil {
 		return nil, nil, err
 	}
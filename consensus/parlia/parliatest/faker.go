@@ -0,0 +1,115 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package parliatest provides a Parlia engine for executing test fixtures.
+package parliatest
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// diffInTurn is the difficulty of an in-turn Parlia block.
+var diffInTurn = big.NewInt(2)
+
+// Faker is a Parlia engine executing the blocks of test fixtures. Fixtures are
+// not sealed by a validator set, so header and seal verification is skipped and
+// blocks are finalized with FinalizeTransition. Votes are not tracked either,
+// hence the genesis block is always reported as justified and finalized.
+type Faker struct {
+	*parlia.Parlia
+}
+
+// NewFaker creates a Parlia engine for executing test fixtures.
+func NewFaker(chainConfig *params.ChainConfig) *Faker {
+	return &Faker{Parlia: parlia.New(chainConfig, rawdb.NewMemoryDatabase(), nil, common.Hash{})}
+}
+
+// VerifyHeader implements consensus.Engine, accepting any header.
+func (f *Faker) VerifyHeader(chain consensus.ChainHeaderReader, header *types.Header) error {
+	return nil
+}
+
+// VerifyHeaders implements consensus.Engine, accepting any headers.
+func (f *Faker) VerifyHeaders(chain consensus.ChainHeaderReader, headers []*types.Header) (chan<- struct{}, <-chan error) {
+	abort, results := make(chan struct{}), make(chan error, len(headers))
+	for range headers {
+		results <- nil
+	}
+	return abort, results
+}
+
+// CalcDifficulty implements consensus.Engine, treating every block as in-turn.
+func (f *Faker) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64, parent *types.Header) *big.Int {
+	return new(big.Int).Set(diffInTurn)
+}
+
+// Finalize implements consensus.Engine, applying the end of block processing
+// derivable without validator snapshots.
+func (f *Faker) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state vm.StateDB, txs *[]*types.Transaction,
+	uncles []*types.Header, _ []*types.Withdrawal, receipts *[]*types.Receipt, systemTxs *[]*types.Transaction, usedGas *uint64, tracer *tracing.Hooks) error {
+	parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return errors.New("parent not found")
+	}
+	return f.FinalizeTransition(chain, header, parent.Time, state, txs, receipts, systemTxs, usedGas, nil, tracer)
+}
+
+// FinalizeAndAssemble implements consensus.Engine, finalizing the block and
+// assembling it with the created system transactions.
+func (f *Faker) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB,
+	body *types.Body, receipts []*types.Receipt, tracer *tracing.Hooks) (*types.Block, []*types.Receipt, error) {
+	parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, nil, errors.New("parent not found")
+	}
+	txs := body.Transactions
+	if err := f.FinalizeTransition(chain, header, parent.Time, state, &txs, &receipts, nil, &header.GasUsed, nil, tracer); err != nil {
+		return nil, nil, err
+	}
+	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
+	header.UncleHash = types.EmptyUncleHash
+
+	block := types.NewBlock(header, &types.Body{Transactions: txs, Withdrawals: body.Withdrawals}, receipts, trie.NewStackTrie(nil))
+	return block, receipts, nil
+}
+
+// GetJustifiedNumberAndHash implements consensus.PoSA, always reporting the
+// genesis block as justified.
+func (f *Faker) GetJustifiedNumberAndHash(chain consensus.ChainHeaderReader, headers []*types.Header) (uint64, common.Hash, error) {
+	genesis := chain.GetHeaderByNumber(0)
+	if genesis == nil {
+		return 0, common.Hash{}, errors.New("genesis not found")
+	}
+	return 0, genesis.Hash(), nil
+}
+
+// GetFinalizedHeader implements consensus.PoSA, always reporting the genesis
+// block as finalized.
+func (f *Faker) GetFinalizedHeader(chain consensus.ChainHeaderReader, header *types.Header) *types.Header {
+	return chain.GetHeaderByNumber(0)
+}
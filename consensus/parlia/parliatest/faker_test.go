@@ -0,0 +1,145 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package parliatest
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+)
+
+// Tests that blocks assembled by the faker carry the system transactions
+// distributing the fees, and that importing them checks these transactions.
+func TestFakerSystemTransactions(t *testing.T) {
+	var (
+		config    = params.ParliaTestChainConfig
+		signer    = types.LatestSigner(config)
+		valKey, _ = crypto.GenerateKey()
+		validator = crypto.PubkeyToAddress(valKey.PublicKey)
+		gspec     = &core.Genesis{
+			Config: config,
+			Alloc:  types.GenesisAlloc{testAddr: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	engine := NewFaker(config)
+	engine.Authorize(validator, nil, func(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+		return types.SignTx(tx, signer, valKey)
+	})
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 3, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(validator)
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(testAddr), common.Address{0xde, 0xad}, common.Big1, params.TxGas, big.NewInt(params.GWei), nil), signer, testKey)
+		gen.AddTx(tx)
+	})
+	for _, block := range blocks {
+		var system int
+		for _, tx := range block.Transactions() {
+			if ok, _ := engine.IsSystemTransaction(tx, block.Header()); ok {
+				system++
+			}
+		}
+		// Block 1 initializes the genesis system contracts on top
+		if want := 1; block.NumberU64() > 1 && system != want {
+			t.Errorf("block %d: system transaction count mismatch: have %d, want %d", block.NumberU64(), system, want)
+		}
+	}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), gspec, NewFaker(config), nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import blocks: %v", err)
+	}
+	state, _ := chain.State()
+	if balance := state.GetBalance(common.HexToAddress(systemcontracts.ValidatorContract)); balance.Uint64() != 3*params.TxGas*params.GWei {
+		t.Errorf("validator contract balance mismatch: have %v, want %d", balance, 3*params.TxGas*params.GWei)
+	}
+	// Blocks lacking the system transactions must be rejected
+	chain, _ = core.NewBlockChain(rawdb.NewMemoryDatabase(), gspec, NewFaker(config), nil)
+	defer chain.Stop()
+
+	stripped := blocks[0].WithBody(types.Body{Transactions: blocks[0].Transactions()[:1]})
+	if _, err := chain.InsertChain(types.Blocks{stripped}); err == nil {
+		t.Fatal("block without system transactions imported")
+	}
+}
+
+// Tests that the finality reward is distributed every 200 blocks, alongside the
+// fees of the block.
+func TestFakerFinalityReward(t *testing.T) {
+	var (
+		config    = params.ParliaTestChainConfig
+		signer    = types.LatestSigner(config)
+		valKey, _ = crypto.GenerateKey()
+		validator = crypto.PubkeyToAddress(valKey.PublicKey)
+		gspec     = &core.Genesis{
+			Config: config,
+			Alloc:  types.GenesisAlloc{testAddr: {Balance: big.NewInt(params.Ether)}},
+		}
+		deposit        = crypto.Keccak256([]byte("deposit(address)"))[:4]
+		finalityReward = crypto.Keccak256([]byte("distributeFinalityReward(address[],uint256[])"))[:4]
+	)
+	engine := NewFaker(config)
+	engine.Authorize(validator, nil, func(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+		return types.SignTx(tx, signer, valKey)
+	})
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 201, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(validator)
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(testAddr), common.Address{0xde, 0xad}, common.Big1, params.TxGas, big.NewInt(params.GWei), nil), signer, testKey)
+		gen.AddTx(tx)
+	})
+	for _, block := range blocks[len(blocks)-3:] {
+		var calls [][]byte
+		for _, tx := range block.Transactions() {
+			if ok, _ := engine.IsSystemTransaction(tx, block.Header()); ok {
+				calls = append(calls, tx.Data()[:4])
+			}
+		}
+		want := [][]byte{deposit}
+		if block.NumberU64() == 200 {
+			want = append(want, finalityReward)
+		}
+		if !slices.EqualFunc(calls, want, bytes.Equal) {
+			t.Errorf("block %d: system calls mismatch: have %x, want %x", block.NumberU64(), calls, want)
+		}
+	}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), gspec, NewFaker(config), nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import blocks: %v", err)
+	}
+}
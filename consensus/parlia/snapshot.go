@@ -470,6 +470,20 @@ func (s *Snapshot) inturnValidator() common.Address {
 	return validators[offset]
 }
 
+// inturnValidators returns the in-turn validators of the following n turns,
+// starting with the validator for the following block height.
+func (s *Snapshot) inturnValidators(n int) []common.Address {
+	validators := s.validators()
+	n = min(n, len(validators))
+	turn := (s.Number + 1) / uint64(s.TurnLength)
+
+	list := make([]common.Address, 0, n)
+	for i := 0; i < n; i++ {
+		list = append(list, validators[(turn+uint64(i))%uint64(len(validators))])
+	}
+	return list
+}
+
 func (s *Snapshot) nexValidatorsChangeBlock() uint64 {
 	epochLength := s.EpochLength
 	currentEpoch := s.Number - s.Number%epochLength
@@ -0,0 +1,71 @@
+package parlia
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// FinalizeTransition applies the end of block processing of a Parlia block to
+// an isolated state transition, where validator snapshots are not available,
+// e.g. in the evm t8n tool or when executing test fixtures. It mirrors Finalize
+// for everything derived from the header, the ancestor headers and the state:
+// the built-in system contract upgrades, the contract initialisations at block
+// 1 and at the Feynman fork, the slashing of the given spoiled validator and
+// the distribution of the block and finality rewards. The finality reward
+// requires the ancestors of the block to be served by the chain, and snapshots
+// only if they carry vote attestations. The daily validator set update depends
+// on the validator contracts of a live chain and is not applied.
+//
+// If systemTxs is nil, the system transactions are created and appended to txs,
+// signed if the engine is authorized with the coinbase key. Otherwise the given
+// system transactions are verified and consumed like during block import.
+func (p *Parlia) FinalizeTransition(chain consensus.ChainHeaderReader, header *types.Header, parentTime uint64, state vm.StateDB, txs *[]*types.Transaction,
+	receipts *[]*types.Receipt, systemTxs *[]*types.Transaction, usedGas *uint64, spoiledVal *common.Address, tracer *tracing.Hooks) error {
+	cx := chainContext{ChainHeaderReader: chain, parlia: p}
+	mode := systemTxImporting
+	if systemTxs == nil {
+		p.lock.RLock()
+		mode = systemTxPacking
+		if p.signTxFn != nil && p.val == header.Coinbase {
+			mode = systemTxMining
+		}
+		p.lock.RUnlock()
+	}
+	systemcontracts.TryUpdateBuildInSystemContract(p.chainConfig, header.Number, parentTime, header.Time, state, false)
+
+	if p.chainConfig.IsOnFeynman(header.Number, parentTime, header.Time) {
+		if err := p.initializeFeynmanContract(state, header, cx, txs, receipts, systemTxs, usedGas, mode, tracer); err != nil {
+			return fmt.Errorf("init feynman contract failed: %v", err)
+		}
+	}
+	if header.Number.Cmp(common.Big1) == 0 {
+		if err := p.initContract(state, header, cx, txs, receipts, systemTxs, usedGas, mode, tracer); err != nil {
+			return fmt.Errorf("init contract failed: %v", err)
+		}
+	}
+	if spoiledVal != nil {
+		if err := p.slash(*spoiledVal, state, header, cx, txs, receipts, systemTxs, usedGas, mode, tracer); err != nil {
+			log.Error("slash validator failed", "number", header.Number, "address", *spoiledVal, "err", err)
+		}
+	}
+	if err := p.distributeIncoming(header.Coinbase, state, header, cx, txs, receipts, systemTxs, usedGas, mode, tracer); err != nil {
+		return err
+	}
+	if p.chainConfig.IsPlato(header.Number) {
+		if err := p.distributeFinalityReward(chain, state, header, cx, txs, receipts, systemTxs, usedGas, mode, tracer); err != nil {
+			return err
+		}
+	}
+	if systemTxs != nil && len(*systemTxs) > 0 {
+		return errors.New("the length of systemTxs do not match")
+	}
+	return nil
+}
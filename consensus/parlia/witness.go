@@ -0,0 +1,97 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package parlia
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ExtendWitness implements core.WitnessExtender. The validator set checks of
+// epoch blocks and the validator election of breathe blocks read the parent
+// state through contract calls made via the RPC API, outside of the block
+// execution. Repeating the same calls on the witness tracking parent state
+// makes the witness cover them, so stateless nodes can repeat the checks.
+func (p *Parlia) ExtendWitness(chain consensus.ChainHeaderReader, header *types.Header, parentState *state.StateDB) error {
+	parent := chain.GetHeaderByHash(header.ParentHash)
+	if parent == nil {
+		return errors.New("parent not found")
+	}
+	epochLength, err := p.epochLength(chain, header, nil)
+	if err != nil {
+		return err
+	}
+	call := p.stateCaller(chain, parent, parentState)
+
+	if header.Number.Uint64()%epochLength == 0 {
+		// Mirror verifyValidators and verifyTurnLength
+		if _, _, err := p.getCurrentValidators(call, parent.Hash(), parent.Number); err != nil {
+			return err
+		}
+		if p.chainConfig.IsBohr(header.Number, header.Time) {
+			if _, err := p.getTurnLength(call, chain, header); err != nil {
+				return err
+			}
+		}
+	}
+	if p.chainConfig.IsFeynman(header.Number, header.Time) && isBreatheBlock(parent.Time, header.Time) &&
+		!p.chainConfig.IsOnFeynman(header.Number, parent.Time, header.Time) {
+		// Mirror updateValidatorSetV2
+		if _, _, _, err := p.electValidators(call, parent.Hash()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stateCaller returns a contractCaller executing the calls on the given state
+// of the parent block the way eth_call does, touching the same accounts.
+func (p *Parlia) stateCaller(chain consensus.ChainHeaderReader, parent *types.Header, statedb *state.StateDB) contractCaller {
+	return func(blockHash common.Hash, to common.Address, data []byte) ([]byte, error) {
+		if blockHash != parent.Hash() {
+			return nil, fmt.Errorf("call on block %x, state of %x", blockHash, parent.Hash())
+		}
+		context := core.NewEVMBlockContext(parent, chainContext{ChainHeaderReader: chain, parlia: p}, nil)
+		context.BaseFee = new(big.Int)
+		evm := vm.NewEVM(context, statedb, p.chainConfig, vm.Config{NoBaseFee: true})
+
+		msg := p.getSystemMessage(common.Address{}, to, data, new(big.Int))
+		msg.GasFeeCap, msg.GasTipCap = new(big.Int), new(big.Int)
+		msg.SkipNonceChecks, msg.SkipTransactionChecks = true, true
+
+		result, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+		if err := statedb.Error(); err != nil {
+			return nil, err
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := result.Err; err != nil {
+			return nil, err
+		}
+		return result.Return(), nil
+	}
+}
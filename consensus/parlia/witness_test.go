@@ -0,0 +1,43 @@
+package parlia
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the witness extension reads the system contracts through the same
+// code paths as the consensus checks, only executed on the given state.
+func TestStateCaller(t *testing.T) {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	// getTurnLength returns 4 regardless of the input
+	statedb.SetCode(common.HexToAddress(systemcontracts.ValidatorContract), common.FromHex("0x600460005260206000f3"), tracing.CodeChangeUnspecified)
+
+	engine := New(params.ParliaTestChainConfig, rawdb.NewMemoryDatabase(), nil, common.Hash{})
+	parent := &types.Header{Number: big.NewInt(1), Difficulty: diffInTurn, GasLimit: params.GenesisGasLimit, ExcessBlobGas: new(uint64)}
+	chain := &finalizedHeaderChain{
+		cfg:      params.ParliaTestChainConfig,
+		current:  parent,
+		byHash:   map[common.Hash]*types.Header{parent.Hash(): parent},
+		byNumber: map[uint64]*types.Header{1: parent},
+	}
+	call := engine.stateCaller(chain, parent, statedb)
+
+	turnLength, err := engine.getTurnLengthFromContract(call, parent)
+	if err != nil {
+		t.Fatalf("failed to get turn length: %v", err)
+	}
+	if turnLength.Uint64() != 4 {
+		t.Errorf("turn length mismatch: have %v, want 4", turnLength)
+	}
+	if _, err := engine.getTurnLengthFromContract(call, &types.Header{Number: big.NewInt(2)}); err == nil {
+		t.Error("call on a foreign block succeeded")
+	}
+}
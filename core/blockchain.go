@@ -243,6 +243,7 @@ type BlockChainConfig struct {
 	// Execution configs
 	StatelessSelfValidation bool // Generate execution witnesses and self-check against them (testing purpose)
 	EnableWitnessStats      bool // Whether trie access statistics collection is enabled
+	StatelessVerify         bool // Import blocks by executing them on top of fetched witnesses, keeping no state
 }
 
 // DefaultConfig returns the default config.
@@ -409,6 +410,9 @@ type BlockChain struct {
 	// future blocks are blocks added for later processing
 	futureBlocks *lru.Cache[common.Hash, *types.Block]
 
+	witnessFetcher atomic.Pointer[WitnessFetcher]          // Source of the witnesses in stateless mode
+	witnessStates  *lru.Cache[common.Hash, state.Database] // Witness backed states of recent blocks in stateless mode
+
 	wg            sync.WaitGroup
 	quit          chan struct{} // shutdown signal, closed in Stop.
 	stopping      atomic.Bool   // false if chain is running, true when stopped
@@ -504,6 +508,7 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 		blockStatsCache: lru.NewCache[common.Hash, *BlockStats](blockCacheLimit),
 		txLookupCache:   lru.NewCache[common.Hash, txLookup](txLookupCacheLimit),
 		futureBlocks:    lru.NewCache[common.Hash, *types.Block](maxFutureBlocks),
+		witnessStates:   lru.NewCache[common.Hash, state.Database](witnessStatesLimit),
 		engine:          engine,
 		logger:          cfg.VmConfig.Tracer,
 	}
@@ -544,8 +549,9 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	}
 	// Make sure the state associated with the block is available, or log out
 	// if there is no available state, waiting for state sync.
+	// Stateless nodes keep no state to repair, the witnesses replace it.
 	head := bc.CurrentBlock()
-	if !bc.HasState(head.Root) {
+	if !bc.Stateless() && !bc.HasState(head.Root) {
 		if head.Number.Uint64() == 0 {
 			// The genesis state is missing, which is only possible in the path-based
 			// scheme. This situation occurs when the initial state sync is not finished
@@ -1152,6 +1158,10 @@ func (bc *BlockChain) rewindPathHead(head *types.Header, root common.Hash) (*typ
 // then block number zero is returned, indicating that snapshot recovery is disabled
 // and the whole snapshot should be auto-generated in case of head mismatch.
 func (bc *BlockChain) rewindHead(head *types.Header, root common.Hash) (*types.Header, uint64) {
+	// Stateless nodes need no state to resume from, any block will do
+	if bc.Stateless() {
+		return head, 0
+	}
 	if bc.triedb.Scheme() == rawdb.PathScheme && !bc.NoTries() {
 		return bc.rewindPathHead(head, root)
 	}
@@ -1573,7 +1583,7 @@ func (bc *BlockChain) Stop() {
 		}
 		bc.snaps.Release()
 	}
-	if !bc.NoTries() {
+	if !bc.NoTries() && !bc.Stateless() {
 		if bc.triedb.Scheme() == rawdb.PathScheme {
 			// Ensure that the in-memory trie nodes are journaled to disk properly.
 			if err := bc.triedb.Journal(bc.CurrentBlock().Root); err != nil {
@@ -1974,6 +1984,12 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	if err != nil {
 		return err
 	}
+	// In stateless mode the state was committed into the witness database, keep
+	// it around for a while to serve the recent state, but don't persist it.
+	if bc.Stateless() {
+		bc.witnessStates.Add(root, statedb.Database())
+		return nil
+	}
 	// Emit the state update to the state sizestats if it's active
 	if bc.stateSizer != nil {
 		bc.stateSizer.Notify(stateUpdate)
@@ -2557,7 +2573,13 @@ func (bc *BlockChain) ProcessBlock(ctx context.Context, parentRoot common.Hash,
 
 	needBadSharedStorage := bc.chainConfig.NeedBadSharedStorage(block.Number())
 	needPrefetch := needBadSharedStorage || (!bc.cfg.NoPrefetch && len(block.Transactions()) >= prefetchTxNumber)
-	if !needPrefetch {
+	if bc.Stateless() {
+		// No local state to execute on or to prefetch from, use the witness
+		statedb, err = bc.statelessState(parentRoot, block)
+		if err != nil {
+			return nil, err
+		}
+	} else if !needPrefetch {
 		statedb, err = state.New(parentRoot, bc.statedb)
 		if err != nil {
 			return nil, err
@@ -431,6 +431,10 @@ func (bc *BlockChain) GetTd(hash common.Hash, number uint64) *big.Int {
 
 // HasState checks if state trie is fully present in the database or not.
 func (bc *BlockChain) HasState(hash common.Hash) bool {
+	// Stateless nodes only hold the witness backed states of recent blocks
+	if bc.Stateless() && bc.witnessStates.Contains(hash) {
+		return true
+	}
 	if bc.NoTries() {
 		if bc.snaps != nil {
 			return bc.snaps.Snapshot(hash) != nil
@@ -485,6 +489,11 @@ func (bc *BlockChain) State() (*state.StateDB, error) {
 
 // StateAt returns a new mutable state based on a particular point in time.
 func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	if bc.Stateless() {
+		if db, ok := bc.witnessStates.Get(root); ok {
+			return state.New(root, db)
+		}
+	}
 	stateDb, err := state.New(root, bc.statedb)
 	if err != nil {
 		return nil, err
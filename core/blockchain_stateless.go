@@ -0,0 +1,150 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// witnessStatesLimit is the number of witness backed states kept around in the
+// stateless mode, making the state of the recently imported blocks accessible
+// to the consensus engine and the RPC API.
+const witnessStatesLimit = 16
+
+var (
+	errNoWitnessFetcher = errors.New("no witness fetcher")
+	errInvalidWitness   = errors.New("witness does not belong to the block")
+	errStatelessMode    = errors.New("state unavailable in stateless mode")
+)
+
+// WitnessFetcher retrieves the execution witness of a block, used by the
+// stateless mode in place of the local state to import blocks.
+type WitnessFetcher func(block *types.Block) (*stateless.Witness, error)
+
+// WitnessExtender is implemented by the consensus engines which read the parent
+// state outside of the block execution when finalizing a block, e.g. through
+// contract calls made via the RPC API. Such reads need to be replayed on the
+// given witness tracking state for the witness to cover them.
+type WitnessExtender interface {
+	ExtendWitness(chain consensus.ChainHeaderReader, header *types.Header, parent *state.StateDB) error
+}
+
+// Stateless reports whether the chain runs in the stateless mode, importing the
+// blocks by executing them on top of witnesses instead of the local state.
+func (bc *BlockChain) Stateless() bool {
+	return bc.cfg.StatelessVerify
+}
+
+// SetWitnessFetcher sets the source of the witnesses in the stateless mode.
+func (bc *BlockChain) SetWitnessFetcher(fetcher WitnessFetcher) {
+	bc.witnessFetcher.Store(&fetcher)
+}
+
+// hasBlockAndState is HasBlockAndState as seen by the block import. Stateless
+// nodes execute every block on top of its witness instead of the parent state,
+// so any imported block is as good as one with state.
+func (bc *BlockChain) hasBlockAndState(hash common.Hash, number uint64) bool {
+	if bc.Stateless() {
+		return bc.HasBlock(hash, number)
+	}
+	return bc.HasBlockAndState(hash, number)
+}
+
+// BuildWitness executes the block on top of its parent state, collecting the
+// execution witness a stateless node needs to verify it. Unlike the import, the
+// execution is neither traced nor accounted in the chain metrics.
+func (bc *BlockChain) BuildWitness(block *types.Block) (*stateless.Witness, error) {
+	if bc.Stateless() {
+		return nil, errStatelessMode
+	}
+	if !bc.chainConfig.IsByzantium(block.Number()) {
+		return nil, fmt.Errorf("witness unavailable for pre-Byzantium block %d", block.NumberU64())
+	}
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	witness, err := stateless.NewWitness(block.Header(), bc)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := state.New(parent.Root, bc.statedb)
+	if err != nil {
+		return nil, err
+	}
+	statedb.StartPrefetcher("witness", witness, nil)
+	defer statedb.StopPrefetcher()
+
+	statedb.SetExpectedStateRoot(block.Root())
+	statedb.SetNeedBadSharedStorage(bc.chainConfig.NeedBadSharedStorage(block.Number()))
+
+	vmConfig := bc.cfg.VmConfig
+	vmConfig.Tracer = nil
+
+	res, err := bc.processor.Process(block, statedb, vmConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := bc.validator.ValidateState(block, statedb, res, false); err != nil {
+		return nil, err
+	}
+	if extender, ok := bc.engine.(WitnessExtender); ok {
+		statedb, err := state.New(parent.Root, bc.statedb)
+		if err != nil {
+			return nil, err
+		}
+		statedb.StartPrefetcher("witness", witness, nil)
+		defer statedb.StopPrefetcher()
+
+		if err := extender.ExtendWitness(bc, block.Header(), statedb); err != nil {
+			return nil, err
+		}
+		// Hashing the state gathers the trie nodes accessed into the witness
+		statedb.IntermediateRoot(bc.chainConfig.IsEIP158(parent.Number))
+	}
+	return witness, nil
+}
+
+// statelessState fetches the witness of the block and opens its parent state
+// from it, used in place of the local state in the stateless mode.
+func (bc *BlockChain) statelessState(parentRoot common.Hash, block *types.Block) (*state.StateDB, error) {
+	fetcher := bc.witnessFetcher.Load()
+	if fetcher == nil {
+		return nil, errNoWitnessFetcher
+	}
+	witness, err := (*fetcher)(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch witness of block %d: %w", block.NumberU64(), err)
+	}
+	// The parent header anchors the pre-state root of the witness, everything
+	// else is keyed by hash and thus self-validating.
+	if len(witness.Headers) == 0 || witness.Headers[0].Hash() != block.ParentHash() {
+		return nil, errInvalidWitness
+	}
+	db := state.NewDatabase(triedb.NewDatabase(witness.MakeHashDB(), triedb.HashDefaults), nil)
+	bc.witnessStates.Add(parentRoot, db)
+
+	return state.New(parentRoot, db)
+}
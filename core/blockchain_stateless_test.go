@@ -0,0 +1,139 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that a stateless chain imports blocks by executing them on top of the
+// witnesses built by a full chain, ending up with the same head.
+func TestStatelessImport(t *testing.T) {
+	var (
+		key, _       = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr         = crypto.PubkeyToAddress(key.PublicKey)
+		recv         = common.HexToAddress("0xdeadbeef")
+		signer       = types.LatestSigner(params.TestChainConfig)
+		funds        = big.NewInt(params.Ether)
+		gspec        = &Genesis{Config: params.TestChainConfig, Alloc: types.GenesisAlloc{addr: {Balance: funds}}}
+		_, blocks, _ = GenerateChainWithGenesis(gspec, ethash.NewFaker(), 8, func(i int, gen *BlockGen) {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), recv, big.NewInt(1000), params.TxGas, gen.header.BaseFee, nil), signer, key)
+			gen.AddTx(tx)
+		})
+	)
+	full, _ := NewBlockChain(rawdb.NewMemoryDatabase(), gspec, ethash.NewFaker(), DefaultConfig())
+	defer full.Stop()
+	if _, err := full.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	newStatelessWithDB := func(db ethdb.Database, fetcher WitnessFetcher) *BlockChain {
+		config := DefaultConfig()
+		config.StatelessVerify = true
+		config.SnapshotLimit = 0
+
+		chain, err := NewBlockChain(db, gspec, ethash.NewFaker(), config)
+		if err != nil {
+			t.Fatalf("failed to create stateless chain: %v", err)
+		}
+		chain.SetWitnessFetcher(fetcher)
+		return chain
+	}
+	newStateless := func(fetcher WitnessFetcher) *BlockChain {
+		return newStatelessWithDB(rawdb.NewMemoryDatabase(), fetcher)
+	}
+	// Import the chain with the correct witnesses
+	chain := newStateless(func(block *types.Block) (*stateless.Witness, error) {
+		return full.BuildWitness(block)
+	})
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain statelessly: %v", err)
+	}
+	if have, want := chain.CurrentBlock().Hash(), full.CurrentBlock().Hash(); have != want {
+		t.Fatalf("head mismatch: have %x, want %x", have, want)
+	}
+	// The state of the recent blocks is accessible as far as the witnesses go
+	statedb, err := chain.StateAt(chain.CurrentBlock().Root)
+	if err != nil {
+		t.Fatalf("failed to open head state: %v", err)
+	}
+	if have := statedb.GetBalance(recv).ToBig(); have.Cmp(big.NewInt(8000)) != 0 {
+		t.Errorf("balance mismatch: have %v, want 8000", have)
+	}
+	if !chain.HasState(chain.CurrentBlock().Root) {
+		t.Error("head state missing")
+	}
+	if chain.HasState(common.Hash{0x01}) {
+		t.Error("unknown state reported present")
+	}
+	if _, err := chain.BuildWitness(blocks[0]); err == nil {
+		t.Error("stateless chain built a witness")
+	}
+	// Witnesses missing state or belonging to other blocks are rejected
+	incomplete := newStateless(func(block *types.Block) (*stateless.Witness, error) {
+		witness, err := full.BuildWitness(block)
+		if err != nil {
+			return nil, err
+		}
+		witness.State = make(map[string]struct{})
+		return witness, nil
+	})
+	defer incomplete.Stop()
+	if _, err := incomplete.InsertChain(blocks[:1]); err == nil {
+		t.Error("block imported with incomplete witness")
+	}
+	mismatched := newStateless(func(block *types.Block) (*stateless.Witness, error) {
+		return full.BuildWitness(blocks[1])
+	})
+	defer mismatched.Stop()
+	if _, err := mismatched.InsertChain(blocks[:1]); !errors.Is(err, errInvalidWitness) {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidWitness)
+	}
+	// Restarted stateless chains keep their head without state and carry on
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		fetcher = func(block *types.Block) (*stateless.Witness, error) {
+			return full.BuildWitness(block)
+		}
+	)
+	restarted := newStatelessWithDB(db, fetcher)
+	if _, err := restarted.InsertChain(blocks[:4]); err != nil {
+		t.Fatalf("failed to insert chain statelessly: %v", err)
+	}
+	restarted.Stop()
+
+	restarted = newStatelessWithDB(db, fetcher)
+	defer restarted.Stop()
+	if have := restarted.CurrentBlock().Number.Uint64(); have != 4 {
+		t.Fatalf("head rewound after restart: have %d, want 4", have)
+	}
+	if _, err := restarted.InsertChain(blocks[4:]); err != nil {
+		t.Fatalf("failed to continue importing after restart: %v", err)
+	}
+}
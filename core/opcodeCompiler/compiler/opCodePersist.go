@@ -0,0 +1,212 @@
+package compiler
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// persistInterval is the interval at which the code call counters are flushed
+// to disk.
+const persistInterval = 5 * time.Minute
+
+// callSampleRate is the ratio of code calls counted. The counters only rank the
+// contracts to warm up on restart, sampling keeps them off the hot path.
+const callSampleRate = 16
+
+// persistentCache backs the in-memory code cache with a database, so that the
+// optimized form of the most called contracts survives restarts.
+type persistentCache struct {
+	db     ethdb.KeyValueStore
+	calls  sync.Map               // Code hash -> *atomic.Uint64 sampled calls since the last flush
+	totals map[common.Hash]uint64 // Persisted call counters, only accessed by the loop
+
+	closed bool
+	lock   sync.RWMutex // Protects the database from writes after closing
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+var persist atomic.Pointer[persistentCache]
+
+// EnablePersistentCache backs the optimized code cache with the given database.
+// Entries of previous optimizer versions are dropped, after which the code
+// excluded from optimization is restored and the optimized code and bitvecs of
+// the warm most called contracts are loaded into memory.
+func EnablePersistentCache(db ethdb.KeyValueStore, warm int) {
+	if deleted := rawdb.DeleteStaleOptimizedCode(db, OptimizerVersion); deleted > 0 {
+		log.Info("Dropped stale optimized code", "entries", deleted)
+	}
+	disabled := rawdb.ReadOptimizedCodeDisabled(db, OptimizerVersion)
+	for _, hash := range disabled {
+		disabledCodes.Store(hash, struct{}{})
+	}
+	totals := rawdb.ReadOptimizedCodeCalls(db, OptimizerVersion)
+
+	hashes := make([]common.Hash, 0, len(totals))
+	for hash := range totals {
+		hashes = append(hashes, hash)
+	}
+	slices.SortFunc(hashes, func(a, b common.Hash) int {
+		if totals[a] != totals[b] {
+			if totals[a] > totals[b] {
+				return -1
+			}
+			return 1
+		}
+		return a.Cmp(b)
+	})
+	var warmed int
+	for _, hash := range hashes {
+		if warmed >= warm {
+			break
+		}
+		if IsCodeOptimizationDisabled(hash) {
+			continue
+		}
+		code, bitvec := rawdb.ReadOptimizedCode(db, OptimizerVersion, hash)
+		if len(code) == 0 {
+			continue
+		}
+		codeCache.AddCodeCache(hash, code)
+		if len(bitvec) > 0 {
+			codeCache.AddBitvecCache(hash, bitvec)
+		}
+		warmed++
+	}
+	p := &persistentCache{
+		db:     db,
+		totals: totals,
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if old := persist.Swap(p); old != nil {
+		old.close()
+	}
+	go p.loop()
+
+	log.Info("Enabled persistent opcode cache", "version", OptimizerVersion, "contracts", len(totals), "warmed", warmed, "disabled", len(disabled))
+}
+
+// ClosePersistentCache flushes the call counters and detaches the database from
+// the optimized code cache. The database itself is not closed.
+func ClosePersistentCache() {
+	if p := persist.Swap(nil); p != nil {
+		p.close()
+	}
+}
+
+// close stops the flush loop and waits for the final flush to finish.
+func (p *persistentCache) close() {
+	close(p.quit)
+	<-p.done
+
+	p.lock.Lock()
+	p.closed = true
+	p.lock.Unlock()
+}
+
+// loop periodically flushes the call counters to disk.
+func (p *persistentCache) loop() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(persistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.flush()
+		case <-p.quit:
+			p.flush()
+			return
+		}
+	}
+}
+
+// flush adds the calls accumulated since the last flush to the persisted call
+// counters.
+func (p *persistentCache) flush() {
+	batch := p.db.NewBatch()
+	p.calls.Range(func(key, value any) bool {
+		// Counters are reset instead of deleted, so no concurrent call is lost
+		calls := value.(*atomic.Uint64).Swap(0)
+		if calls == 0 {
+			return true
+		}
+		hash := key.(common.Hash)
+		p.totals[hash] += calls
+		rawdb.WriteOptimizedCodeCalls(batch, OptimizerVersion, hash, p.totals[hash])
+		return true
+	})
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to flush optimized code calls", "err", err)
+	}
+}
+
+// called counts a call into the code with the given hash. Only one in every
+// callSampleRate calls is counted, weighted accordingly.
+func (p *persistentCache) called(hash common.Hash) {
+	if rand.Uint32N(callSampleRate) != 0 {
+		return
+	}
+	counter, ok := p.calls.Load(hash)
+	if !ok {
+		counter, _ = p.calls.LoadOrStore(hash, new(atomic.Uint64))
+	}
+	counter.(*atomic.Uint64).Add(callSampleRate)
+}
+
+// readCode retrieves the persisted optimized code with the given hash, along
+// with its jumpdest bitvec if known.
+func (p *persistentCache) readCode(hash common.Hash) ([]byte, []byte) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.closed {
+		return nil, nil
+	}
+	return rawdb.ReadOptimizedCode(p.db, OptimizerVersion, hash)
+}
+
+// writeCode persists the optimized code with the given hash. The bitvec is not
+// known yet, it is added once the code is first executed.
+func (p *persistentCache) writeCode(hash common.Hash, code []byte) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if !p.closed {
+		rawdb.WriteOptimizedCode(p.db, OptimizerVersion, hash, code, nil)
+	}
+}
+
+// writeBitvec persists the jumpdest bitvec together with the optimized code of
+// the given hash.
+func (p *persistentCache) writeBitvec(hash common.Hash, code []byte, bitvec []byte) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if !p.closed {
+		rawdb.WriteOptimizedCode(p.db, OptimizerVersion, hash, code, bitvec)
+	}
+}
+
+// disableCode removes all persisted data of the code with the given hash and
+// excludes it from optimization across restarts.
+func (p *persistentCache) disableCode(hash common.Hash) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if !p.closed {
+		rawdb.DeleteOptimizedCode(p.db, OptimizerVersion, hash)
+		rawdb.WriteOptimizedCodeDisabled(p.db, OptimizerVersion, hash)
+	}
+}
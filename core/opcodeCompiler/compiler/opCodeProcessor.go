@@ -3,14 +3,21 @@ package compiler
 import (
 	"errors"
 	"runtime"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 var (
 	enabled     bool
 	codeCache   *OpCodeCache
 	taskChannel chan optimizeTask
+
+	// disabledCodes is the set of code hashes which must never be executed in
+	// optimized form, e.g. because their optimized execution was found to
+	// diverge from the plain interpreter.
+	disabledCodes sync.Map
 )
 
 var (
@@ -70,10 +77,31 @@ func IsEnabled() bool {
 	return enabled
 }
 
+// DisableCodeOptimization permanently excludes the code with the given hash
+// from optimization, dropping any optimized code already cached for it. The
+// exclusion is persisted if the persistent cache is enabled.
+func DisableCodeOptimization(hash common.Hash) {
+	disabledCodes.Store(hash, struct{}{})
+	codeCache.RemoveCachedCode(hash)
+	if p := persist.Load(); p != nil {
+		p.disableCode(hash)
+	}
+}
+
+// IsCodeOptimizationDisabled returns whether the code with the given hash was
+// excluded from optimization.
+func IsCodeOptimizationDisabled(hash common.Hash) bool {
+	_, ok := disabledCodes.Load(hash)
+	return ok
+}
+
 func LoadOptimizedCode(hash common.Hash) []byte {
-	if !enabled {
+	if !enabled || IsCodeOptimizationDisabled(hash) {
 		return nil
 	}
+	if p := persist.Load(); p != nil {
+		p.called(hash)
+	}
 	processedCode := codeCache.GetCachedCode(hash)
 	return processedCode
 }
@@ -91,10 +119,15 @@ func StoreBitvec(codeHash common.Hash, bitvec []byte) {
 		return
 	}
 	codeCache.AddBitvecCache(codeHash, bitvec)
+	if p := persist.Load(); p != nil {
+		if code := codeCache.GetCachedCode(codeHash); len(code) > 0 {
+			p.writeBitvec(codeHash, code, bitvec)
+		}
+	}
 }
 
 func GenOrLoadOptimizedCode(hash common.Hash, code []byte) {
-	if !enabled {
+	if !enabled || IsCodeOptimizationDisabled(hash) {
 		return
 	}
 	task := optimizeTask{generate, hash, code}
@@ -120,7 +153,7 @@ func handleOptimizationTask(task optimizeTask) {
 
 // GenOrRewriteOptimizedCode generate the optimized code and refresh the code cache.
 func GenOrRewriteOptimizedCode(hash common.Hash, code []byte) ([]byte, error) {
-	if !enabled {
+	if !enabled || IsCodeOptimizationDisabled(hash) {
 		return nil, ErrOptimizedDisabled
 	}
 	processedCode, err := processByteCodes(code)
@@ -128,12 +161,29 @@ func GenOrRewriteOptimizedCode(hash common.Hash, code []byte) ([]byte, error) {
 		return nil, err
 	}
 	codeCache.AddCodeCache(hash, processedCode)
+	if p := persist.Load(); p != nil && crypto.Keccak256Hash(code) == hash {
+		p.writeCode(hash, processedCode)
+	}
 	return processedCode, err
 }
 
 func TryGenerateOptimizedCode(hash common.Hash, code []byte) ([]byte, error) {
 	processedCode := codeCache.GetCachedCode(hash)
 	var err error = nil
+	if len(processedCode) == 0 && !IsCodeOptimizationDisabled(hash) {
+		// Reuse the code optimized in an earlier run if available, making sure
+		// the entry is looked up by the hash of the code it was derived from
+		if p := persist.Load(); p != nil && crypto.Keccak256Hash(code) == hash {
+			var bitvec []byte
+			if processedCode, bitvec = p.readCode(hash); len(processedCode) > 0 {
+				codeCache.AddCodeCache(hash, processedCode)
+				if len(bitvec) > 0 {
+					codeCache.AddBitvecCache(hash, bitvec)
+				}
+				return processedCode, nil
+			}
+		}
+	}
 	if len(processedCode) == 0 {
 		processedCode, err = GenOrRewriteOptimizedCode(hash, code)
 	}
@@ -0,0 +1,81 @@
+package compiler
+
+import (
+	"encoding/binary"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// optimizedCodeEncoding is the revision of the optimized code encoding. It must
+// be bumped whenever the fusion changes without altering the rule table.
+const optimizedCodeEncoding = 1
+
+// fusionRules maps every fused instruction to the sequence of opcodes it fuses,
+// as applied by applyFusionPatterns. Immediates of PUSH opcodes are kept inline.
+// The table is checked against the actual fusion by the tests of core/vm, any
+// change to applyFusionPatterns must be reflected here to bump the version.
+var fusionRules = map[ByteCode][]ByteCode{
+	AndSwap1PopSwap2Swap1: {AND, SWAP1, POP, SWAP2, SWAP1},
+	Swap2Swap1PopJump:     {SWAP2, SWAP1, POP, JUMP},
+	Swap1PopSwap2Swap1:    {SWAP1, POP, SWAP2, SWAP1},
+	PopSwap2Swap1Pop:      {POP, SWAP2, SWAP1, POP},
+	Push2Jump:             {PUSH2, JUMP},
+	Push2JumpI:            {PUSH2, JUMPI},
+	Push1Push1:            {PUSH1, PUSH1},
+	Push1Add:              {PUSH1, ADD},
+	Push1Shl:              {PUSH1, SHL},
+	Push1Dup1:             {PUSH1, DUP1},
+	Swap1Pop:              {SWAP1, POP},
+	PopJump:               {POP, JUMP},
+	Pop2:                  {POP, POP},
+	Swap2Swap1:            {SWAP2, SWAP1},
+	Swap2Pop:              {SWAP2, POP},
+	Dup2LT:                {DUP2, LT},
+	JumpIfZero:            {ISZERO, PUSH2, JUMPI},
+	IsZeroPush2:           {ISZERO, PUSH2},
+	Dup2MStorePush1Add:    {DUP2, MSTORE, PUSH1, ADD},
+	Dup1Push4EqPush2:      {DUP1, PUSH4, EQ, PUSH2},
+	Push1CalldataloadPush1ShrDup1Push4GtPush2:      {PUSH1, CALLDATALOAD, PUSH1, SHR, DUP1, PUSH4, GT, PUSH2},
+	Push1Push1Push1SHLSub:                          {PUSH1, PUSH1, PUSH1, SHL, SUB},
+	AndDup2AddSwap1Dup2LT:                          {AND, DUP2, ADD, SWAP1, DUP2, LT},
+	Swap1Push1Dup1NotSwap2AddAndDup2AddSwap1Dup2LT: {SWAP1, PUSH1, DUP1, NOT, SWAP2, ADD, AND, DUP2, ADD, SWAP1, DUP2, LT},
+	Dup3And:                           {DUP3, AND},
+	Swap2Swap1Dup3SubSwap2Dup3GtPush2: {SWAP2, SWAP1, DUP3, SUB, SWAP2, DUP3, GT, PUSH2},
+	Swap1Dup2:                         {SWAP1, DUP2},
+	SHRSHRDup1MulDup1:                 {SHR, SHR, DUP1, MUL, DUP1},
+	Swap3PopPopPop:                    {SWAP3, POP, POP, POP},
+	SubSLTIsZeroPush2:                 {SUB, SLT, ISZERO, PUSH2},
+	Dup11MulDup3SubMulDup1:            {DUP11, MUL, DUP3, SUB, MUL, DUP1},
+}
+
+// FusionRule returns the sequence of opcodes fused into the given instruction,
+// or false if it is not a fused instruction.
+func FusionRule(op ByteCode) ([]ByteCode, bool) {
+	seq, ok := fusionRules[op]
+	return slices.Clone(seq), ok
+}
+
+// OptimizerVersion identifies the fusion rules and the encoding of optimized
+// code. It is derived from the rule table, and is part of the key of all
+// persisted optimized code, so entries of other rule sets are never loaded.
+var OptimizerVersion = optimizerVersion()
+
+// optimizerVersion hashes the encoding revision and the rule table, in opcode
+// order, into a version number.
+func optimizerVersion() uint32 {
+	ops := make([]ByteCode, 0, len(fusionRules))
+	for op := range fusionRules {
+		ops = append(ops, op)
+	}
+	slices.Sort(ops)
+
+	blob := []byte{optimizedCodeEncoding}
+	for _, op := range ops {
+		blob = append(blob, byte(op), byte(len(fusionRules[op])))
+		for _, code := range fusionRules[op] {
+			blob = append(blob, byte(code))
+		}
+	}
+	return binary.BigEndian.Uint32(crypto.Keccak256(blob))
+}
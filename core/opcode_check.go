@@ -0,0 +1,178 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/opcodeCompiler/compiler"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	opcodeCheckMeter    = metrics.NewRegisteredMeter("chain/opcode/check", nil)
+	opcodeMismatchMeter = metrics.NewRegisteredMeter("chain/opcode/mismatch", nil)
+)
+
+// OpcodeMismatch describes a divergence between the optimized and the plain
+// execution of a message.
+type OpcodeMismatch struct {
+	Field     string        // Execution output that diverged
+	Optimized string        // Output of the optimized interpreter
+	Plain     string        // Output of the plain interpreter
+	Codes     []common.Hash // Optimized code hashes taking part in the execution
+}
+
+func (m *OpcodeMismatch) Error() string {
+	return fmt.Sprintf("opcode optimization mismatch in %s: optimized %s, plain %s", m.Field, m.Optimized, m.Plain)
+}
+
+// opcodeRun is the outcome of executing a message on a shadow state.
+type opcodeRun struct {
+	result *ExecutionResult
+	err    error
+	logs   []*types.Log
+	root   common.Hash
+	codes  map[common.Hash][]byte // Code executed during the run, keyed by hash
+}
+
+// CheckOpcodeOptimization executes the message on two shadow copies of the
+// state, once with the plain and once with the optimized interpreter, and
+// compares the gas used, errors, return data, logs and resulting state.
+//
+// The code executed by the message is optimized synchronously beforehand, so
+// the check covers it even if the background optimizer did not get to it yet.
+// On a mismatch, optimization is disabled for all optimized code taking part
+// in the execution, so the canonical execution that follows is not affected.
+//
+// The check is skipped if the optimizer is not enabled.
+func CheckOpcodeOptimization(msg *Message, statedb *state.StateDB, blockCtx vm.BlockContext, config *params.ChainConfig, cfg vm.Config) *OpcodeMismatch {
+	if !compiler.IsEnabled() {
+		return nil
+	}
+	opcodeCheckMeter.Mark(1)
+
+	plain := runOpcodeCheck(msg, statedb, blockCtx, config, cfg, false)
+	for hash, code := range plain.codes {
+		compiler.TryGenerateOptimizedCode(hash, code)
+	}
+	optimized := runOpcodeCheck(msg, statedb, blockCtx, config, cfg, true)
+
+	mismatch := compareOpcodeRuns(optimized, plain)
+	if mismatch == nil {
+		return nil
+	}
+	for hash := range optimized.codes {
+		if compiler.LoadOptimizedCode(hash) != nil {
+			mismatch.Codes = append(mismatch.Codes, hash)
+		}
+	}
+	for _, hash := range mismatch.Codes {
+		compiler.DisableCodeOptimization(hash)
+	}
+	opcodeMismatchMeter.Mark(1)
+	return mismatch
+}
+
+// runOpcodeCheck executes the message on a copy of the state.
+func runOpcodeCheck(msg *Message, statedb *state.StateDB, blockCtx vm.BlockContext, config *params.ChainConfig, cfg vm.Config, optimize bool) *opcodeRun {
+	var (
+		db  = statedb.Copy()
+		run = &opcodeRun{codes: make(map[common.Hash][]byte)}
+	)
+	vmConfig := vm.Config{
+		NoBaseFee:                 cfg.NoBaseFee,
+		ExtraEips:                 cfg.ExtraEips,
+		EnableOpcodeOptimizations: optimize,
+		Tracer: &tracing.Hooks{
+			OnEnter: func(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+				switch vm.OpCode(typ) {
+				case vm.CREATE, vm.CREATE2:
+					return
+				}
+				if code := db.GetCode(to); len(code) > 0 {
+					run.codes[db.GetCodeHash(to)] = code
+				}
+			},
+		},
+	}
+	evm := vm.NewEVM(blockCtx, db, config, vmConfig)
+	evm.SetTxContext(NewEVMTxContext(msg))
+
+	run.result, run.err = ApplyMessage(evm, msg, new(GasPool).AddGas(math.MaxUint64))
+	run.logs = db.Logs()
+	run.root = db.IntermediateRoot(config.IsEIP158(blockCtx.BlockNumber))
+	return run
+}
+
+// compareOpcodeRuns returns the first difference between the two executions.
+func compareOpcodeRuns(optimized, plain *opcodeRun) *OpcodeMismatch {
+	if fmt.Sprint(optimized.err) != fmt.Sprint(plain.err) {
+		return &OpcodeMismatch{Field: "error", Optimized: fmt.Sprint(optimized.err), Plain: fmt.Sprint(plain.err)}
+	}
+	if optimized.result == nil || plain.result == nil {
+		return nil
+	}
+	if have, want := optimized.result.UsedGas, plain.result.UsedGas; have != want {
+		return &OpcodeMismatch{Field: "gas", Optimized: fmt.Sprint(have), Plain: fmt.Sprint(want)}
+	}
+	if have, want := executionOutcome(optimized.result.Err), executionOutcome(plain.result.Err); have != want {
+		return &OpcodeMismatch{Field: "execution outcome", Optimized: fmt.Sprintf("%s (%v)", have, optimized.result.Err), Plain: fmt.Sprintf("%s (%v)", want, plain.result.Err)}
+	}
+	if have, want := optimized.result.ReturnData, plain.result.ReturnData; !bytes.Equal(have, want) {
+		return &OpcodeMismatch{Field: "return data", Optimized: common.Bytes2Hex(have), Plain: common.Bytes2Hex(want)}
+	}
+	if have, want := logsHash(optimized.logs), logsHash(plain.logs); have != want {
+		return &OpcodeMismatch{Field: "logs", Optimized: have.Hex(), Plain: want.Hex()}
+	}
+	if have, want := optimized.root, plain.root; have != want {
+		return &OpcodeMismatch{Field: "state", Optimized: have.Hex(), Plain: want.Hex()}
+	}
+	return nil
+}
+
+// executionOutcome classifies an execution error by its consensus effect. The
+// error details, e.g. the stack depth of an underflow, are not consensus
+// critical and may legitimately differ between the interpreters.
+func executionOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, vm.ErrExecutionReverted):
+		return "reverted"
+	default:
+		return "failed"
+	}
+}
+
+// logsHash returns the hash of the consensus fields of a list of logs.
+func logsHash(logs []*types.Log) common.Hash {
+	enc, _ := rlp.EncodeToBytes(logs)
+	return crypto.Keccak256Hash(enc)
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// optimizedCodeEntry is the persisted form of an optimized code, stored together
+// with its jumpdest bitvec and a checksum of both.
+type optimizedCodeEntry struct {
+	Code     []byte
+	Bitvec   []byte
+	Checksum common.Hash
+}
+
+// optimizedCodeChecksum returns the checksum of an optimized code entry.
+func optimizedCodeChecksum(code, bitvec []byte) common.Hash {
+	return crypto.Keccak256Hash(code, bitvec)
+}
+
+// ReadOptimizedCode retrieves the optimized form of the code with the given hash,
+// as produced by the given optimizer version, along with its jumpdest bitvec if
+// already known. Nil is returned for entries failing the checksum.
+func ReadOptimizedCode(db ethdb.KeyValueReader, version uint32, hash common.Hash) ([]byte, []byte) {
+	data, _ := db.Get(optimizedCodeKey(OptimizedCodePrefix, version, hash))
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var entry optimizedCodeEntry
+	if err := rlp.DecodeBytes(data, &entry); err != nil {
+		log.Warn("Invalid optimized code entry", "hash", hash, "err", err)
+		return nil, nil
+	}
+	if optimizedCodeChecksum(entry.Code, entry.Bitvec) != entry.Checksum {
+		log.Warn("Corrupted optimized code entry", "hash", hash)
+		return nil, nil
+	}
+	return entry.Code, entry.Bitvec
+}
+
+// WriteOptimizedCode stores the optimized form of the code with the given hash,
+// along with its jumpdest bitvec if already known.
+func WriteOptimizedCode(db ethdb.KeyValueWriter, version uint32, hash common.Hash, code []byte, bitvec []byte) {
+	data, err := rlp.EncodeToBytes(&optimizedCodeEntry{
+		Code:     code,
+		Bitvec:   bitvec,
+		Checksum: optimizedCodeChecksum(code, bitvec),
+	})
+	if err != nil {
+		log.Crit("Failed to encode optimized code", "err", err)
+	}
+	if err := db.Put(optimizedCodeKey(OptimizedCodePrefix, version, hash), data); err != nil {
+		log.Crit("Failed to store optimized code", "err", err)
+	}
+}
+
+// ReadOptimizedCodeCalls retrieves the call counters of all optimized code of
+// the given optimizer version.
+func ReadOptimizedCodeCalls(db ethdb.Iteratee, version uint32) map[common.Hash]uint64 {
+	prefix := optimizedCodeKey(OptimizedCallsPrefix, version, common.Hash{})[:len(OptimizedCallsPrefix)+4]
+
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	calls := make(map[common.Hash]uint64)
+	for it.Next() {
+		if len(it.Key()) != len(prefix)+common.HashLength || len(it.Value()) != 8 {
+			continue
+		}
+		calls[common.BytesToHash(it.Key()[len(prefix):])] = binary.BigEndian.Uint64(it.Value())
+	}
+	return calls
+}
+
+// WriteOptimizedCodeCalls stores the call counter of the optimized code with the
+// given hash.
+func WriteOptimizedCodeCalls(db ethdb.KeyValueWriter, version uint32, hash common.Hash, calls uint64) {
+	if err := db.Put(optimizedCodeKey(OptimizedCallsPrefix, version, hash), binary.BigEndian.AppendUint64(nil, calls)); err != nil {
+		log.Crit("Failed to store optimized code calls", "err", err)
+	}
+}
+
+// ReadOptimizedCodeDisabled retrieves the hashes of all code excluded from the
+// given optimizer version.
+func ReadOptimizedCodeDisabled(db ethdb.Iteratee, version uint32) []common.Hash {
+	prefix := optimizedCodeKey(OptimizedDisabledPrefix, version, common.Hash{})[:len(OptimizedDisabledPrefix)+4]
+
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var hashes []common.Hash
+	for it.Next() {
+		if len(it.Key()) != len(prefix)+common.HashLength {
+			continue
+		}
+		hashes = append(hashes, common.BytesToHash(it.Key()[len(prefix):]))
+	}
+	return hashes
+}
+
+// WriteOptimizedCodeDisabled marks the code with the given hash as excluded from
+// the given optimizer version.
+func WriteOptimizedCodeDisabled(db ethdb.KeyValueWriter, version uint32, hash common.Hash) {
+	if err := db.Put(optimizedCodeKey(OptimizedDisabledPrefix, version, hash), nil); err != nil {
+		log.Crit("Failed to store disabled optimized code", "err", err)
+	}
+}
+
+// DeleteOptimizedCode removes all data of the optimized code with the given hash.
+func DeleteOptimizedCode(db ethdb.KeyValueWriter, version uint32, hash common.Hash) {
+	for _, prefix := range [][]byte{OptimizedCodePrefix, OptimizedCallsPrefix} {
+		if err := db.Delete(optimizedCodeKey(prefix, version, hash)); err != nil {
+			log.Crit("Failed to delete optimized code", "err", err)
+		}
+	}
+}
+
+// DeleteStaleOptimizedCode removes all optimized code produced by optimizer
+// versions other than the given one, returning the number of deleted entries.
+// Code excluded from an earlier version is reconsidered by the new one.
+func DeleteStaleOptimizedCode(db ethdb.KeyValueStore, version uint32) int {
+	var (
+		batch   = db.NewBatch()
+		current = binary.BigEndian.AppendUint32(nil, version)
+		deleted int
+	)
+	for _, prefix := range [][]byte{OptimizedCodePrefix, OptimizedCallsPrefix, OptimizedDisabledPrefix} {
+		it := db.NewIterator(prefix, nil)
+		for it.Next() {
+			if key := it.Key(); len(key) >= len(prefix)+4 && bytes.Equal(key[len(prefix):len(prefix)+4], current) {
+				continue
+			}
+			batch.Delete(common.CopyBytes(it.Key()))
+			deleted++
+
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					log.Crit("Failed to delete stale optimized code", "err", err)
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to delete stale optimized code", "err", err)
+	}
+	return deleted
+}
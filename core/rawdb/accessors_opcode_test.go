@@ -0,0 +1,99 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that optimized code of stale optimizer versions is purged, while the
+// entries of the current version are retained.
+func TestDeleteStaleOptimizedCode(t *testing.T) {
+	var (
+		db    = NewMemoryDatabase()
+		hashA = common.HexToHash("0xaa")
+		hashB = common.HexToHash("0xbb")
+	)
+	for version := uint32(1); version <= 3; version++ {
+		WriteOptimizedCode(db, version, hashA, []byte{byte(version)}, []byte{0xff})
+		WriteOptimizedCodeCalls(db, version, hashA, uint64(version))
+		WriteOptimizedCodeDisabled(db, version, hashB)
+	}
+	WriteOptimizedCodeCalls(db, 2, hashB, 7)
+
+	if deleted := DeleteStaleOptimizedCode(db, 2); deleted != 6 {
+		t.Fatalf("deleted entry count mismatch: have %d, want 6", deleted)
+	}
+	for _, version := range []uint32{1, 3} {
+		if code, _ := ReadOptimizedCode(db, version, hashA); code != nil {
+			t.Errorf("version %d: stale code retained: %x", version, code)
+		}
+		if calls := ReadOptimizedCodeCalls(db, version); len(calls) != 0 {
+			t.Errorf("version %d: stale calls retained: %v", version, calls)
+		}
+		if disabled := ReadOptimizedCodeDisabled(db, version); len(disabled) != 0 {
+			t.Errorf("version %d: stale exclusions retained: %v", version, disabled)
+		}
+	}
+	if disabled := ReadOptimizedCodeDisabled(db, 2); len(disabled) != 1 || disabled[0] != hashB {
+		t.Errorf("current exclusions mismatch: have %v", disabled)
+	}
+	code, bitvec := ReadOptimizedCode(db, 2, hashA)
+	if !bytes.Equal(code, []byte{2}) {
+		t.Errorf("current code mismatch: have %x, want 02", code)
+	}
+	if !bytes.Equal(bitvec, []byte{0xff}) {
+		t.Errorf("current bitvec mismatch: have %x, want ff", bitvec)
+	}
+	calls := ReadOptimizedCodeCalls(db, 2)
+	if len(calls) != 2 || calls[hashA] != 2 || calls[hashB] != 7 {
+		t.Errorf("current calls mismatch: have %v", calls)
+	}
+	DeleteOptimizedCode(db, 2, hashA)
+	if code, _ := ReadOptimizedCode(db, 2, hashA); code != nil {
+		t.Errorf("deleted code retained: %x", code)
+	}
+	if calls := ReadOptimizedCodeCalls(db, 2); len(calls) != 1 {
+		t.Errorf("deleted calls retained: %v", calls)
+	}
+}
+
+// Tests that corrupted optimized code entries are not loaded.
+func TestReadCorruptedOptimizedCode(t *testing.T) {
+	var (
+		db   = NewMemoryDatabase()
+		hash = common.HexToHash("0xaa")
+		key  = optimizedCodeKey(OptimizedCodePrefix, 1, hash)
+	)
+	WriteOptimizedCode(db, 1, hash, []byte{0x60, 0x01}, []byte{0x02})
+	if code, bitvec := ReadOptimizedCode(db, 1, hash); !bytes.Equal(code, []byte{0x60, 0x01}) || !bytes.Equal(bitvec, []byte{0x02}) {
+		t.Fatalf("entry mismatch: have %x/%x", code, bitvec)
+	}
+	data, _ := db.Get(key)
+	data[3] ^= 0xff // Flip a byte of the optimized code
+	db.Put(key, data)
+	if code, bitvec := ReadOptimizedCode(db, 1, hash); code != nil || bitvec != nil {
+		t.Errorf("corrupted entry loaded: %x/%x", code, bitvec)
+	}
+	db.Put(key, []byte{0x01})
+	if code, _ := ReadOptimizedCode(db, 1, hash); code != nil {
+		t.Errorf("malformed entry loaded: %x", code)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Roles of an address in the flat call traces of a block, stored as flags in
+// the trace filter address index.
+const (
+	TraceFilterFrom byte = 1 << iota // The address is the sender of a trace
+	TraceFilterTo                    // The address is the receiver of a trace
+)
+
+// ReadTraceFilterTail retrieves the number of the oldest block in the trace
+// filter index, or nil if the index is empty.
+func ReadTraceFilterTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(TraceFilterTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteTraceFilterTail stores the number of the oldest block in the trace
+// filter index.
+func WriteTraceFilterTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(TraceFilterTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store trace filter tail", "err", err)
+	}
+}
+
+// ReadTraceFilterBlock retrieves the json encoded flat call traces of the block
+// with the given number.
+func ReadTraceFilterBlock(db ethdb.KeyValueReader, number uint64) []byte {
+	data, _ := db.Get(traceFilterBlockKey(number))
+	return data
+}
+
+// WriteTraceFilterBlock stores the json encoded flat call traces of the block
+// with the given number.
+func WriteTraceFilterBlock(db ethdb.KeyValueWriter, number uint64, traces []byte) {
+	if err := db.Put(traceFilterBlockKey(number), traces); err != nil {
+		log.Crit("Failed to store trace filter block", "err", err)
+	}
+}
+
+// DeleteTraceFilterBlock removes the flat call traces of the block with the
+// given number.
+func DeleteTraceFilterBlock(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Delete(traceFilterBlockKey(number)); err != nil {
+		log.Crit("Failed to delete trace filter block", "err", err)
+	}
+}
+
+// WriteTraceFilterAddress stores the roles of an address in the flat call
+// traces of the block with the given number.
+func WriteTraceFilterAddress(db ethdb.KeyValueWriter, addr common.Address, number uint64, roles byte) {
+	if err := db.Put(traceFilterAddrKey(addr, number), []byte{roles}); err != nil {
+		log.Crit("Failed to store trace filter address", "err", err)
+	}
+}
+
+// DeleteTraceFilterAddress removes an address from the trace filter index of
+// the block with the given number.
+func DeleteTraceFilterAddress(db ethdb.KeyValueWriter, addr common.Address, number uint64) {
+	if err := db.Delete(traceFilterAddrKey(addr, number)); err != nil {
+		log.Crit("Failed to delete trace filter address", "err", err)
+	}
+}
+
+// ReadTraceFilterAddressBlocks retrieves the numbers of the blocks in the
+// inclusive range [from, to] whose flat call traces contain the given address
+// in any of the given roles, in ascending order.
+func ReadTraceFilterAddressBlocks(db ethdb.Iteratee, addr common.Address, from, to uint64, roles byte) []uint64 {
+	prefix := traceFilterAddrKey(addr, 0)[:len(TraceFilterAddrPrefix)+common.AddressLength]
+
+	it := db.NewIterator(prefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var numbers []uint64
+	for it.Next() {
+		if len(it.Key()) != len(prefix)+8 || len(it.Value()) != 1 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(it.Key()[len(prefix):])
+		if number > to {
+			break
+		}
+		if it.Value()[0]&roles != 0 {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
@@ -165,6 +165,17 @@ var (
 
 	// Verkle transition information
 	VerkleTransitionStatePrefix = []byte("verkle-transition-state-")
+
+	// Persistent cache of the opcode optimizer, stored in its own database
+	OptimizedCodePrefix  = []byte("opt-code-")  // OptimizedCodePrefix + version (uint32 big endian) + code hash -> optimized code and jumpdest bitvec
+	OptimizedCallsPrefix = []byte("opt-calls-") // OptimizedCallsPrefix + version (uint32 big endian) + code hash -> call count (uint64 big endian)
+
+	OptimizedDisabledPrefix = []byte("opt-disabled-") // OptimizedDisabledPrefix + version (uint32 big endian) + code hash -> empty, code excluded from optimization
+
+	// Trace filter index of the live tracer, stored in its own database
+	TraceFilterTailKey     = []byte("TraceFilterTail") // Number of the oldest indexed block
+	TraceFilterBlockPrefix = []byte("tf-b")            // TraceFilterBlockPrefix + num (uint64 big endian) -> json encoded flat call traces
+	TraceFilterAddrPrefix  = []byte("tf-a")            // TraceFilterAddrPrefix + address + num (uint64 big endian) -> address roles
 )
 
 // LegacyTxLookupEntry is the legacy TxLookupEntry definition with some unnecessary
@@ -256,6 +267,29 @@ func codeKey(hash common.Hash) []byte {
 	return append(CodePrefix, hash.Bytes()...)
 }
 
+// optimizedCodeKey = prefix + version (uint32 big endian) + hash
+func optimizedCodeKey(prefix []byte, version uint32, hash common.Hash) []byte {
+	key := make([]byte, len(prefix)+4+common.HashLength)
+	copy(key, prefix)
+	binary.BigEndian.PutUint32(key[len(prefix):], version)
+	copy(key[len(prefix)+4:], hash.Bytes())
+	return key
+}
+
+// traceFilterBlockKey = TraceFilterBlockPrefix + num (uint64 big endian)
+func traceFilterBlockKey(number uint64) []byte {
+	return append(TraceFilterBlockPrefix, encodeBlockNumber(number)...)
+}
+
+// traceFilterAddrKey = TraceFilterAddrPrefix + address + num (uint64 big endian)
+func traceFilterAddrKey(addr common.Address, number uint64) []byte {
+	key := make([]byte, len(TraceFilterAddrPrefix)+common.AddressLength+8)
+	copy(key, TraceFilterAddrPrefix)
+	copy(key[len(TraceFilterAddrPrefix):], addr.Bytes())
+	binary.BigEndian.PutUint64(key[len(TraceFilterAddrPrefix)+common.AddressLength:], number)
+	return key
+}
+
 // IsCodeKey reports whether the given byte slice is the key of contract code,
 // if so return the raw code hash as well.
 func IsCodeKey(key []byte) (bool, []byte) {
@@ -145,6 +145,11 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		}
 		statedb.SetTxContext(tx.Hash(), i)
 
+		if cfg.EnableOpcodeOptimizations && cfg.CheckOpcodeOptimizations {
+			if mismatch := CheckOpcodeOptimization(msg, statedb, context, config, cfg); mismatch != nil {
+				log.Error("Opcode optimization diverged, disabled for involved code", "number", blockNumber, "tx", tx.Hash(), "err", mismatch, "codes", mismatch.Codes)
+			}
+		}
 		receipt, err := ApplyTransactionWithEVM(msg, gp, statedb, blockNumber, blockHash, context.Time, tx, usedGas, evm, bloomProcessors)
 		if err != nil {
 			bloomProcessors.Close()
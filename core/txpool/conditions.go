@@ -0,0 +1,118 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// MaxConditionEntries is the maximum number of storage roots and slots the known
+// accounts of a conditional transaction may reference in total.
+const MaxConditionEntries = 1000
+
+var (
+	// ErrConditionsTooLarge is returned if the known accounts of a conditional
+	// transaction reference too many storage entries.
+	ErrConditionsTooLarge = errors.New("knownAccounts too large")
+
+	// ErrConditionsPremature is returned if the block or time lower bound of a
+	// conditional transaction is not yet reached. Such a transaction may become
+	// includable later on.
+	ErrConditionsPremature = errors.New("conditions not met yet")
+
+	// ErrConditionsExpired is returned if the block or time upper bound of a
+	// conditional transaction is exceeded, or the known accounts don't match
+	// the state. Such a transaction can be evicted from the pool.
+	ErrConditionsExpired = errors.New("conditions not met")
+)
+
+// ValidateConditionsBasics checks the stateless limits of transaction conditions.
+func ValidateConditionsBasics(opts *types.TransactionOpts) error {
+	var entries int
+	for _, account := range opts.KnownAccounts {
+		if account.StorageRoot != nil {
+			entries += 1
+		} else {
+			entries += len(account.StorageSlots)
+		}
+	}
+	if entries > MaxConditionEntries {
+		return ErrConditionsTooLarge
+	}
+	return nil
+}
+
+// ValidateConditions checks whether the conditions attached to a transaction are
+// satisfied by a block with the given number and timestamp on top of the given
+// state.
+//
+// An error wrapping ErrConditionsPremature is returned if only the lower bounds
+// are violated, ErrConditionsExpired if the conditions can never be satisfied
+// anymore on the current chain.
+func ValidateConditions(opts *types.TransactionOpts, number uint64, time uint64, statedb *state.StateDB) error {
+	if opts.BlockNumberMax != nil && number > uint64(*opts.BlockNumberMax) {
+		return fmt.Errorf("%w: block number %d above max %d", ErrConditionsExpired, number, uint64(*opts.BlockNumberMax))
+	}
+	if opts.TimestampMax != nil && time > uint64(*opts.TimestampMax) {
+		return fmt.Errorf("%w: timestamp %d above max %d", ErrConditionsExpired, time, uint64(*opts.TimestampMax))
+	}
+	if err := ValidateConditionsBasics(opts); err != nil {
+		return err
+	}
+	for address, account := range opts.KnownAccounts {
+		if account.StorageRoot != nil {
+			if root := statedb.GetStorageRoot(address); root != *account.StorageRoot {
+				return fmt.Errorf("%w: storage root mismatch for %x", ErrConditionsExpired, address)
+			}
+			continue
+		}
+		for slot, value := range account.StorageSlots {
+			if statedb.GetState(address, slot) != value {
+				return fmt.Errorf("%w: storage slot %x mismatch for %x", ErrConditionsExpired, slot, address)
+			}
+		}
+	}
+	if opts.BlockNumberMin != nil && number < uint64(*opts.BlockNumberMin) {
+		return fmt.Errorf("%w: block number %d below min %d", ErrConditionsPremature, number, uint64(*opts.BlockNumberMin))
+	}
+	if opts.TimestampMin != nil && time < uint64(*opts.TimestampMin) {
+		return fmt.Errorf("%w: timestamp %d below min %d", ErrConditionsPremature, time, uint64(*opts.TimestampMin))
+	}
+	return nil
+}
+
+// ValidateConditionsAdmission checks whether the conditions attached to a
+// transaction can still be satisfied by the next block on top of the given
+// head, as required to admit it into the pool. Conditions whose lower bounds
+// are not reached yet are accepted, the miner holds the transaction back until
+// they are.
+//
+// The timestamp of the next block is not known yet, it is approximated by the
+// current time, but at least one second past the head.
+func ValidateConditionsAdmission(opts *types.TransactionOpts, head *types.Header, statedb *state.StateDB) error {
+	next := max(head.Time+1, uint64(time.Now().Unix()))
+	err := ValidateConditions(opts, head.Number.Uint64()+1, next, statedb)
+	if errors.Is(err, ErrConditionsPremature) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that transaction conditions are classified as premature or expired
+// depending on which bound or state requirement is violated.
+func TestValidateConditions(t *testing.T) {
+	var (
+		addr  = common.HexToAddress("0xaa")
+		slot  = common.HexToHash("0x01")
+		value = common.HexToHash("0x02")
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.SetState(addr, slot, value)
+
+	u64 := func(n uint64) *hexutil.Uint64 { return (*hexutil.Uint64)(&n) }
+	tests := []struct {
+		opts types.TransactionOpts
+		want error
+	}{
+		{types.TransactionOpts{}, nil},
+		{types.TransactionOpts{BlockNumberMin: u64(10), BlockNumberMax: u64(10)}, nil},
+		{types.TransactionOpts{BlockNumberMin: u64(11)}, ErrConditionsPremature},
+		{types.TransactionOpts{BlockNumberMax: u64(9)}, ErrConditionsExpired},
+		{types.TransactionOpts{TimestampMin: u64(101)}, ErrConditionsPremature},
+		{types.TransactionOpts{TimestampMax: u64(99)}, ErrConditionsExpired},
+		{types.TransactionOpts{KnownAccounts: types.KnownAccounts{
+			addr: {StorageSlots: map[common.Hash]common.Hash{slot: value}},
+		}}, nil},
+		{types.TransactionOpts{KnownAccounts: types.KnownAccounts{
+			addr: {StorageSlots: map[common.Hash]common.Hash{slot: {}}},
+		}}, ErrConditionsExpired},
+		{types.TransactionOpts{KnownAccounts: types.KnownAccounts{
+			addr: {StorageRoot: &common.Hash{}},
+		}}, ErrConditionsExpired},
+		// A state mismatch can never be healed by waiting, even if premature
+		{types.TransactionOpts{BlockNumberMin: u64(11), KnownAccounts: types.KnownAccounts{
+			addr: {StorageSlots: map[common.Hash]common.Hash{slot: {}}},
+		}}, ErrConditionsExpired},
+	}
+	for i, tt := range tests {
+		if err := ValidateConditions(&tt.opts, 10, 100, statedb); !errors.Is(err, tt.want) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.want)
+		}
+	}
+}
+
+// Tests that conditions referencing too many storage entries are rejected.
+func TestValidateConditionsBasics(t *testing.T) {
+	slots := make(map[common.Hash]common.Hash)
+	for i := 0; i < MaxConditionEntries; i++ {
+		slots[common.BigToHash(big.NewInt(int64(i)))] = common.Hash{}
+	}
+	opts := &types.TransactionOpts{KnownAccounts: types.KnownAccounts{
+		common.HexToAddress("0xaa"): {StorageSlots: slots},
+	}}
+	if err := ValidateConditionsBasics(opts); err != nil {
+		t.Fatalf("conditions at the limit rejected: %v", err)
+	}
+	opts.KnownAccounts[common.HexToAddress("0xbb")] = types.AccountStorage{StorageRoot: &common.Hash{}}
+	if err := ValidateConditionsBasics(opts); !errors.Is(err, ErrConditionsTooLarge) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrConditionsTooLarge)
+	}
+}
+
+// Tests that admission checks conditions against the block on top of the head,
+// accepting premature conditions but rejecting expired ones.
+func TestValidateConditionsAdmission(t *testing.T) {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	now := uint64(time.Now().Unix())
+	head := &types.Header{Number: big.NewInt(10), Time: now - 100}
+
+	u64 := func(n uint64) *hexutil.Uint64 { return (*hexutil.Uint64)(&n) }
+	tests := []struct {
+		opts types.TransactionOpts
+		want error
+	}{
+		{types.TransactionOpts{BlockNumberMin: u64(11), BlockNumberMax: u64(11)}, nil},
+		{types.TransactionOpts{BlockNumberMin: u64(20)}, nil},
+		{types.TransactionOpts{TimestampMin: u64(now + 100)}, nil},
+		{types.TransactionOpts{TimestampMax: u64(now + 100)}, nil},
+		{types.TransactionOpts{BlockNumberMax: u64(10)}, ErrConditionsExpired},
+		// The next block can't be older than the current time, nor the head
+		{types.TransactionOpts{TimestampMax: u64(now - 10)}, ErrConditionsExpired},
+		{types.TransactionOpts{TimestampMax: u64(now - 100)}, ErrConditionsExpired},
+	}
+	for i, tt := range tests {
+		if err := ValidateConditionsAdmission(&tt.opts, head, statedb); !errors.Is(err, tt.want) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.want)
+		}
+	}
+}
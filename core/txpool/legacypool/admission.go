@@ -0,0 +1,311 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// admissionWindow is the time window over which sender and target throughput
+	// is capped. Sender statistics are decayed at the end of each window.
+	admissionWindow = time.Minute
+
+	// scoreDecay is the factor all sender statistics are multiplied by at the end
+	// of every admission window, giving them a half-life of ~6.5 windows.
+	scoreDecay = 0.9
+
+	// scoreForgetThreshold is the total amount of decayed activity below which a
+	// sender is forgotten altogether.
+	scoreForgetThreshold = 0.5
+
+	// maxScoredSenders is the maximum number of senders tracked at once. Beyond
+	// it, the best scored of a few sampled senders is forgotten to make room.
+	maxScoredSenders = 65536
+
+	// scoreEvictSamples is the number of senders sampled for eviction.
+	scoreEvictSamples = 8
+)
+
+var (
+	// ErrSenderThrottled is returned if the sender of a transaction exceeded its
+	// score-adjusted throughput allowance in the current admission window.
+	ErrSenderThrottled = errors.New("sender throughput exceeded")
+
+	// ErrTargetThrottled is returned if the recipient of a transaction received
+	// too many transactions in the current admission window.
+	ErrTargetThrottled = errors.New("target throughput exceeded")
+
+	senderThrottleMeter = metrics.NewRegisteredMeter("txpool/admission/sender", nil)
+	targetThrottleMeter = metrics.NewRegisteredMeter("txpool/admission/target", nil)
+	scoredSendersGauge  = metrics.NewRegisteredGauge("txpool/admission/senders", nil)
+)
+
+// AdmissionEvent is a pool event feeding back into the admission policy.
+type AdmissionEvent uint8
+
+const (
+	AdmissionIncluded AdmissionEvent = iota // Transactions of the sender got included in a block
+	AdmissionDropped                        // Transactions of the sender got dropped without inclusion
+	AdmissionReplaced                       // A transaction of the sender replaced an earlier one
+	AdmissionGapped                         // A transaction of the sender was added with a nonce gap
+)
+
+// AdmissionPolicy decides whether a transaction is admitted into the pool on top
+// of the static slot and price limits, based on the past behaviour of its sender.
+//
+// Admit is called for every new transaction passing basic validation, Refund for
+// the admitted ones the pool rejects afterwards, and Track with the events the
+// pool observes later on. All are called concurrently.
+//
+// The pool cannot tell local submissions apart from network ones, so transactions
+// submitted over RPC are subject to the policy too.
+type AdmissionPolicy interface {
+	// Admit returns an error if the transaction should be rejected.
+	Admit(from common.Address, tx *types.Transaction) error
+
+	// Refund reverts the charge of an admitted transaction the pool rejected.
+	Refund(from common.Address, tx *types.Transaction)
+
+	// Track feeds a number of pool events of the given sender into the policy.
+	Track(from common.Address, event AdmissionEvent, count int)
+
+	// Scores returns the current score of all senders tracked by the policy.
+	Scores() map[common.Address]SenderScore
+}
+
+// SenderScore is the admission state of a single sender. The counters decay over
+// time, so they are fractional.
+type SenderScore struct {
+	Score     float64 `json:"score"`     // Score in (0, 1], scaling the sender's throughput allowance
+	Admitted  float64 `json:"admitted"`  // Number of transactions admitted into the pool
+	Included  float64 `json:"included"`  // Number of transactions included in blocks
+	Dropped   float64 `json:"dropped"`   // Number of transactions dropped without inclusion
+	Replaced  float64 `json:"replaced"`  // Number of replacement transactions
+	Gapped    float64 `json:"gapped"`    // Number of transactions added with a nonce gap
+	Allowance uint64  `json:"allowance"` // Number of transactions admissible in the current window
+	Used      uint64  `json:"used"`      // Number of transactions admitted in the current window
+}
+
+// score calculates the admission score from the sender's history. Senders are
+// rewarded for getting transactions included, and penalized for having them
+// dropped, churning replacements and leaving nonce gaps. Senders without any
+// history start out with the maximum score.
+func (s *SenderScore) score() float64 {
+	var (
+		inclusion = (s.Included + 1) / (s.Included + s.Dropped + 1)
+		churn     = math.Min(s.Replaced/(s.Admitted+1), 1)
+		gaps      = math.Min(s.Gapped/(s.Admitted+1), 1)
+	)
+	return inclusion * (1 - churn/2) * (1 - gaps/2)
+}
+
+// scoringPolicy is the default admission policy, capping the throughput of every
+// sender proportionally to its score and the throughput of every recipient to a
+// fixed rate, protecting the pool from a spammer with many funded accounts.
+type scoringPolicy struct {
+	senderRate uint64 // Transactions admitted per window for a sender with a perfect score (0 = unlimited)
+	targetRate uint64 // Transactions admitted per window towards a single recipient (0 = unlimited)
+
+	senders map[common.Address]*SenderScore
+	targets map[common.Address]uint64
+	window  time.Time
+	now     func() time.Time
+	lock    sync.Mutex
+}
+
+// NewScoringPolicy creates the default admission policy with the given per-sender
+// and per-target throughput limits per minute.
+func NewScoringPolicy(senderRate, targetRate uint64) AdmissionPolicy {
+	return newScoringPolicy(senderRate, targetRate, time.Now)
+}
+
+func newScoringPolicy(senderRate, targetRate uint64, now func() time.Time) *scoringPolicy {
+	return &scoringPolicy{
+		senderRate: senderRate,
+		targetRate: targetRate,
+		senders:    make(map[common.Address]*SenderScore),
+		targets:    make(map[common.Address]uint64),
+		window:     now(),
+		now:        now,
+	}
+}
+
+// Admit implements AdmissionPolicy, charging the transaction against both the
+// sender's and the recipient's allowance if admitted.
+func (p *scoringPolicy) Admit(from common.Address, tx *types.Transaction) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.roll()
+	sender := p.sender(from)
+	if p.senderRate > 0 && sender.Used >= p.allowance(sender) {
+		senderThrottleMeter.Mark(1)
+		return ErrSenderThrottled
+	}
+	if to := tx.To(); to != nil && p.targetRate > 0 {
+		if p.targets[*to] >= p.targetRate {
+			targetThrottleMeter.Mark(1)
+			return ErrTargetThrottled
+		}
+		p.targets[*to]++
+	}
+	sender.Used++
+	sender.Admitted++
+	return nil
+}
+
+// Refund implements AdmissionPolicy. Charges from an elapsed window were already
+// reset, so the counters are only reverted down to zero.
+func (p *scoringPolicy) Refund(from common.Address, tx *types.Transaction) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.roll()
+	if to := tx.To(); to != nil && p.targets[*to] > 0 {
+		if p.targets[*to]--; p.targets[*to] == 0 {
+			delete(p.targets, *to)
+		}
+	}
+	sender, ok := p.senders[from]
+	if !ok {
+		return
+	}
+	if sender.Used > 0 {
+		sender.Used--
+	}
+	sender.Admitted = max(sender.Admitted-1, 0)
+}
+
+// Track implements AdmissionPolicy.
+func (p *scoringPolicy) Track(from common.Address, event AdmissionEvent, count int) {
+	if count == 0 {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.roll()
+	sender := p.sender(from)
+	switch event {
+	case AdmissionIncluded:
+		sender.Included += float64(count)
+	case AdmissionDropped:
+		sender.Dropped += float64(count)
+	case AdmissionReplaced:
+		sender.Replaced += float64(count)
+	case AdmissionGapped:
+		sender.Gapped += float64(count)
+	}
+}
+
+// Scores implements AdmissionPolicy.
+func (p *scoringPolicy) Scores() map[common.Address]SenderScore {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.roll()
+	scores := make(map[common.Address]SenderScore, len(p.senders))
+	for addr, sender := range p.senders {
+		score := *sender
+		score.Score = sender.score()
+		score.Allowance = p.allowance(sender)
+		scores[addr] = score
+	}
+	return scores
+}
+
+// sender retrieves the state of a sender, starting to track it if unknown.
+//
+// Note, this method assumes the policy lock is held!
+func (p *scoringPolicy) sender(from common.Address) *SenderScore {
+	sender, ok := p.senders[from]
+	if !ok {
+		if len(p.senders) >= maxScoredSenders {
+			p.evict()
+		}
+		sender = new(SenderScore)
+		p.senders[from] = sender
+		scoredSendersGauge.Update(int64(len(p.senders)))
+	}
+	return sender
+}
+
+// evict forgets the best scored out of a few randomly sampled senders, keeping
+// the penalized ones tracked for as long as possible.
+//
+// Note, this method assumes the policy lock is held!
+func (p *scoringPolicy) evict() {
+	var (
+		victim common.Address
+		best   = -1.0
+		seen   int
+	)
+	for addr, sender := range p.senders {
+		if score := sender.score(); score > best {
+			victim, best = addr, score
+		}
+		if seen++; seen == scoreEvictSamples {
+			break
+		}
+	}
+	delete(p.senders, victim)
+}
+
+// allowance returns the number of transactions the sender may add within a
+// single window. Every sender is allowed at least one.
+//
+// Note, this method assumes the policy lock is held!
+func (p *scoringPolicy) allowance(sender *SenderScore) uint64 {
+	if p.senderRate == 0 {
+		return math.MaxUint64
+	}
+	return max(1, uint64(float64(p.senderRate)*sender.score()))
+}
+
+// roll starts a new admission window if the current one elapsed, resetting the
+// throughput counters and decaying the sender history.
+//
+// Note, this method assumes the policy lock is held!
+func (p *scoringPolicy) roll() {
+	now := p.now()
+	if now.Sub(p.window) < admissionWindow {
+		return
+	}
+	decay := math.Pow(scoreDecay, float64(now.Sub(p.window)/admissionWindow))
+	p.window = now
+
+	clear(p.targets)
+	maps.DeleteFunc(p.senders, func(_ common.Address, sender *SenderScore) bool {
+		sender.Used = 0
+		sender.Admitted *= decay
+		sender.Included *= decay
+		sender.Dropped *= decay
+		sender.Replaced *= decay
+		sender.Gapped *= decay
+		return sender.Admitted+sender.Included+sender.Dropped+sender.Replaced+sender.Gapped < scoreForgetThreshold
+	})
+	scoredSendersGauge.Update(int64(len(p.senders)))
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that the scoring policy caps sender throughput proportionally to the
+// sender's history and recipient throughput to a fixed rate per window.
+func TestScoringPolicy(t *testing.T) {
+	var (
+		now    = time.Unix(0, 0)
+		policy = newScoringPolicy(4, 6, func() time.Time { return now })
+
+		good   = common.HexToAddress("0x01")
+		bad    = common.HexToAddress("0x02")
+		target = common.HexToAddress("0xaa")
+	)
+	tx := func(to common.Address) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{To: &to})
+	}
+	// Fresh senders start with a perfect score and the full allowance
+	for i := 0; i < 4; i++ {
+		if err := policy.Admit(good, tx(target)); err != nil {
+			t.Fatalf("transaction %d: failed to admit: %v", i, err)
+		}
+	}
+	if err := policy.Admit(good, tx(target)); !errors.Is(err, ErrSenderThrottled) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrSenderThrottled)
+	}
+	// Senders getting their transactions dropped should be throttled harder
+	policy.Track(bad, AdmissionDropped, 10)
+	policy.Track(bad, AdmissionGapped, 10)
+	if err := policy.Admit(bad, tx(target)); err != nil {
+		t.Fatalf("failed to admit minimum allowance: %v", err)
+	}
+	if err := policy.Admit(bad, tx(target)); !errors.Is(err, ErrSenderThrottled) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrSenderThrottled)
+	}
+	// Recipients are capped independently of the sender score
+	other := common.HexToAddress("0x03")
+	if err := policy.Admit(other, tx(target)); err != nil {
+		t.Fatalf("failed to admit up to the target rate: %v", err)
+	}
+	if err := policy.Admit(other, tx(target)); !errors.Is(err, ErrTargetThrottled) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrTargetThrottled)
+	}
+	if err := policy.Admit(other, tx(common.HexToAddress("0xbb"))); err != nil {
+		t.Fatalf("failed to admit towards another target: %v", err)
+	}
+	scores := policy.Scores()
+	if scores[good].Score != 1 || scores[good].Used != 4 || scores[good].Allowance != 4 {
+		t.Errorf("good sender mismatch: have %+v", scores[good])
+	}
+	if scores[bad].Score >= 0.25 || scores[bad].Allowance != 1 {
+		t.Errorf("bad sender mismatch: have %+v", scores[bad])
+	}
+	// A new window resets the allowance and decays the history
+	now = now.Add(admissionWindow)
+	if err := policy.Admit(good, tx(target)); err != nil {
+		t.Fatalf("failed to admit in new window: %v", err)
+	}
+	if scores := policy.Scores(); scores[bad].Dropped != 10*scoreDecay {
+		t.Errorf("history not decayed: have %v, want %v", scores[bad].Dropped, 10*scoreDecay)
+	}
+	// Senders without any recent activity should eventually be forgotten
+	now = now.Add(100 * admissionWindow)
+	if scores := policy.Scores(); len(scores) != 0 {
+		t.Errorf("stale senders not forgotten: have %d", len(scores))
+	}
+}
+
+// Tests that refunded transactions are no longer charged against the sender's
+// and recipient's allowance.
+func TestScoringPolicyRefund(t *testing.T) {
+	var (
+		now    = time.Unix(0, 0)
+		policy = newScoringPolicy(1, 1, func() time.Time { return now })
+
+		sender = common.HexToAddress("0x01")
+		target = common.HexToAddress("0xaa")
+		tx     = types.NewTx(&types.LegacyTx{To: &target})
+	)
+	if err := policy.Admit(sender, tx); err != nil {
+		t.Fatalf("failed to admit: %v", err)
+	}
+	policy.Refund(sender, tx)
+	if err := policy.Admit(sender, tx); err != nil {
+		t.Fatalf("failed to admit after refund: %v", err)
+	}
+	if scores := policy.Scores(); scores[sender].Used != 1 || scores[sender].Admitted != 1 {
+		t.Errorf("sender charge mismatch: have %+v", scores[sender])
+	}
+	// Refunds of charges from an elapsed window must not underflow
+	now = now.Add(admissionWindow)
+	policy.Refund(sender, tx)
+	if scores := policy.Scores(); scores[sender].Used != 0 {
+		t.Errorf("stale refund underflowed: have %+v", scores[sender])
+	}
+}
+
+// Tests that the number of tracked senders is capped, keeping penalized senders
+// over well-behaved ones.
+func TestScoringPolicyBound(t *testing.T) {
+	policy := newScoringPolicy(4, 0, time.Now)
+
+	bad := common.HexToAddress("0xbad")
+	policy.Track(bad, AdmissionDropped, 10)
+	for i := 0; i < 2*maxScoredSenders; i++ {
+		policy.Track(common.BigToAddress(big.NewInt(int64(i+1))), AdmissionIncluded, 1)
+	}
+	scores := policy.Scores()
+	if len(scores) != maxScoredSenders {
+		t.Errorf("tracked sender count mismatch: have %d, want %d", len(scores), maxScoredSenders)
+	}
+	if _, ok := scores[bad]; !ok {
+		t.Errorf("penalized sender evicted")
+	}
+}
+
+// Tests that transactions rejected by the pool after admission are refunded, so
+// they don't eat into the throughput allowance of their sender.
+func TestAdmissionRefundOnReject(t *testing.T) {
+	pool, key := setupPool()
+	defer pool.Close()
+	pool.SetAdmissionPolicy(NewScoringPolicy(1, 0))
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	// Unfunded transactions pass basic validation but are rejected by the pool
+	if err := pool.addRemoteSync(transaction(0, 100000, key)); !errors.Is(err, core.ErrInsufficientFunds) {
+		t.Fatalf("error mismatch: have %v, want %v", err, core.ErrInsufficientFunds)
+	}
+	testAddBalance(pool, from, big.NewInt(1000000000))
+	if err := pool.addRemoteSync(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction after rejected one: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(1, 100000, key)); !errors.Is(err, ErrSenderThrottled) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrSenderThrottled)
+	}
+}
@@ -98,6 +98,10 @@ var (
 	queuedNofundsMeter   = metrics.NewRegisteredMeter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
 	queuedEvictionMeter  = metrics.NewRegisteredMeter("txpool/queued/eviction", nil)  // Dropped due to lifetime
 
+	// conditionalEvictionMeter counts conditional transactions dropped due to their
+	// conditions not being satisfiable anymore.
+	conditionalEvictionMeter = metrics.NewRegisteredMeter("txpool/conditional/eviction", nil)
+
 	// General tx metrics
 	knownTxMeter       = metrics.NewRegisteredMeter("txpool/known", nil)
 	validTxMeter       = metrics.NewRegisteredMeter("txpool/valid", nil)
@@ -154,6 +158,12 @@ type Config struct {
 
 	Lifetime       time.Duration // Maximum amount of time non-executable transaction are queued
 	ReannounceTime time.Duration // Duration for announcing local pending transactions again
+
+	LifecycleRetention time.Duration // Time window to retain transaction lifecycle records (0 = disabled)
+
+	// Admission throughput limits, applied to local submissions too
+	SenderRate uint64 // Transactions admitted per minute from a well-scored sender (0 = unlimited)
+	TargetRate uint64 // Transactions admitted per minute towards a single recipient (0 = unlimited)
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -248,6 +258,8 @@ type LegacyPool struct {
 	currentState  *state.StateDB               // Current state in the blockchain head
 	pendingNonces *noncer                      // Pending state tracking virtual nonces
 	reserver      txpool.Reserver              // Address reserver to ensure exclusivity across subpools
+	lifecycle     *txpool.LifecycleTracker     // Optional tracker of transaction pool transitions
+	admission     AdmissionPolicy              // Optional policy deciding on the admission of new transactions
 
 	pending map[common.Address]*list // All currently processable transactions
 	queue   *queue
@@ -293,10 +305,40 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		initDoneCh:      make(chan struct{}),
 	}
 	pool.priced = newPricedList(pool.all)
-
+	if config.SenderRate > 0 || config.TargetRate > 0 {
+		pool.admission = NewScoringPolicy(config.SenderRate, config.TargetRate)
+	}
 	return pool
 }
 
+// SetLifecycleTracker sets the tracker to report pool transitions into. It must
+// be called before the pool is initialized.
+func (pool *LegacyPool) SetLifecycleTracker(tracker *txpool.LifecycleTracker) {
+	pool.lifecycle = tracker
+}
+
+// SetAdmissionPolicy replaces the policy deciding on the admission of new
+// transactions. It must be called before the pool is initialized.
+func (pool *LegacyPool) SetAdmissionPolicy(policy AdmissionPolicy) {
+	pool.admission = policy
+}
+
+// SenderScores returns the admission scores of all tracked senders, or nil if
+// no admission policy is configured.
+func (pool *LegacyPool) SenderScores() map[common.Address]SenderScore {
+	if pool.admission == nil {
+		return nil
+	}
+	return pool.admission.Scores()
+}
+
+// trackAdmission feeds a pool event into the admission policy, if any.
+func (pool *LegacyPool) trackAdmission(from common.Address, event AdmissionEvent, count int) {
+	if pool.admission != nil {
+		pool.admission.Track(from, event, count)
+	}
+}
+
 // Filter returns whether the given transaction can be consumed by the legacy
 // pool, specifically, whether it is a Legacy, AccessList or Dynamic transaction.
 func (pool *LegacyPool) Filter(tx *types.Transaction) bool {
@@ -382,7 +424,12 @@ func (pool *LegacyPool) loop() {
 		case <-evict.C:
 			pool.mu.Lock()
 			for _, hash := range pool.queue.evictList() {
+				if tx := pool.all.Get(hash); tx != nil {
+					from, _ := types.Sender(pool.signer, tx)
+					pool.trackAdmission(from, AdmissionDropped, 1)
+				}
 				pool.removeTx(hash, true, true)
+				pool.lifecycle.Record(hash, txpool.TxEventDropped)
 			}
 			pool.mu.Unlock()
 
@@ -462,6 +509,7 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 		drop := pool.all.TxsBelowTip(tip)
 		for _, tx := range drop {
 			pool.removeTx(tx.Hash(), false, true)
+			pool.lifecycle.Record(tx.Hash(), txpool.TxEventDropped)
 		}
 		pool.priced.Removed(len(drop))
 	}
@@ -645,9 +693,22 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction) error {
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err
 	}
+	if err := pool.validateConditions(tx); err != nil {
+		return err
+	}
 	return pool.validateAuth(tx)
 }
 
+// validateConditions checks whether the conditions attached to a transaction can
+// still be satisfied by the next block on top of the current head.
+func (pool *LegacyPool) validateConditions(tx *types.Transaction) error {
+	opts := tx.Conditions()
+	if opts == nil {
+		return nil
+	}
+	return txpool.ValidateConditionsAdmission(opts, pool.currentHead.Load(), pool.currentState)
+}
+
 // checkDelegationLimit determines if the tx sender is delegated or has a
 // pending delegation, and if so, ensures they have at most one in-flight
 // **executable** transaction, e.g. disallow stacked and gapped transactions
@@ -811,6 +872,7 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 
 			sender, _ := types.Sender(pool.signer, tx)
 			dropped := pool.removeTx(tx.Hash(), false, sender != from) // Don't unreserve the sender of the tx being added if last from the acc
+			pool.lifecycle.Record(tx.Hash(), txpool.TxEventDropped)
 
 			pool.changesSinceReorg += dropped
 		}
@@ -829,10 +891,13 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.lifecycle.Record(old.Hash(), txpool.TxEventReplaced)
+			pool.trackAdmission(from, AdmissionReplaced, 1)
 		}
 		pool.all.Add(tx)
 		pool.priced.Put(tx)
 		pool.queueTxEvent(tx)
+		pool.lifecycle.Record(hash, txpool.TxEventPending)
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
 		// Successful promotion, bump the heartbeat
@@ -840,10 +905,17 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 		return old != nil, nil
 	}
 	// New transaction isn't replacing a pending one, push into queue
+	gapped := pool.admission != nil && pool.isGapped(from, tx)
 	replaced, err = pool.enqueueTx(hash, tx, true)
 	if err != nil {
 		return false, err
 	}
+	if replaced {
+		pool.trackAdmission(from, AdmissionReplaced, 1)
+	}
+	if gapped {
+		pool.trackAdmission(from, AdmissionGapped, 1)
+	}
 
 	log.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To())
 	return replaced, nil
@@ -882,6 +954,7 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, addAl
 		return false, err
 	}
 	if replaced != nil {
+		pool.lifecycle.Record(*replaced, txpool.TxEventReplaced)
 		pool.removeTx(*replaced, true, true)
 	}
 	// If the transaction isn't in lookup set but it's expected to be there,
@@ -892,6 +965,9 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, addAl
 	if addAll {
 		pool.all.Add(tx)
 		pool.priced.Put(tx)
+		pool.lifecycle.Record(hash, txpool.TxEventQueued)
+	} else {
+		pool.lifecycle.Record(hash, txpool.TxEventDemoted)
 	}
 	return replaced != nil, nil
 }
@@ -913,6 +989,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.lifecycle.Record(hash, txpool.TxEventDropped)
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -920,10 +997,13 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.lifecycle.Record(old.Hash(), txpool.TxEventReplaced)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
 	}
+	pool.lifecycle.Record(hash, txpool.TxEventPending)
+
 	// Set the potentially new pending nonce and notify any subsystems of the new tx
 	pool.pendingNonces.set(addr, tx.Nonce()+1)
 
@@ -983,6 +1063,16 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, sync bool) []error {
 			invalidTxMeter.Mark(1)
 			continue
 		}
+		// Charge the transaction against the throughput allowance of its sender
+		// and recipient, the sender was cached by the basic validation
+		if pool.admission != nil {
+			from, _ := types.Sender(pool.signer, tx)
+			if err := pool.admission.Admit(from, tx); err != nil {
+				errs[i] = err
+				log.Trace("Discarding throttled transaction", "hash", tx.Hash(), "from", from, "err", err)
+				continue
+			}
+		}
 		// Accumulate all unknown transactions for deeper processing
 		news = append(news, tx)
 	}
@@ -995,6 +1085,15 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, sync bool) []error {
 	newErrs, dirtyAddrs := pool.addTxsLocked(news)
 	pool.mu.Unlock()
 
+	// Refund the throughput allowance charged for the transactions rejected
+	if pool.admission != nil {
+		for i, err := range newErrs {
+			if err != nil {
+				from, _ := types.Sender(pool.signer, news[i])
+				pool.admission.Refund(from, news[i])
+			}
+		}
+	}
 	var nilSlot = 0
 	for _, err := range newErrs {
 		for errs[nilSlot] != nil {
@@ -1290,6 +1389,7 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 				})
 				for _, hash := range hashes {
 					pool.removeTx(hash, true, true)
+					pool.lifecycle.Record(hash, txpool.TxEventDropped)
 				}
 			}
 		}
@@ -1314,6 +1414,7 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 	// because of another transaction (e.g. higher gas price).
 	if reset != nil {
 		pool.demoteUnexecutables()
+		pool.evictConditionals()
 		if reset.newHead != nil {
 			if pool.chainconfig.IsLondon(new(big.Int).Add(reset.newHead.Number, big.NewInt(1))) {
 				pendingBaseFee := eip1559.CalcBaseFee(pool.chainconfig, reset.newHead)
@@ -1451,6 +1552,9 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
+	for _, tx := range reinject {
+		pool.lifecycle.Record(tx.Hash(), txpool.TxEventReinjected)
+	}
 	core.SenderCacher().Recover(pool.signer, reinject)
 	pool.addTxsLocked(reinject)
 }
@@ -1460,7 +1564,7 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 // invalidated transactions (low nonce, low balance) are deleted.
 func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.Transaction {
 	gasLimit := pool.currentHead.Load().GasLimit
-	promotable, dropped, removedAddresses := pool.queue.promoteExecutables(accounts, gasLimit, pool.currentState, pool.pendingNonces)
+	promotable, stale, dropped, removedAddresses := pool.queue.promoteExecutables(accounts, gasLimit, pool.currentState, pool.pendingNonces)
 
 	// promote all promotable transactions
 	promoted := make([]*types.Transaction, 0, len(promotable))
@@ -1472,10 +1576,15 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 	}
 
 	// remove all removable transactions
+	for _, hash := range stale {
+		pool.all.Remove(hash)
+		pool.lifecycle.Record(hash, txpool.TxEventStale)
+	}
 	for _, hash := range dropped {
 		pool.all.Remove(hash)
+		pool.lifecycle.Record(hash, txpool.TxEventDropped)
 	}
-	pool.priced.Removed(len(dropped))
+	pool.priced.Removed(len(stale) + len(dropped))
 
 	// release all accounts that have no more transactions in the pool
 	for _, addr := range removedAddresses {
@@ -1530,6 +1639,7 @@ func (pool *LegacyPool) truncatePending() {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
 						pool.all.Remove(hash)
+						pool.lifecycle.Record(hash, txpool.TxEventDropped)
 
 						// Update the account nonce to the dropped transaction
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
@@ -1555,6 +1665,7 @@ func (pool *LegacyPool) truncatePending() {
 					// Drop the transaction from the global pools too
 					hash := tx.Hash()
 					pool.all.Remove(hash)
+					pool.lifecycle.Record(hash, txpool.TxEventDropped)
 
 					// Update the account nonce to the dropped transaction
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
@@ -1576,6 +1687,7 @@ func (pool *LegacyPool) truncateQueue() {
 	// Remove all removable transactions from the lookup and global price list
 	for _, hash := range removed {
 		pool.all.Remove(hash)
+		pool.lifecycle.Record(hash, txpool.TxEventDropped)
 	}
 	pool.priced.Removed(len(removed))
 
@@ -1605,16 +1717,20 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		for _, tx := range olds {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.lifecycle.Record(hash, txpool.TxEventStale)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
+		pool.trackAdmission(addr, AdmissionIncluded, len(olds))
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), gasLimit)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.lifecycle.Record(hash, txpool.TxEventDropped)
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
+		pool.trackAdmission(addr, AdmissionDropped, len(drops))
 
 		for _, tx := range invalids {
 			hash := tx.Hash()
@@ -1647,6 +1763,24 @@ func (pool *LegacyPool) demoteUnexecutables() {
 	}
 }
 
+// evictConditionals removes all conditional transactions whose conditions can't
+// be satisfied anymore on top of the current head, e.g. because their block or
+// time bounds elapsed, or a known account's storage changed.
+func (pool *LegacyPool) evictConditionals() {
+	var evicted int
+	for _, tx := range pool.all.Conditionals() {
+		if err := pool.validateConditions(tx); err != nil {
+			hash := tx.Hash()
+			log.Trace("Removed conditional transaction", "hash", hash, "err", err)
+
+			pool.removeTx(hash, true, true)
+			pool.lifecycle.Record(hash, txpool.TxEventDropped)
+			evicted++
+		}
+	}
+	conditionalEvictionMeter.Mark(int64(evicted))
+}
+
 // accountSet is simply a set of addresses to check for existence, and a signer
 // capable of deriving addresses from transactions.
 type accountSet struct {
@@ -1710,7 +1844,8 @@ type lookup struct {
 	lock  sync.RWMutex
 	txs   map[common.Hash]*types.Transaction
 
-	auths map[common.Address][]common.Hash // All accounts with a pooled authorization
+	auths map[common.Address][]common.Hash   // All accounts with a pooled authorization
+	conds map[common.Hash]*types.Transaction // All transactions with attached conditions
 }
 
 // newLookup returns a new lookup structure.
@@ -1718,6 +1853,7 @@ func newLookup() *lookup {
 	return &lookup{
 		txs:   make(map[common.Hash]*types.Transaction),
 		auths: make(map[common.Address][]common.Hash),
+		conds: make(map[common.Hash]*types.Transaction),
 	}
 }
 
@@ -1769,6 +1905,9 @@ func (t *lookup) Add(tx *types.Transaction) {
 
 	t.txs[tx.Hash()] = tx
 	t.addAuthorities(tx)
+	if tx.Conditions() != nil {
+		t.conds[tx.Hash()] = tx
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1786,6 +1925,7 @@ func (t *lookup) Remove(hash common.Hash) {
 	slotsGauge.Update(int64(t.slots))
 
 	delete(t.txs, hash)
+	delete(t.conds, hash)
 }
 
 // Clear resets the lookup structure, removing all stored entries.
@@ -1796,6 +1936,15 @@ func (t *lookup) Clear() {
 	t.slots = 0
 	t.txs = make(map[common.Hash]*types.Transaction)
 	t.auths = make(map[common.Address][]common.Hash)
+	t.conds = make(map[common.Hash]*types.Transaction)
+}
+
+// Conditionals returns all transactions in the lookup with attached conditions.
+func (t *lookup) Conditionals() []*types.Transaction {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return slices.Collect(maps.Values(t.conds))
 }
 
 // TxsBelowTip finds all remote transactions below the given tip threshold.
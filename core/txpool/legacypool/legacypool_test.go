@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
@@ -2312,6 +2313,52 @@ func TestTransactionPendingReannouce(t *testing.T) {
 	}
 }
 
+// Tests that conditional transactions are rejected if their conditions can't be
+// met anymore, and evicted once a state change invalidates them.
+func TestConditionalTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	var (
+		account = crypto.PubkeyToAddress(key.PublicKey)
+		target  = common.HexToAddress("0xaa")
+		slot    = common.HexToHash("0x01")
+		max     = hexutil.Uint64(0)
+	)
+	testAddBalance(pool, account, big.NewInt(1000000))
+	pool.currentState.SetState(target, slot, common.HexToHash("0x01"))
+
+	// Transactions whose block bound already elapsed must be rejected
+	expired := transaction(0, 100000, key)
+	expired.SetConditions(&types.TransactionOpts{BlockNumberMax: &max})
+	if err := pool.addRemoteSync(expired); !errors.Is(err, txpool.ErrConditionsExpired) {
+		t.Fatalf("expired conditional transaction error mismatch: have %v, want %v", err, txpool.ErrConditionsExpired)
+	}
+	// Transactions with satisfied conditions should be accepted
+	tx := transaction(0, 100000, key)
+	tx.SetConditions(&types.TransactionOpts{KnownAccounts: types.KnownAccounts{
+		target: {StorageSlots: map[common.Hash]common.Hash{slot: common.HexToHash("0x01")}},
+	}})
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	// Invalidate the known account and ensure the transaction is evicted
+	pool.currentState.SetState(target, slot, common.HexToHash("0x02"))
+	<-pool.requestReset(nil, nil)
+
+	if pool.Has(tx.Hash()) {
+		t.Fatalf("invalidated conditional transaction not evicted")
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // TestSetCodeTransactions tests a few scenarios regarding the EIP-7702
 // SetCodeTx.
 func TestSetCodeTransactions(t *testing.T) {
@@ -2768,3 +2815,60 @@ func BenchmarkMultiAccountBatchInsert(b *testing.B) {
 		pool.addRemotesSync([]*types.Transaction{tx})
 	}
 }
+
+// Tests that transactions leaving the pending set or the queue as their nonces
+// got used on chain are recorded as stale, and only the ones the chain reports
+// in a block as included.
+func TestLifecycleInclusion(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	tracker := txpool.NewLifecycleTracker(time.Hour, nil)
+	pool.SetLifecycleTracker(tracker)
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(1000000000))
+
+	pending, queued := transaction(0, 100000, key), transaction(2, 100000, key)
+	if errs := pool.addRemotesSync([]*types.Transaction{pending, queued}); errs[0] != nil || errs[1] != nil {
+		t.Fatalf("failed to add transactions: %v", errs)
+	}
+	// Use up the nonces on chain and reset the pool on top
+	testSetNonce(pool, from, 3)
+	<-pool.requestReset(nil, nil)
+
+	if count := pool.all.Count(); count != 0 {
+		t.Fatalf("pool not emptied: %d transactions left", count)
+	}
+	// Only the pending transaction made it into the block, the queued one lost
+	// its nonce to another transaction
+	tracker.Included([]common.Hash{pending.Hash()}, 1, common.Hash{0x01})
+
+	for _, test := range []struct {
+		tx   *types.Transaction
+		want []txpool.TxLifecycleEventKind
+	}{
+		{pending, []txpool.TxLifecycleEventKind{txpool.TxEventQueued, txpool.TxEventPending, txpool.TxEventIncluded}},
+		{queued, []txpool.TxLifecycleEventKind{txpool.TxEventQueued, txpool.TxEventStale}},
+	} {
+		life := tracker.Get(test.tx.Hash())
+		if life == nil {
+			t.Fatalf("transaction %x not tracked", test.tx.Hash())
+		}
+		var kinds []txpool.TxLifecycleEventKind
+		for _, event := range life.Events {
+			kinds = append(kinds, event.Kind)
+		}
+		if !slices.Equal(kinds, test.want) {
+			t.Errorf("transaction %d: events mismatch: have %v, want %v", test.tx.Nonce(), kinds, test.want)
+		}
+	}
+	if life := tracker.Get(pending.Hash()); life.BlockNumber != 1 {
+		t.Errorf("inclusion block mismatch: have %d, want %d", life.BlockNumber, 1)
+	}
+	if life := tracker.Get(queued.Hash()); life.BlockNumber != 0 {
+		t.Errorf("stale transaction marked included in block %d", life.BlockNumber)
+	}
+}
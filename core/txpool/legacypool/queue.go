@@ -149,14 +149,16 @@ func (q *queue) add(tx *types.Transaction) (*common.Hash, error) {
 // for promotion any that are now executable. It also drops any transactions that are
 // deemed too old (nonce too low) or too costly (insufficient funds or over gas limit).
 //
-// Returns three lists:
+// Returns four lists:
 // - all transactions that were removed from the queue and selected for promotion;
+// - all transactions that were removed from the queue as their nonce was used on chain;
 // - all other transactions that were removed from the queue and dropped;
 // - the list of addresses removed.
-func (q *queue) promoteExecutables(accounts []common.Address, gasLimit uint64, currentState *state.StateDB, nonces *noncer) ([]*types.Transaction, []common.Hash, []common.Address) {
+func (q *queue) promoteExecutables(accounts []common.Address, gasLimit uint64, currentState *state.StateDB, nonces *noncer) ([]*types.Transaction, []common.Hash, []common.Hash, []common.Address) {
 	// Track the promotable transactions to broadcast them at once
 	var (
 		promotable       []*types.Transaction
+		stale            []common.Hash
 		dropped          []common.Hash
 		removedAddresses []common.Address
 	)
@@ -169,7 +171,7 @@ func (q *queue) promoteExecutables(accounts []common.Address, gasLimit uint64, c
 		// Drop all transactions that are deemed too old (low nonce)
 		forwards := list.Forward(currentState.GetNonce(addr))
 		for _, tx := range forwards {
-			dropped = append(dropped, tx.Hash())
+			stale = append(stale, tx.Hash())
 		}
 		log.Trace("Removing old queued transactions", "count", len(forwards))
 
@@ -203,8 +205,8 @@ func (q *queue) promoteExecutables(accounts []common.Address, gasLimit uint64, c
 			removedAddresses = append(removedAddresses, addr)
 		}
 	}
-	queuedGauge.Dec(int64(len(dropped)))
-	return promotable, dropped, removedAddresses
+	queuedGauge.Dec(int64(len(stale) + len(dropped)))
+	return promotable, stale, dropped, removedAddresses
 }
 
 // truncate drops the oldest transactions from the queue until the total
@@ -0,0 +1,341 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// lifecycleMaxEvents is the maximum number of pool events retained for a
+	// single transaction. Anything beyond is dropped to avoid a churning tx
+	// (e.g. one bouncing between pending and queued) from bloating memory.
+	lifecycleMaxEvents = 32
+
+	// lifecycleMaxEntries is the maximum number of transactions tracked at any
+	// point in time, independent of the retention window.
+	lifecycleMaxEntries = 1 << 20
+
+	// lifecyclePruneInterval is the time interval to evict expired entries.
+	lifecyclePruneInterval = time.Minute
+
+	// chainEventChanSize is the size of channel listening to ChainEvent.
+	chainEventChanSize = 64
+)
+
+var lifecycleGauge = metrics.NewRegisteredGauge("txpool/lifecycle", nil)
+
+// TxOrigin denotes how the local node first learned about a transaction.
+type TxOrigin uint8
+
+const (
+	TxOriginUnknown      TxOrigin = iota // Origin not recorded (e.g. reinjected after reorg)
+	TxOriginLocal                        // Submitted via the local RPC
+	TxOriginBroadcast                    // Received as a full transaction broadcast
+	TxOriginAnnouncement                 // Retrieved after a hash announcement
+)
+
+// String implements fmt.Stringer.
+func (o TxOrigin) String() string {
+	switch o {
+	case TxOriginLocal:
+		return "local"
+	case TxOriginBroadcast:
+		return "broadcast"
+	case TxOriginAnnouncement:
+		return "announcement"
+	default:
+		return "unknown"
+	}
+}
+
+// TxLifecycleEventKind is the type of transition a transaction went through
+// while being tracked by the pool.
+type TxLifecycleEventKind string
+
+const (
+	TxEventQueued     TxLifecycleEventKind = "queued"     // Added to the non-executable queue
+	TxEventPending    TxLifecycleEventKind = "pending"    // Promoted into the executable set
+	TxEventDemoted    TxLifecycleEventKind = "demoted"    // Moved back from pending into the queue
+	TxEventReplaced   TxLifecycleEventKind = "replaced"   // Replaced by a higher priced transaction
+	TxEventDropped    TxLifecycleEventKind = "dropped"    // Evicted from the pool
+	TxEventReinjected TxLifecycleEventKind = "reinjected" // Reinjected after a chain reorg
+	TxEventStale      TxLifecycleEventKind = "stale"      // Removed as its nonce was used on chain
+	TxEventIncluded   TxLifecycleEventKind = "included"   // Included in a canonical block
+)
+
+// TxLifecycleEvent is a single transition in the lifecycle of a transaction.
+type TxLifecycleEvent struct {
+	Kind TxLifecycleEventKind
+	Time time.Time
+}
+
+// TxLifecycle contains everything the node observed about a transaction, from
+// the moment it was first seen until its inclusion in a block.
+type TxLifecycle struct {
+	Hash      common.Hash
+	FirstSeen time.Time
+	Origin    TxOrigin
+	Peer      string // ID of the peer the transaction was first received from
+
+	Events []TxLifecycleEvent
+
+	BlockNumber uint64      // Number of the block including the transaction, zero if not included yet
+	BlockHash   common.Hash // Hash of the block including the transaction, empty if not included yet
+	IncludedAt  time.Time   // Local time at which the including block was imported
+}
+
+// copy returns a deep copy of the lifecycle, safe for use outside the tracker.
+func (l *TxLifecycle) copy() *TxLifecycle {
+	cpy := *l
+	cpy.Events = slices.Clone(l.Events)
+	return &cpy
+}
+
+// lifecycleChain defines the minimal set of methods needed to back the
+// lifecycle tracker with a chain.
+type lifecycleChain interface {
+	// SubscribeChainEvent subscribes to new blocks being added to the chain.
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+}
+
+// LifecycleTracker records the first-seen time, origin, pool transitions and
+// block inclusion of transactions, retaining them for a configurable window.
+//
+// All methods are safe to be called on a nil tracker, in which case they are
+// no-ops. This allows the pools to unconditionally report into it.
+type LifecycleTracker struct {
+	retention time.Duration
+	chain     lifecycleChain
+
+	entries map[common.Hash]*list.Element // Tracked lifecycles, indexing into order
+	order   *list.List                    // Tracked lifecycles ordered by first-seen time
+	lock    sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewLifecycleTracker creates a tracker retaining lifecycles for the given
+// duration. Block inclusion is tracked by subscribing to the given chain.
+func NewLifecycleTracker(retention time.Duration, chain lifecycleChain) *LifecycleTracker {
+	return &LifecycleTracker{
+		retention: retention,
+		chain:     chain,
+		entries:   make(map[common.Hash]*list.Element),
+		order:     list.New(),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start implements node.Lifecycle, spinning up the inclusion tracking and
+// eviction loop.
+func (t *LifecycleTracker) Start() error {
+	t.wg.Add(1)
+	go t.loop()
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating all background goroutines.
+func (t *LifecycleTracker) Stop() error {
+	close(t.quit)
+	t.wg.Wait()
+	return nil
+}
+
+// loop marks transactions as included when new blocks arrive and periodically
+// evicts lifecycles which fell out of the retention window.
+func (t *LifecycleTracker) loop() {
+	defer t.wg.Done()
+
+	var (
+		chainCh  = make(chan core.ChainEvent, chainEventChanSize)
+		chainSub = t.chain.SubscribeChainEvent(chainCh)
+		prune    = time.NewTicker(lifecyclePruneInterval)
+	)
+	defer chainSub.Unsubscribe()
+	defer prune.Stop()
+
+	for {
+		select {
+		case ev := <-chainCh:
+			hashes := make([]common.Hash, len(ev.Transactions))
+			for i, tx := range ev.Transactions {
+				hashes[i] = tx.Hash()
+			}
+			t.Included(hashes, ev.Header.Number.Uint64(), ev.Header.Hash())
+
+		case <-prune.C:
+			t.prune(time.Now())
+
+		case <-chainSub.Err():
+			return
+
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// Seen records the first sighting of a batch of transactions. Transactions
+// already being tracked retain their original first-seen time and origin.
+func (t *LifecycleTracker) Seen(hashes []common.Hash, peer string, origin TxOrigin) {
+	if t == nil || len(hashes) == 0 {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := time.Now()
+	for _, hash := range hashes {
+		if _, ok := t.entries[hash]; ok {
+			continue
+		}
+		t.track(&TxLifecycle{
+			Hash:      hash,
+			FirstSeen: now,
+			Origin:    origin,
+			Peer:      peer,
+		})
+	}
+}
+
+// Record appends a pool transition to the lifecycle of a transaction. If the
+// transaction was not seen before, it's tracked with an unknown origin.
+func (t *LifecycleTracker) Record(hash common.Hash, kind TxLifecycleEventKind) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.record(hash, kind, time.Now())
+}
+
+// Included marks a batch of transactions as included in the given block.
+// Only transactions that are already tracked are updated, system transactions
+// and those included before the node saw them are ignored.
+//
+// The pool removes the transactions of a new block as stale, not knowing which
+// one used up the nonce. For the included ones, that removal is superseded.
+func (t *LifecycleTracker) Included(hashes []common.Hash, number uint64, blockHash common.Hash) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := time.Now()
+	for _, hash := range hashes {
+		elem, ok := t.entries[hash]
+		if !ok {
+			continue
+		}
+		entry := elem.Value.(*TxLifecycle)
+		entry.BlockNumber = number
+		entry.BlockHash = blockHash
+		entry.IncludedAt = now
+		if n := len(entry.Events); n > 0 && entry.Events[n-1].Kind == TxEventStale {
+			entry.Events = entry.Events[:n-1]
+		}
+		t.record(hash, TxEventIncluded, now)
+	}
+}
+
+// Get retrieves the lifecycle of a transaction, or nil if it's not tracked.
+func (t *LifecycleTracker) Get(hash common.Hash) *TxLifecycle {
+	if t == nil {
+		return nil
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	elem, ok := t.entries[hash]
+	if !ok {
+		return nil
+	}
+	return elem.Value.(*TxLifecycle).copy()
+}
+
+// record appends an event to a tracked lifecycle, starting to track it if it
+// is unknown yet.
+//
+// Note, this method assumes the tracker lock is held!
+func (t *LifecycleTracker) record(hash common.Hash, kind TxLifecycleEventKind, now time.Time) {
+	elem, ok := t.entries[hash]
+	if !ok {
+		elem = t.track(&TxLifecycle{Hash: hash, FirstSeen: now})
+	}
+	entry := elem.Value.(*TxLifecycle)
+	if len(entry.Events) >= lifecycleMaxEvents {
+		return
+	}
+	// The chain may report an inclusion before the pool removes the transaction
+	if kind == TxEventStale && !entry.IncludedAt.IsZero() {
+		return
+	}
+	entry.Events = append(entry.Events, TxLifecycleEvent{Kind: kind, Time: now})
+}
+
+// track inserts a new lifecycle, evicting the oldest one if the tracker is
+// at capacity.
+//
+// Note, this method assumes the tracker lock is held!
+func (t *LifecycleTracker) track(entry *TxLifecycle) *list.Element {
+	if t.order.Len() >= lifecycleMaxEntries {
+		oldest := t.order.Front()
+		delete(t.entries, oldest.Value.(*TxLifecycle).Hash)
+		t.order.Remove(oldest)
+	}
+	elem := t.order.PushBack(entry)
+	t.entries[entry.Hash] = elem
+	lifecycleGauge.Update(int64(t.order.Len()))
+	return elem
+}
+
+// prune evicts all lifecycles first seen before the retention window.
+func (t *LifecycleTracker) prune(now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var (
+		cutoff = now.Add(-t.retention)
+		pruned int
+	)
+	for elem := t.order.Front(); elem != nil; elem = t.order.Front() {
+		entry := elem.Value.(*TxLifecycle)
+		if !entry.FirstSeen.Before(cutoff) {
+			break
+		}
+		delete(t.entries, entry.Hash)
+		t.order.Remove(elem)
+		pruned++
+	}
+	lifecycleGauge.Update(int64(t.order.Len()))
+	if pruned > 0 {
+		log.Debug("Pruned transaction lifecycles", "count", pruned, "remaining", t.order.Len())
+	}
+}
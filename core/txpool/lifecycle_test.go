@@ -0,0 +1,111 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that the lifecycle tracker retains the first sighting of a transaction,
+// records pool transitions and block inclusion, and prunes expired entries.
+func TestLifecycleTracker(t *testing.T) {
+	var (
+		tracker = NewLifecycleTracker(time.Hour, nil)
+		tx1     = common.HexToHash("0x01")
+		tx2     = common.HexToHash("0x02")
+		block   = common.HexToHash("0xb1")
+	)
+	tracker.Seen([]common.Hash{tx1}, "peer-a", TxOriginAnnouncement)
+	tracker.Seen([]common.Hash{tx1}, "peer-b", TxOriginBroadcast)
+	tracker.Record(tx1, TxEventQueued)
+	tracker.Record(tx1, TxEventPending)
+	tracker.Included([]common.Hash{tx1, tx2}, 100, block)
+
+	life := tracker.Get(tx1)
+	if life == nil {
+		t.Fatalf("tracked transaction missing")
+	}
+	if life.Peer != "peer-a" || life.Origin != TxOriginAnnouncement {
+		t.Errorf("first sighting overwritten: have %s/%v, want %s/%v", life.Peer, life.Origin, "peer-a", TxOriginAnnouncement)
+	}
+	want := []TxLifecycleEventKind{TxEventQueued, TxEventPending, TxEventIncluded}
+	if len(life.Events) != len(want) {
+		t.Fatalf("event count mismatch: have %d, want %d", len(life.Events), len(want))
+	}
+	for i, event := range life.Events {
+		if event.Kind != want[i] {
+			t.Errorf("event %d mismatch: have %s, want %s", i, event.Kind, want[i])
+		}
+	}
+	if life.BlockNumber != 100 || life.BlockHash != block {
+		t.Errorf("inclusion mismatch: have %d/%x, want %d/%x", life.BlockNumber, life.BlockHash, 100, block)
+	}
+	// Inclusion of untracked transactions should not start tracking them
+	if tracker.Get(tx2) != nil {
+		t.Errorf("untracked transaction tracked on inclusion")
+	}
+	// Returned lifecycles must not alias the internal state
+	life.Events[0].Kind = TxEventDropped
+	if tracker.Get(tx1).Events[0].Kind != TxEventQueued {
+		t.Errorf("lifecycle aliases internal state")
+	}
+	// Anything first seen before the retention window should be evicted
+	tracker.prune(time.Now().Add(2 * time.Hour))
+	if tracker.Get(tx1) != nil {
+		t.Errorf("expired transaction not pruned")
+	}
+}
+
+// Tests that a stale removal by the pool is superseded by the chain reporting
+// the transaction in a block, regardless of which is observed first.
+func TestLifecycleTrackerStale(t *testing.T) {
+	var (
+		tracker = NewLifecycleTracker(time.Hour, nil)
+		tx1     = common.HexToHash("0x01")
+		tx2     = common.HexToHash("0x02")
+		tx3     = common.HexToHash("0x03")
+	)
+	for _, hash := range []common.Hash{tx1, tx2, tx3} {
+		tracker.Record(hash, TxEventPending)
+	}
+	tracker.Record(tx1, TxEventStale)
+	tracker.Included([]common.Hash{tx1, tx2}, 1, common.Hash{0x01})
+	tracker.Record(tx2, TxEventStale)
+	tracker.Record(tx3, TxEventStale)
+
+	for hash, want := range map[common.Hash]TxLifecycleEventKind{tx1: TxEventIncluded, tx2: TxEventIncluded, tx3: TxEventStale} {
+		events := tracker.Get(hash).Events
+		if len(events) != 2 || events[1].Kind != want {
+			t.Errorf("transaction %x: events mismatch: have %v, want [pending %s]", hash, events, want)
+		}
+	}
+}
+
+// Tests that a nil tracker is usable as a no-op.
+func TestLifecycleTrackerNil(t *testing.T) {
+	var tracker *LifecycleTracker
+
+	tracker.Seen([]common.Hash{{0x01}}, "", TxOriginLocal)
+	tracker.Record(common.Hash{0x01}, TxEventQueued)
+	tracker.Included([]common.Hash{{0x01}}, 1, common.Hash{})
+	if tracker.Get(common.Hash{0x01}) != nil {
+		t.Errorf("nil tracker returned lifecycle")
+	}
+}
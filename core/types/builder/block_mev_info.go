@@ -8,6 +8,7 @@ package builder
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 const blockMevInfoVersionOffset = common.HashLength - common.AddressLength - 1 // 11
@@ -23,6 +24,19 @@ const (
 	BlockMevInfoVersionBidBlock BlockMevInfoVersion = 2
 )
 
+// String returns the name of the version exposed over the APIs: "v1" for
+// legacy SendBid and "v2" for BEP-675 SendBidBlock.
+func (v BlockMevInfoVersion) String() string {
+	switch v {
+	case BlockMevInfoVersionBid:
+		return "v1"
+	case BlockMevInfoVersionBidBlock:
+		return "v2"
+	default:
+		return ""
+	}
+}
+
 // EncodeBlockMevInfo packs (version, builder) into a 32-byte hash suitable for
 // header.RequestsHash. Layout:
 //
@@ -57,3 +71,12 @@ func DecodeBlockMevInfo(h common.Hash) (version BlockMevInfoVersion, builder com
 	}
 	return v, builder, true
 }
+
+// HeaderMevInfo recovers the MEV source and builder tagged into a header.
+// ok=false means callers should treat the block as local.
+func HeaderMevInfo(header *types.Header) (version BlockMevInfoVersion, builder common.Address, ok bool) {
+	if header.RequestsHash == nil {
+		return 0, common.Address{}, false
+	}
+	return DecodeBlockMevInfo(*header.RequestsHash)
+}
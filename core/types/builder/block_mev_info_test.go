@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 func TestBlockMevInfoEncodeDecode(t *testing.T) {
@@ -48,3 +49,23 @@ func TestBlockMevInfoEncodeDecode(t *testing.T) {
 		}
 	})
 }
+
+func TestHeaderMevInfo(t *testing.T) {
+	builder := common.HexToAddress("0x317aB60A0815F8Db2e6cb3f302C152d2A5ef4854")
+
+	tagged := EncodeBlockMevInfo(BlockMevInfoVersionBidBlock, builder)
+	version, got, ok := HeaderMevInfo(&types.Header{RequestsHash: &tagged})
+	if !ok || version.String() != "v2" || got != builder {
+		t.Fatalf("tagged header: got (v=%s, builder=%s, ok=%v), want (v2, %s, true)", version, got.Hex(), ok, builder.Hex())
+	}
+	if BlockMevInfoVersionBid.String() != "v1" {
+		t.Fatalf("bid version name: got %s, want v1", BlockMevInfoVersionBid)
+	}
+	// Headers without requests hash or with the default one are local.
+	empty := types.EmptyRequestsHash
+	for _, header := range []*types.Header{{}, {RequestsHash: &empty}} {
+		if _, _, ok := HeaderMevInfo(header); ok {
+			t.Errorf("untagged header: ok=true, want false")
+		}
+	}
+}
@@ -57,6 +57,8 @@ type Transaction struct {
 	inner TxData    // Consensus contents of a transaction
 	time  time.Time // Time first seen locally (spam avoidance)
 
+	conditions *TransactionOpts // Inclusion conditions attached locally (not part of consensus)
+
 	// caches
 	hash atomic.Pointer[common.Hash]
 	size atomic.Uint64
@@ -582,6 +584,19 @@ func (tx *Transaction) Time() time.Time {
 	return tx.time
 }
 
+// SetConditions attaches the inclusion conditions submitted together with the
+// transaction (eth_sendRawTransactionConditional). They are not part of the
+// consensus encoding and are only enforced by the local pool and miner.
+func (tx *Transaction) SetConditions(opts *TransactionOpts) {
+	tx.conditions = opts
+}
+
+// Conditions returns the inclusion conditions attached to the transaction, or
+// nil if it's unconditional.
+func (tx *Transaction) Conditions() *TransactionOpts {
+	return tx.conditions
+}
+
 // Hash returns the transaction hash.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
@@ -35,6 +35,7 @@ type Config struct {
 	EnablePreimageRecording   bool  // Enables recording of SHA3/keccak preimages
 	ExtraEips                 []int // Additional EIPS that are to be enabled
 	EnableOpcodeOptimizations bool  // Enable opcode optimization
+	CheckOpcodeOptimizations  bool  // Cross-check optimized execution against the plain interpreter
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -5,7 +5,8 @@ import "strings"
 // superInstructionMap maps super-instruction opcodes to the slice of ordinary opcodes
 // they were fused from.  The mapping comes from the fusion patterns implemented in
 // core/opcodeCompiler/compiler/opCodeProcessor.go (applyFusionPatterns).  When that file
-// is updated with new fusion rules, this map should be kept in sync.
+// is updated with new fusion rules, this map and the optimizer's rule table in
+// core/opcodeCompiler/compiler/opCodeRules.go should be kept in sync.
 var superInstructionMap = map[OpCode][]OpCode{
 	AndSwap1PopSwap2Swap1: {AND, SWAP1, POP, SWAP2, SWAP1},
 	Swap2Swap1PopJump:     {SWAP2, SWAP1, POP, JUMP},
@@ -0,0 +1,127 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/opcodeCompiler/compiler"
+)
+
+// Tests that the super-instructions decomposed by the interpreter match the rule
+// table the optimizer version is derived from.
+func TestSuperInstructionRules(t *testing.T) {
+	for op := 0; op < 256; op++ {
+		seq, ok := DecomposeSuperInstruction(OpCode(op))
+		rule, ruleOk := compiler.FusionRule(compiler.ByteCode(op))
+		if ok != ruleOk {
+			t.Errorf("%v: super-instruction mismatch: interpreter %v, optimizer %v", OpCode(op), ok, ruleOk)
+			continue
+		}
+		want := make([]compiler.ByteCode, len(seq))
+		for i, code := range seq {
+			want[i] = compiler.ByteCode(code)
+		}
+		if !slices.Equal(rule, want) {
+			t.Errorf("%v: sequence mismatch: interpreter %v, optimizer %v", OpCode(op), want, rule)
+		}
+	}
+}
+
+// Tests that the rule table the optimizer version is derived from matches what
+// the fusion actually does: every rule sequence gets fused into its instruction,
+// and every fused instruction replaces the sequence of its rule.
+func TestSuperInstructionFusion(t *testing.T) {
+	var rules [][]compiler.ByteCode
+	for op := 0; op < 256; op++ {
+		rule, ok := compiler.FusionRule(compiler.ByteCode(op))
+		if !ok {
+			continue
+		}
+		rules = append(rules, rule)
+
+		// The fusion only applies to the entry and jump target blocks, and may
+		// look ahead a few bytes past the sequence
+		code := append(assembleRule(rule), make([]byte, 16)...)
+		fused, err := compiler.DoCFGBasedOpcodeFusion(code)
+		if err != nil {
+			t.Fatalf("%v: fusion failed: %v", OpCode(op), err)
+		}
+		if fused[0] != byte(op) {
+			t.Errorf("%v: rule sequence %x fused into %v", OpCode(op), code, OpCode(fused[0]))
+		}
+	}
+	// Fuse random programs sprinkled with rule sequences and check every fused
+	// instruction against the code it replaced
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		var code []byte
+		for len(code) < 256 {
+			if rng.Intn(2) == 0 {
+				code = append(code, assembleRule(rules[rng.Intn(len(rules))])...)
+			} else {
+				code = append(code, byte(rng.Intn(0xa0)))
+			}
+		}
+		fused, err := compiler.DoCFGBasedOpcodeFusion(code)
+		if err != nil {
+			continue
+		}
+		for pc := 0; pc < len(code); pc++ {
+			if fused[pc] == code[pc] {
+				continue
+			}
+			rule, ok := compiler.FusionRule(compiler.ByteCode(fused[pc]))
+			if !ok {
+				continue // Nop filler of an earlier fusion
+			}
+			if !matchesRule(code[pc:], rule) {
+				t.Fatalf("%v fused over %x, rule %v", OpCode(fused[pc]), code[pc:min(pc+16, len(code))], rule)
+			}
+		}
+	}
+}
+
+// assembleRule returns the bytecode of a rule sequence, with immediates for
+// its PUSH opcodes.
+func assembleRule(rule []compiler.ByteCode) []byte {
+	var code []byte
+	for _, op := range rule {
+		code = append(code, byte(op))
+		if OpCode(op).IsPush() {
+			code = append(code, make([]byte, op-compiler.PUSH1+1)...)
+		}
+	}
+	return code
+}
+
+// matchesRule reports whether the bytecode starts with the given rule sequence.
+func matchesRule(code []byte, rule []compiler.ByteCode) bool {
+	var pc int
+	for _, op := range rule {
+		if pc >= len(code) || compiler.ByteCode(code[pc]) != op {
+			return false
+		}
+		pc++
+		if OpCode(op).IsPush() {
+			pc += int(op - compiler.PUSH1 + 1)
+		}
+	}
+	return true
+}
@@ -21,6 +21,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -223,3 +224,37 @@ func Call(address common.Address, input []byte, cfg *Config) ([]byte, uint64, er
 	}
 	return ret, leftOverGas, err
 }
+
+// CheckOpcodeOptimization executes the code with both the optimized and the
+// plain interpreter on copies of the same state, returning the first divergence
+// between the two, or nil if they behave identically. The optimizer must have
+// been enabled by the caller, otherwise nothing is checked.
+func CheckOpcodeOptimization(code, input []byte, cfg *Config) *core.OpcodeMismatch {
+	if cfg == nil {
+		cfg = new(Config)
+	}
+	setDefaults(cfg)
+
+	if cfg.State == nil {
+		cfg.State, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	}
+	address := common.BytesToAddress([]byte("contract"))
+	cfg.State.CreateAccount(address)
+	cfg.State.SetCode(address, code, tracing.CodeChangeUnspecified)
+
+	msg := &core.Message{
+		To:                    &address,
+		From:                  cfg.Origin,
+		Value:                 cfg.Value,
+		GasLimit:              cfg.GasLimit,
+		GasPrice:              cfg.GasPrice,
+		GasFeeCap:             cfg.GasPrice,
+		GasTipCap:             cfg.GasPrice,
+		Data:                  input,
+		SkipNonceChecks:       true,
+		SkipTransactionChecks: true,
+	}
+	evmConfig := cfg.EVMConfig
+	evmConfig.NoBaseFee = true
+	return core.CheckOpcodeOptimization(msg, cfg.State, NewEnv(cfg).Context, cfg.ChainConfig, evmConfig)
+}
@@ -18,6 +18,9 @@ package runtime
 
 import (
 	"testing"
+
+	"github.com/ethereum/go-ethereum/core/opcodeCompiler/compiler"
+	"github.com/ethereum/go-ethereum/core/vm"
 )
 
 func FuzzVmRuntime(f *testing.F) {
@@ -27,3 +30,49 @@ func FuzzVmRuntime(f *testing.F) {
 		})
 	})
 }
+
+func FuzzOpcodeOptimization(f *testing.F) {
+	// Seed with a program for every super-instruction the optimizer may fuse
+	for op := 0; op < 256; op++ {
+		if seq, ok := vm.DecomposeSuperInstruction(vm.OpCode(op)); ok {
+			f.Add(superInstructionProgram(seq), []byte{})
+		}
+	}
+	f.Fuzz(func(t *testing.T, code, input []byte) {
+		if !compiler.IsEnabled() {
+			compiler.EnableOptimization()
+			t.Cleanup(compiler.DisableOptimization)
+		}
+		if mismatch := CheckOpcodeOptimization(code, input, &Config{GasLimit: 12000000}); mismatch != nil {
+			t.Fatalf("code %x: %v", code, mismatch)
+		}
+	})
+}
+
+// superInstructionProgram assembles a program running the given opcode sequence
+// in the entry block, where the optimizer fuses it. The stack is filled first so
+// the sequence doesn't underflow, and jumps land on a JUMPDEST behind it.
+func superInstructionProgram(seq []vm.OpCode) []byte {
+	var code []byte
+	for i := 1; i <= 12; i++ {
+		code = append(code, byte(vm.PUSH1), byte(i))
+	}
+	var targets []int // Offsets of the PUSH2 immediates to point at the JUMPDEST
+	for _, op := range seq {
+		code = append(code, byte(op))
+		switch {
+		case op == vm.PUSH2:
+			targets = append(targets, len(code))
+			code = append(code, 0, 0)
+		case op.IsPush():
+			for i := vm.PUSH1; i <= op; i++ {
+				code = append(code, 0x04)
+			}
+		}
+	}
+	dest := len(code)
+	for _, target := range targets {
+		code[target], code[target+1] = byte(dest>>8), byte(dest)
+	}
+	return append(code, byte(vm.JUMPDEST), byte(vm.STOP))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"slices"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -51,6 +52,9 @@ type VoteManager struct {
 	syncVoteCh  chan core.NewVoteEvent
 	syncVoteSub event.Subscription
 
+	// heads checked for the inclusion of local votes, off the voting path
+	inclusionCh chan *types.Header
+
 	pool    *VotePool
 	signer  *VoteSigner
 	journal *VoteJournal
@@ -64,6 +68,7 @@ func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journal
 		chain:                  chain,
 		highestVerifiedBlockCh: make(chan core.HighestVerifiedBlockEvent, highestVerifiedBlockChanSize),
 		syncVoteCh:             make(chan core.NewVoteEvent, voteBufferForPut),
+		inclusionCh:            make(chan *types.Header, highestVerifiedBlockChanSize),
 		pool:                   pool,
 		engine:                 engine,
 	}
@@ -90,12 +95,14 @@ func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journal
 	voteManager.syncVoteSub = voteManager.pool.SubscribeNewVoteEvent(voteManager.syncVoteCh)
 
 	go voteManager.loop()
+	go voteManager.inclusionLoop()
 
 	return voteManager, nil
 }
 
 func (voteManager *VoteManager) loop() {
 	log.Debug("vote manager routine loop started")
+	defer close(voteManager.inclusionCh)
 	defer voteManager.highestVerifiedBlockSub.Unsubscribe()
 	defer voteManager.syncVoteSub.Unsubscribe()
 
@@ -131,6 +138,7 @@ func (voteManager *VoteManager) loop() {
 				startVote = true
 			}
 		case cHead := <-voteManager.highestVerifiedBlockCh:
+			voteManager.queueInclusion(cHead.Header)
 			if !startVote {
 				log.Debug("startVote flag is false, continue")
 				continue
@@ -208,6 +216,7 @@ func (voteManager *VoteManager) loop() {
 				}
 
 				log.Debug("vote manager produced vote", "votedBlockNumber", voteMessage.Data.TargetNumber, "votedBlockHash", voteMessage.Data.TargetHash, "voteMessageHash", voteMessage.Hash())
+				voteManager.pool.timings.signed(voteMessage)
 				voteManager.pool.PutVote(voteMessage)
 				voteManager.chain.GetBlockStats(curHead.Hash()).SendVoteTime.Store(time.Now().UnixMilli())
 				votesManagerCounter.Inc(1)
@@ -264,6 +273,45 @@ func (voteManager *VoteManager) loop() {
 	}
 }
 
+// queueInclusion hands a new head over to the inclusion tracking. Heads are
+// skipped if the tracking falls behind, it must never delay the voting.
+func (voteManager *VoteManager) queueInclusion(header *types.Header) {
+	if !voteManager.pool.timings.awaitingInclusion() {
+		return
+	}
+	select {
+	case voteManager.inclusionCh <- header:
+	default:
+	}
+}
+
+// inclusionLoop tracks the inclusion of local votes in the queued heads until
+// the vote manager loop terminates.
+func (voteManager *VoteManager) inclusionLoop() {
+	for header := range voteManager.inclusionCh {
+		voteManager.trackInclusion(header)
+	}
+}
+
+// trackInclusion checks whether the vote attestation carried in the header
+// includes a vote of the local validator, recording its inclusion.
+func (voteManager *VoteManager) trackInclusion(header *types.Header) {
+	if header == nil || !voteManager.pool.timings.awaitingInclusion() {
+		return
+	}
+	p, ok := voteManager.engine.(*parlia.Parlia)
+	if !ok {
+		return
+	}
+	attestation, voters, err := p.GetVoteAttestation(voteManager.chain, header)
+	if err != nil || attestation == nil {
+		return
+	}
+	if slices.Contains(voters, p.ConsensusAddress()) {
+		voteManager.pool.timings.included(attestation.Data.TargetHash, header.Hash())
+	}
+}
+
 // UnderRules checks if the produced header under the following rules:
 // A validator must not publish two distinct votes for the same height. (Rule 1)
 // A validator must not vote within the span of its other votes . (Rule 2)
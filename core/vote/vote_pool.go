@@ -81,6 +81,8 @@ type VotePool struct {
 	quit    chan struct{}
 
 	engine consensus.PoSA
+
+	timings *voteTimings // Propagation timings of the local votes
 }
 
 type votesPriorityQueue []*types.VoteData
@@ -97,6 +99,7 @@ func NewVotePool(chain *core.BlockChain, engine consensus.PoSA) *VotePool {
 		votesCh:                make(chan *types.VoteEnvelope, voteBufferForPut),
 		quit:                   make(chan struct{}),
 		engine:                 engine,
+		timings:                newVoteTimings(),
 	}
 
 	// Subscribe events from blockchain and start the main event loop.
@@ -191,6 +194,30 @@ func (pool *VotePool) putIntoVotePool(vote *types.VoteEnvelope) bool {
 	return true
 }
 
+// IsLocalVote reports whether the vote was signed by the local validator.
+func (pool *VotePool) IsLocalVote(hash common.Hash) bool {
+	return pool.timings.local(hash)
+}
+
+// MarkVoteRelayed records the first broadcast of a vote to the given number of
+// peers, of which targeted were the peers of upcoming proposers. Only the votes
+// of the local validator are tracked.
+func (pool *VotePool) MarkVoteRelayed(hash common.Hash, peers int, targeted int) {
+	pool.timings.relayed(hash, peers, targeted)
+}
+
+// MarkVoteSeen records a vote received from a peer. Only the votes of the local
+// validator are tracked.
+func (pool *VotePool) MarkVoteSeen(hash common.Hash) {
+	pool.timings.seen(hash)
+}
+
+// VoteTimings returns the propagation timings of the recent local votes, most
+// recent first.
+func (pool *VotePool) VoteTimings() []*VoteTiming {
+	return pool.timings.list()
+}
+
 func (pool *VotePool) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Subscription {
 	return pool.scope.Track(pool.votesFeed.Subscribe(ch))
 }
@@ -360,6 +387,28 @@ func (pool *VotePool) GetVotes() []*types.VoteEnvelope {
 	return votesRes
 }
 
+// HighestVotedBlock returns the number and hash of the highest block that at least
+// quorum votes were received for. False is returned if there's no such block.
+func (pool *VotePool) HighestVotedBlock(quorum int) (uint64, common.Hash, bool) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	var (
+		number uint64
+		hash   common.Hash
+		found  bool
+	)
+	for _, voteBox := range pool.curVotes {
+		if len(voteBox.voteMessages) < quorum {
+			continue
+		}
+		if !found || voteBox.blockNumber > number {
+			number, hash, found = voteBox.blockNumber, voteBox.blockHash, true
+		}
+	}
+	return number, hash, found
+}
+
 func (pool *VotePool) FetchVotesByBlockHash(targetBlockHash common.Hash, sourceBlockNum uint64) []*types.VoteEnvelope {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
@@ -0,0 +1,162 @@
+package vote
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// maxVoteTimings is the number of local votes whose propagation is tracked,
+// matching the range of blocks the pool keeps votes for.
+const maxVoteTimings = lowerLimitOfVoteBlockNumber
+
+var (
+	voteRelayTimer    = metrics.NewRegisteredTimer("votesManager/delay/relay", nil)
+	voteSeenBackTimer = metrics.NewRegisteredTimer("votesManager/delay/seenBack", nil)
+	voteIncludedTimer = metrics.NewRegisteredTimer("votesManager/delay/included", nil)
+)
+
+// VoteTiming records the propagation milestones of a vote signed by the local
+// validator. The delays are measured from the signing of the vote.
+type VoteTiming struct {
+	Hash         common.Hash `json:"hash"`
+	TargetNumber uint64      `json:"targetNumber"`
+	TargetHash   common.Hash `json:"targetHash"`
+	Signed       time.Time   `json:"signed"`
+
+	FirstRelayed  time.Duration `json:"firstRelayed,omitempty"`  // Delay until the vote was first sent to peers
+	RelayPeers    int           `json:"relayPeers,omitempty"`    // Number of peers the vote was first sent to
+	Targeted      int           `json:"targeted,omitempty"`      // Number of upcoming proposer peers pushed first
+	SeenBack      time.Duration `json:"seenBack,omitempty"`      // Delay until a peer sent the vote back
+	SeenBackCount int           `json:"seenBackCount,omitempty"` // Number of times peers sent the vote back
+	Included      time.Duration `json:"included,omitempty"`      // Delay until a block attested the vote
+	IncludedIn    *common.Hash  `json:"includedIn,omitempty"`    // Block carrying the attestation
+}
+
+// voteTimings tracks the propagation of the most recent local votes.
+type voteTimings struct {
+	lock  sync.Mutex
+	votes map[common.Hash]*VoteTiming // Timings by vote hash
+	order []common.Hash               // Vote hashes in signing order, for eviction
+
+	pendingInclusion int // Number of tracked votes not attested yet
+}
+
+func newVoteTimings() *voteTimings {
+	return &voteTimings{votes: make(map[common.Hash]*VoteTiming)}
+}
+
+// signed starts tracking a vote signed by the local validator.
+func (t *voteTimings) signed(vote *types.VoteEnvelope) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	hash := vote.Hash()
+	if _, ok := t.votes[hash]; ok {
+		return
+	}
+	if len(t.order) >= maxVoteTimings {
+		if old := t.votes[t.order[0]]; old != nil && old.IncludedIn == nil {
+			t.pendingInclusion--
+		}
+		delete(t.votes, t.order[0])
+		t.order = t.order[1:]
+	}
+	t.votes[hash] = &VoteTiming{
+		Hash:         hash,
+		TargetNumber: vote.Data.TargetNumber,
+		TargetHash:   vote.Data.TargetHash,
+		Signed:       time.Now(),
+	}
+	t.order = append(t.order, hash)
+	t.pendingInclusion++
+}
+
+// local reports whether the vote was signed by the local validator.
+func (t *voteTimings) local(hash common.Hash) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	_, ok := t.votes[hash]
+	return ok
+}
+
+// relayed records the first broadcast of a local vote.
+func (t *voteTimings) relayed(hash common.Hash, peers int, targeted int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	timing := t.votes[hash]
+	if timing == nil || timing.FirstRelayed != 0 || peers == 0 {
+		return
+	}
+	timing.FirstRelayed = time.Since(timing.Signed)
+	timing.RelayPeers = peers
+	timing.Targeted = targeted
+	voteRelayTimer.Update(timing.FirstRelayed)
+}
+
+// seen records a local vote being sent back by a peer.
+func (t *voteTimings) seen(hash common.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	timing := t.votes[hash]
+	if timing == nil {
+		return
+	}
+	if timing.SeenBackCount == 0 {
+		timing.SeenBack = time.Since(timing.Signed)
+		voteSeenBackTimer.Update(timing.SeenBack)
+	}
+	timing.SeenBackCount++
+}
+
+// awaitingInclusion reports whether any tracked vote is not attested yet.
+func (t *voteTimings) awaitingInclusion() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.pendingInclusion > 0
+}
+
+// included records the local vote for the target block being attested in the
+// given block.
+func (t *voteTimings) included(target common.Hash, block common.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for i := len(t.order) - 1; i >= 0; i-- {
+		timing := t.votes[t.order[i]]
+		if timing.TargetHash != target {
+			continue
+		}
+		if timing.IncludedIn == nil {
+			timing.Included = time.Since(timing.Signed)
+			timing.IncludedIn = &block
+			t.pendingInclusion--
+			voteIncludedTimer.Update(timing.Included)
+		}
+		return
+	}
+}
+
+// list returns a copy of the tracked timings, most recent vote first.
+func (t *voteTimings) list() []*VoteTiming {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	list := make([]*VoteTiming, 0, len(t.votes))
+	for _, timing := range t.votes {
+		cpy := *timing
+		list = append(list, &cpy)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Signed.After(list[j].Signed)
+	})
+	return list
+}
@@ -0,0 +1,75 @@
+package vote
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newTimingVote(number uint64) *types.VoteEnvelope {
+	return &types.VoteEnvelope{
+		Data: &types.VoteData{
+			TargetNumber: number,
+			TargetHash:   common.BigToHash(new(big.Int).SetUint64(number)),
+		},
+	}
+}
+
+func TestVoteTimings(t *testing.T) {
+	timings := newVoteTimings()
+
+	vote := newTimingVote(1)
+	other := newTimingVote(2)
+	timings.signed(vote)
+
+	if !timings.local(vote.Hash()) || timings.local(other.Hash()) {
+		t.Fatal("local vote mismatch")
+	}
+	// Only the local votes are tracked, and only the first relay counts
+	timings.relayed(other.Hash(), 5, 0)
+	timings.relayed(vote.Hash(), 0, 0)
+	timings.relayed(vote.Hash(), 10, 2)
+	timings.relayed(vote.Hash(), 20, 0)
+	timings.seen(vote.Hash())
+	timings.seen(vote.Hash())
+
+	if !timings.awaitingInclusion() {
+		t.Fatal("vote not awaiting inclusion")
+	}
+	block := common.HexToHash("0x01")
+	timings.included(vote.Data.TargetHash, block)
+	timings.included(vote.Data.TargetHash, common.HexToHash("0x02"))
+	if timings.awaitingInclusion() {
+		t.Fatal("vote still awaiting inclusion")
+	}
+	list := timings.list()
+	if len(list) != 1 {
+		t.Fatalf("timing count mismatch: have %d, want 1", len(list))
+	}
+	timing := list[0]
+	if timing.RelayPeers != 10 || timing.Targeted != 2 || timing.SeenBackCount != 2 {
+		t.Errorf("timing mismatch: %+v", timing)
+	}
+	if timing.IncludedIn == nil || *timing.IncludedIn != block {
+		t.Errorf("inclusion mismatch: %+v", timing.IncludedIn)
+	}
+}
+
+func TestVoteTimingsEviction(t *testing.T) {
+	timings := newVoteTimings()
+	for i := uint64(1); i <= maxVoteTimings+10; i++ {
+		timings.signed(newTimingVote(i))
+	}
+	list := timings.list()
+	if len(list) != maxVoteTimings {
+		t.Fatalf("timing count mismatch: have %d, want %d", len(list), maxVoteTimings)
+	}
+	if timings.local(newTimingVote(10).Hash()) || !timings.local(newTimingVote(11).Hash()) {
+		t.Fatal("wrong votes evicted")
+	}
+	if timings.pendingInclusion != maxVoteTimings {
+		t.Fatalf("pending inclusion mismatch: have %d, want %d", timings.pendingInclusion, maxVoteTimings)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	cmath "github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/consensus/parlia"
@@ -43,6 +44,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -54,6 +56,8 @@ type EthAPIBackend struct {
 	allowUnprotectedTxs bool
 	eth                 *Ethereum
 	gpo                 *gasprice.Oracle
+
+	responseCache *ethapi.ResponseCache // Cache of RPC responses anchored to finalized blocks, nil if disabled
 }
 
 // ChainConfig returns the active chain configuration.
@@ -68,6 +72,13 @@ func (b *EthAPIBackend) CurrentBlock() *types.Header {
 func (b *EthAPIBackend) SetHead(number uint64) {
 	b.eth.handler.downloader.Cancel()
 	b.eth.blockchain.SetHead(number)
+	b.responseCache.Purge()
+}
+
+// ResponseCache returns the cache of the RPC responses anchored to finalized
+// blocks, nil if disabled.
+func (b *EthAPIBackend) ResponseCache() *ethapi.ResponseCache {
+	return b.responseCache
 }
 
 func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
@@ -97,6 +108,9 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 		}
 		return block, nil
 	}
+	if number.IsFastFinalityTag() {
+		return b.fastFinalityHeader(ctx, number)
+	}
 	var bn uint64
 	if number == rpc.EarliestBlockNumber {
 		bn = b.HistoryPruningCutoff()
@@ -106,6 +120,41 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 	return b.eth.blockchain.GetHeaderByNumber(bn), nil
 }
 
+// fastFinalityHeader resolves the "voted" and "finalized:<threshold>" block tags
+// via Parlia fast finality.
+func (b *EthAPIBackend) fastFinalityHeader(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if n, ok := number.FinalizedByValidators(); ok {
+		finalized, err := ethapi.FinalizedNumberByValidators(ctx, b, n)
+		if err != nil {
+			return nil, err
+		}
+		header := b.eth.blockchain.GetHeaderByNumber(uint64(finalized))
+		if header == nil {
+			return nil, errors.New("finalized block not found")
+		}
+		return header, nil
+	}
+	// The voted block is the highest canonical block a quorum of the current
+	// validators voted for, falling back to the justified one if the votes
+	// were already aggregated or the vote pool is unavailable.
+	safe := b.eth.blockchain.CurrentSafeBlock()
+	if safe == nil {
+		return nil, errors.New("voted block not found")
+	}
+	if b.eth.votePool == nil {
+		return safe, nil
+	}
+	validators, err := b.CurrentValidators()
+	if err != nil {
+		return nil, err
+	}
+	voted, hash, ok := b.eth.votePool.HighestVotedBlock(cmath.CeilDiv(len(validators)*2, 3))
+	if !ok || voted <= safe.Number.Uint64() || b.eth.blockchain.GetCanonicalHash(voted) != hash {
+		return safe, nil
+	}
+	return b.eth.blockchain.GetHeaderByHash(hash), nil
+}
+
 func (b *EthAPIBackend) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
 	if blockNr, ok := blockNrOrHash.Number(); ok {
 		return b.HeaderByNumber(ctx, blockNr)
@@ -155,6 +204,13 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 		}
 		return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
 	}
+	if number.IsFastFinalityTag() {
+		header, err := b.fastFinalityHeader(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
+	}
 	bn := uint64(number) // the resolved number
 	if number == rpc.EarliestBlockNumber {
 		bn = b.HistoryPruningCutoff()
@@ -339,6 +395,7 @@ func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 }
 
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	b.eth.txLifecycle.Seen([]common.Hash{signedTx.Hash()}, "", txpool.TxOriginLocal)
 	err := b.eth.txPool.Add([]*types.Transaction{signedTx}, false)[0]
 
 	// If the local transaction tracker is not configured, returns whatever
@@ -414,6 +471,31 @@ func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transac
 	return b.eth.txPool.ContentFrom(addr)
 }
 
+func (b *EthAPIBackend) TxLifecycle(txHash common.Hash) *txpool.TxLifecycle {
+	return b.eth.txLifecycle.Get(txHash)
+}
+
+func (b *EthAPIBackend) TxPoolScores() map[common.Address]ethapi.SenderScore {
+	scores := b.eth.legacyPool.SenderScores()
+	if scores == nil {
+		return nil
+	}
+	res := make(map[common.Address]ethapi.SenderScore, len(scores))
+	for addr, s := range scores {
+		res[addr] = ethapi.SenderScore{
+			Score:     s.Score,
+			Admitted:  s.Admitted,
+			Included:  s.Included,
+			Dropped:   s.Dropped,
+			Replaced:  s.Replaced,
+			Gapped:    s.Gapped,
+			Allowance: s.Allowance,
+			Used:      s.Used,
+		}
+	}
+	return res
+}
+
 func (b *EthAPIBackend) TxPool() *txpool.TxPool {
 	return b.eth.txPool
 }
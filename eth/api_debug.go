@@ -24,7 +24,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/stateless"
@@ -74,6 +73,13 @@ func (api *DebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error) {
 	case rpc.SafeBlockNumber:
 		header = api.eth.blockchain.CurrentSafeBlock()
 	default:
+		if blockNr.IsFastFinalityTag() {
+			var err error
+			if header, err = api.eth.APIBackend.fastFinalityHeader(context.Background(), blockNr); err != nil {
+				return state.Dump{}, err
+			}
+			break
+		}
 		block := api.eth.blockchain.GetBlockByNumber(uint64(blockNr))
 		if block == nil {
 			return state.Dump{}, fmt.Errorf("block #%d not found", blockNr)
@@ -160,6 +166,12 @@ func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hex
 			case rpc.SafeBlockNumber:
 				header = api.eth.blockchain.CurrentSafeBlock()
 			default:
+				if number.IsFastFinalityTag() {
+					if header, err = api.eth.APIBackend.fastFinalityHeader(context.Background(), number); err != nil {
+						return state.Dump{}, err
+					}
+					break
+				}
 				block := api.eth.blockchain.GetBlockByNumber(uint64(number))
 				if block == nil {
 					return state.Dump{}, fmt.Errorf("block #%d not found", number)
@@ -508,15 +520,9 @@ func (api *DebugAPI) ExecutionWitness(bn rpc.BlockNumberOrHash) (*stateless.ExtW
 	if parent == nil {
 		return &stateless.ExtWitness{}, fmt.Errorf("block %v found, but parent missing", bn)
 	}
-	config := core.ExecuteConfig{
-		WriteState:   false,
-		EnableTracer: false,
-		MakeWitness:  true,
-	}
-	result, err := bc.ProcessBlock(context.Background(), parent.Root, block, config)
+	witness, err := bc.BuildWitness(block)
 	if err != nil {
 		return nil, err
 	}
-
-	return result.Witness().ToExtWitness(), nil
+	return witness.ToExtWitness(), nil
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -37,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
@@ -335,3 +337,58 @@ func TestGetModifiedAccounts(t *testing.T) {
 		}
 	})
 }
+
+// Tests that the fast finality block tags are resolved when dumping state, and
+// that resolution failures are surfaced instead of being reported as a missing
+// block.
+func TestDumpFastFinalityTags(t *testing.T) {
+	t.Parallel()
+
+	// Create a Parlia chain with a genesis validator set of three
+	config := *params.TestChainConfig
+	config.Parlia = &params.ParliaConfig{}
+
+	extra := make([]byte, 32+3*common.AddressLength+65)
+	for i := 0; i < 3; i++ {
+		extra[32+i*common.AddressLength] = byte(i + 1)
+	}
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		gspec = &core.Genesis{
+			Config:    &config,
+			ExtraData: extra,
+			Alloc:     types.GenesisAlloc{common.HexToAddress("0xaa"): {Balance: big.NewInt(params.Ether)}},
+		}
+		genesis = gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+		engine  = parlia.New(&config, db, nil, genesis.Hash())
+	)
+	chain, err := core.NewBlockChain(db, gspec, engine, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	eth := &Ethereum{blockchain: chain, engine: engine}
+	eth.APIBackend = &EthAPIBackend{eth: eth}
+	api := NewDebugAPI(eth)
+
+	for _, number := range []rpc.BlockNumber{rpc.VotedBlockNumber, rpc.FinalizedByValidatorsBlockNumber(-2), rpc.FinalizedByValidatorsBlockNumber(3)} {
+		dump, err := api.DumpBlock(number)
+		if err != nil {
+			t.Fatalf("%d: failed to dump block: %v", number, err)
+		}
+		if dump.Root != fmt.Sprintf("%x", genesis.Root()) {
+			t.Errorf("%d: state root mismatch: have %s, want %x", number, dump.Root, genesis.Root())
+		}
+		if _, err := api.AccountRange(rpc.BlockNumberOrHashWithNumber(number), nil, 1, true, true, true); err != nil {
+			t.Errorf("%d: failed to iterate accounts: %v", number, err)
+		}
+	}
+	// More confirmations than validators can never be satisfied
+	if _, err := api.DumpBlock(rpc.FinalizedByValidatorsBlockNumber(4)); err == nil || strings.Contains(err.Error(), "not found") {
+		t.Errorf("resolution error not surfaced: %v", err)
+	}
+	if _, err := api.AccountRange(rpc.BlockNumberOrHashWithNumber(rpc.FinalizedByValidatorsBlockNumber(4)), nil, 1, true, true, true); err == nil || strings.Contains(err.Error(), "not found") {
+		t.Errorf("resolution error not surfaced: %v", err)
+	}
+}
@@ -17,11 +17,13 @@
 package eth
 
 import (
+	"errors"
 	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vote"
 )
 
 // MinerAPI provides an API to control the miner.
@@ -106,3 +108,13 @@ func (api *MinerAPI) AddBuilder(builder common.Address, url string) error {
 func (api *MinerAPI) RemoveBuilder(builder common.Address) error {
 	return api.e.APIBackend.RemoveBuilder(builder)
 }
+
+// VoteTimings returns the propagation timings of the recent votes signed by the
+// local validator, most recent first: the delays from signing until the vote
+// was first relayed, seen back from a peer and included in an attestation.
+func (api *MinerAPI) VoteTimings() ([]*vote.VoteTiming, error) {
+	if api.e.VotePool() == nil {
+		return nil, errors.New("vote pool not available")
+	}
+	return api.e.VotePool().VoteTimings(), nil
+}
@@ -26,6 +26,7 @@ import (
 	"math/big"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sync"
 	"time"
 
@@ -40,8 +41,10 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/monitor"
+	"github.com/ethereum/go-ethereum/core/opcodeCompiler/compiler"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
@@ -57,6 +60,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/live"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -121,8 +125,10 @@ type Ethereum struct {
 	// core protocol objects
 	config         *ethconfig.Config
 	txPool         *txpool.TxPool
+	legacyPool     *legacypool.LegacyPool
 	blobTxPool     *blobpool.BlobPool
 	localTxTracker *locals.TxTracker
+	txLifecycle    *txpool.LifecycleTracker
 	blockchain     *core.BlockChain
 
 	handler *handler
@@ -138,6 +144,7 @@ type Ethereum struct {
 
 	filterMaps      *filtermaps.FilterMaps
 	closeFilterMaps chan chan struct{}
+	filterIndex     *tracers.FilterIndex // Trace filter index maintained by the traceFilter live tracer
 
 	APIBackend *EthAPIBackend
 
@@ -184,6 +191,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	if config.EnableOpcodeOptimizing && config.OpcodeCachePersist {
+		opcodeDb, err := stack.OpenDatabase("opcodecache", 16, 16, "eth/db/opcodecache/", false)
+		if err != nil {
+			return nil, err
+		}
+		compiler.EnablePersistentCache(opcodeDb, config.OpcodeCacheWarm)
+	}
 	noTries := config.TriesVerifyMode != core.LocalVerify
 	if noTries && config.StateScheme != rawdb.HashScheme {
 		config.StateScheme = rawdb.HashScheme
@@ -306,10 +320,14 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		stopCh:          make(chan struct{}),
 	}
 
-	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), stack.Config().AllowUnprotectedTxs, eth, nil}
+	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), stack.Config().AllowUnprotectedTxs, eth, nil, nil}
 	if eth.APIBackend.allowUnprotectedTxs {
 		log.Info("Unprotected transactions allowed")
 	}
+	if config.RPCResponseCache > 0 {
+		eth.APIBackend.responseCache = ethapi.NewResponseCache(eth.APIBackend, config.RPCResponseCache*1024*1024)
+		log.Info("Enabled RPC response cache", "size", common.StorageSize(config.RPCResponseCache*1024*1024))
+	}
 	ethAPI := ethapi.NewBlockChainAPI(eth.APIBackend)
 	eth.engine, err = ethconfig.CreateConsensusEngine(chainConfig, chainDb, ethAPI, genesisHash)
 	if err != nil {
@@ -361,6 +379,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			VmConfig: vm.Config{
 				EnablePreimageRecording:   config.EnablePreimageRecording,
 				EnableOpcodeOptimizations: config.EnableOpcodeOptimizing,
+				CheckOpcodeOptimizations:  config.CheckOpcodeOptimizing,
 			},
 			// Enables file journaling for the trie database. The journal files will be stored
 			// within the data directory. The corresponding paths will be either:
@@ -371,8 +390,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 
 			StatelessSelfValidation: config.StatelessSelfValidation,
 			EnableWitnessStats:      config.EnableWitnessStats,
+			StatelessVerify:         config.StatelessVerify,
 		}
 	)
+	if config.StatelessVerify {
+		// The stateless mode keeps no state to snapshot
+		options.SnapshotLimit = 0
+	}
 	if config.DisableTxIndexer {
 		log.Warn("The TxIndexer is disabled. Please note that the next time you re-enable it, it may affect the node performance because of rebuilding the tx index.")
 		options.TxLookupLimit = -1
@@ -383,7 +407,17 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		if config.VMTraceJsonConfig != "" {
 			traceConfig = json.RawMessage(config.VMTraceJsonConfig)
 		}
-		t, err := tracers.LiveDirectory.New(config.VMTrace, traceConfig)
+		var t *tracing.Hooks
+		if config.VMTrace == live.TraceFilterTracer {
+			// Keep the trace filter index in the data directory, pruned along
+			// with the transaction indices unless configured otherwise.
+			if traceConfig, err = live.TraceFilterConfig(traceConfig, stack.ResolvePath("tracefilter"), config.TransactionHistory); err != nil {
+				return nil, fmt.Errorf("failed to create tracer %s: %v", config.VMTrace, err)
+			}
+			t, eth.filterIndex, err = live.NewTraceFilterTracer(traceConfig)
+		} else {
+			t, err = tracers.LiveDirectory.New(config.VMTrace, traceConfig)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to create tracer %s: %v", config.VMTrace, err)
 		}
@@ -428,14 +462,19 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
-	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
+	eth.legacyPool = legacypool.New(config.TxPool, eth.blockchain)
+	if config.TxPool.LifecycleRetention > 0 {
+		eth.txLifecycle = txpool.NewLifecycleTracker(config.TxPool.LifecycleRetention, eth.blockchain)
+		eth.legacyPool.SetLifecycleTracker(eth.txLifecycle)
+		stack.RegisterLifecycle(eth.txLifecycle)
+	}
 
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
 	}
-	eth.blobTxPool = blobpool.New(config.BlobPool, eth.blockchain, legacyPool.HasPendingAuth)
+	eth.blobTxPool = blobpool.New(config.BlobPool, eth.blockchain, eth.legacyPool.HasPendingAuth)
 
-	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, eth.blobTxPool})
+	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{eth.legacyPool, eth.blobTxPool})
 	if err != nil {
 		return nil, err
 	}
@@ -457,6 +496,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		Database:                  chainDb,
 		Chain:                     eth.blockchain,
 		TxPool:                    eth.txPool,
+		TxLifecycle:               eth.txLifecycle,
 		Network:                   networkID,
 		Sync:                      config.SyncMode,
 		BloomCache:                uint64(cacheLimit),
@@ -466,14 +506,23 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		EnableEVNFeatures:         stack.Config().EnableEVNFeatures,
 		EVNNodeIdsWhitelist:       stack.Config().P2P.EVNNodeIdsWhitelist,
 		ProxyedValidatorAddresses: stack.Config().P2P.ProxyedValidatorAddresses,
-		ProxyedNodeIds:            stack.Config().P2P.ProxyedNodeIds,
+		ProxyedNodeIds:            proxyedNodeIds(&stack.Config().P2P),
 		DisablePeerTxBroadcast:    config.DisablePeerTxBroadcast,
 		PeerSet:                   newPeerSet(),
 		EnableQuickBlockFetching:  stack.Config().EnableQuickBlockFetching,
+		ServeWitnesses:            config.ServeWitnesses,
+		TargetedVoteRelay:         config.Miner.VoteTargetedRelay,
+		UploadBudget:              stack.Config().P2P.UploadBudget,
+		DownloadBudget:            stack.Config().P2P.DownloadBudget,
+		TrafficRates:              eth.p2pServer.TrafficRates,
+		SetPeerLimit:              eth.p2pServer.SetPeerLimit,
 	}); err != nil {
 		return nil, err
 	}
 
+	if config.Miner.VoteTargetedRelay && !stack.Config().EnableEVNFeatures {
+		log.Warn("Targeted vote relay needs the EVN features to resolve the proposer peers, set Node.EnableEVNFeatures")
+	}
 	eth.dropper = newDropper(eth.p2pServer.MaxDialedConns(), eth.p2pServer.MaxInboundConns())
 
 	eth.miner = miner.New(eth, &config.Miner, eth.EventMux(), eth.engine)
@@ -557,6 +606,19 @@ func makeExtraData(extra []byte) []byte {
 	return extra
 }
 
+// proxyedNodeIds returns the nodes which get the blocks and votes directly and
+// first: the configured proxyed nodes, plus the sentries of a validator running
+// behind sentries.
+func proxyedNodeIds(config *p2p.Config) []enode.ID {
+	ids := slices.Clone(config.ProxyedNodeIds)
+	if config.BehindSentry {
+		for _, n := range config.SentryNodes {
+			ids = append(ids, n.ID())
+		}
+	}
+	return ids
+}
+
 // APIs return the collection of RPC services the ethereum package offers.
 // NOTE, some of these services probably need to be moved to somewhere else.
 func (s *Ethereum) APIs() []rpc.API {
@@ -566,6 +628,13 @@ func (s *Ethereum) APIs() []rpc.API {
 	if p, ok := s.engine.(*parlia.Parlia); ok {
 		apis = append(apis, p.APIs(s.BlockChain())...)
 	}
+	// Serve trace_filter if the live tracer maintains its index
+	if s.filterIndex != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "trace",
+			Service:   tracers.NewFilterAPI(s.APIBackend, s.filterIndex),
+		})
+	}
 
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
@@ -1025,6 +1094,7 @@ func (s *Ethereum) Stop() error {
 	s.miner.Close()
 	s.blockchain.Stop()
 	s.engine.Close()
+	compiler.ClosePersistentCache()
 
 	// Clean shutdown marker as the last thing before closing db
 	s.shutdownTracker.Stop()
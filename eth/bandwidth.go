@@ -0,0 +1,222 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"math"
+	mrand "math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+const (
+	// bandwidthTuneInterval is the interval between two adjustments to the
+	// bandwidth budget.
+	bandwidthTuneInterval = 5 * time.Second
+
+	// Budget usage above which the propagation is cut back, and below which it
+	// is restored.
+	bandwidthHighUsage = 0.9
+	bandwidthLowUsage  = 0.6
+
+	// fanoutStep is the factor the transaction fan-out is cut back by on each
+	// adjustment, down to minFanout of the square root of the peers.
+	fanoutStep = 0.75
+	minFanout  = 0.25
+)
+
+var (
+	bandwidthFanoutGauge    = metrics.NewRegisteredGaugeFloat64("eth/bandwidth/fanout", nil)
+	bandwidthPeerLimitGauge = metrics.NewRegisteredGauge("eth/bandwidth/peerlimit", nil)
+	bandwidthDroppedMeter   = metrics.NewRegisteredMeter("eth/bandwidth/dropped", nil)
+)
+
+// bandwidthTuner adapts the propagation to the bandwidth budget of the node.
+// When the traffic nears the budget, the transaction fan-out is cut back first,
+// block propagation falls back from direct broadcast to the square root of the
+// peers, and once the fan-out is at its minimum the peer count is lowered. The
+// steps are undone in reverse order when the traffic falls well below budget.
+type bandwidthTuner struct {
+	upload, download uint64                           // Budgets in bytes per second, zero for none
+	rates            func() (ingress, egress float64) // Current traffic rates in bytes per second
+
+	lock      sync.RWMutex
+	fanout    float64 // Scale of the transaction fan-out, in [minFanout, 1]
+	maxPeers  int     // Configured peer limit
+	peerLimit int     // Peer limit under the budget, in [minPeers, maxPeers]
+}
+
+// newBandwidthTuner creates a tuner for the given budgets, or returns nil if
+// neither budget is set.
+func newBandwidthTuner(upload, download uint64, rates func() (float64, float64)) *bandwidthTuner {
+	if upload == 0 && download == 0 {
+		return nil
+	}
+	return &bandwidthTuner{
+		upload:   upload,
+		download: download,
+		rates:    rates,
+		fanout:   1,
+	}
+}
+
+// start sets the configured peer limit the tuner starts from.
+func (t *bandwidthTuner) start(maxPeers int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.maxPeers, t.peerLimit = maxPeers, maxPeers
+	bandwidthFanoutGauge.Update(t.fanout)
+	bandwidthPeerLimitGauge.Update(int64(t.peerLimit))
+}
+
+// minPeers is the floor of the peer limit under the budget.
+func (t *bandwidthTuner) minPeers() int {
+	return max(t.maxPeers/4, 1)
+}
+
+// usage returns the largest fraction of a budget in use.
+func (t *bandwidthTuner) usage() float64 {
+	ingress, egress := t.rates()
+
+	var usage float64
+	if t.upload > 0 {
+		usage = egress / float64(t.upload)
+	}
+	if t.download > 0 {
+		usage = max(usage, ingress/float64(t.download))
+	}
+	return usage
+}
+
+// tune adjusts the fan-out and the peer limit to the current budget usage.
+func (t *bandwidthTuner) tune() {
+	usage := t.usage()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	fanout, peerLimit := t.fanout, t.peerLimit
+	step := max(t.maxPeers/10, 1)
+	switch {
+	case usage > bandwidthHighUsage:
+		if t.fanout > minFanout {
+			t.fanout = max(t.fanout*fanoutStep, minFanout)
+		} else {
+			t.peerLimit = max(t.peerLimit-step, t.minPeers())
+		}
+	case usage < bandwidthLowUsage:
+		if t.peerLimit < t.maxPeers {
+			t.peerLimit = min(t.peerLimit+step, t.maxPeers)
+		} else {
+			t.fanout = min(t.fanout/fanoutStep, 1)
+		}
+	}
+	if t.fanout != fanout || t.peerLimit != peerLimit {
+		log.Debug("Adjusted propagation to bandwidth budget", "usage", usage, "fanout", t.fanout, "peers", t.peerLimit)
+		bandwidthFanoutGauge.Update(t.fanout)
+		bandwidthPeerLimitGauge.Update(int64(t.peerLimit))
+	}
+}
+
+// txFanout returns the scale of the transaction fan-out.
+func (t *bandwidthTuner) txFanout() float64 {
+	if t == nil {
+		return 1
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.fanout
+}
+
+// throttled reports whether the propagation is cut back.
+func (t *bandwidthTuner) throttled() bool {
+	return t.txFanout() < 1
+}
+
+// peers returns the peer limit under the budget.
+func (t *bandwidthTuner) peers(maxPeers int) int {
+	if t == nil {
+		return maxPeers
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return min(t.peerLimit, maxPeers)
+}
+
+// bandwidthLoop periodically adapts the propagation to the bandwidth budget,
+// limits the p2p server to the adapted peer limit and drops the peers above it.
+func (h *handler) bandwidthLoop() {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(bandwidthTuneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.bandwidth.tune()
+			if h.setPeerLimit != nil {
+				limit := h.peerLimit()
+				if limit >= h.maxPeers {
+					limit = 0
+				}
+				h.setPeerLimit(limit)
+			}
+			if h.peers.len() > h.peerLimit() {
+				h.dropBudgetPeer()
+			}
+		case <-h.quitSync:
+			return
+		}
+	}
+}
+
+// peerLimit returns the maximum number of untrusted peers.
+func (h *handler) peerLimit() int {
+	return h.bandwidth.peers(h.maxPeers)
+}
+
+// dropBudgetPeer drops a random peer to get back under the budget peer limit.
+// Trusted, static, proxyed and EVN peers are kept.
+func (h *handler) dropBudgetPeer() {
+	keep := func(p *ethPeer) bool {
+		return p.Peer.Peer.Trusted() || p.Peer.Peer.StaticDialed() || p.ProxyedPeerFlag.Load()
+	}
+	peers := slices.DeleteFunc(h.peers.allNonEVNPeers(), keep)
+	if len(peers) == 0 {
+		return
+	}
+	p := peers[mrand.Intn(len(peers))]
+	p.Log().Debug("Dropping peer over bandwidth budget", "peers", h.peers.len(), "limit", h.peerLimit())
+	p.Peer.Disconnect(p2p.DiscBandwidthBudget)
+	bandwidthDroppedMeter.Mark(1)
+}
+
+// txDirectPeers returns the number of peers a transaction is sent to directly,
+// out of the given number of peers.
+func txDirectPeers(peers int, fanout float64) int {
+	n := int(math.Ceil(math.Sqrt(float64(peers)) * fanout))
+	return min(max(n, 1), peers)
+}
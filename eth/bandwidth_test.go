@@ -0,0 +1,94 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import "testing"
+
+// Tests that the bandwidth tuner cuts back the fan-out before the peers when
+// over budget, and restores them in reverse order.
+func TestBandwidthTuner(t *testing.T) {
+	var ingress, egress float64
+	tuner := newBandwidthTuner(1000, 4000, func() (float64, float64) { return ingress, egress })
+	tuner.start(40)
+
+	// Within budget, nothing changes.
+	egress = 700
+	tuner.tune()
+	if tuner.throttled() || tuner.peers(40) != 40 {
+		t.Fatalf("throttled within budget: fanout %v, peers %d", tuner.txFanout(), tuner.peers(40))
+	}
+	// Over the download budget, the fan-out is cut back down to its minimum
+	// before the peers are.
+	ingress = 3900
+	for i := 0; i < 5; i++ {
+		tuner.tune()
+	}
+	if fanout := tuner.txFanout(); fanout != minFanout {
+		t.Fatalf("fanout mismatch: have %v, want %v", fanout, minFanout)
+	}
+	if peers := tuner.peers(40); peers != 40 {
+		t.Fatalf("peers cut back before fanout: %d", peers)
+	}
+	for i := 0; i < 10; i++ {
+		tuner.tune()
+	}
+	if peers := tuner.peers(40); peers != 10 {
+		t.Fatalf("peer limit mismatch: have %d, want 10", peers)
+	}
+	// Well below budget, the peers come back before the fan-out.
+	ingress, egress = 0, 0
+	tuner.tune()
+	if peers, fanout := tuner.peers(40), tuner.txFanout(); peers != 14 || fanout != minFanout {
+		t.Fatalf("restore mismatch: peers %d, fanout %v", peers, fanout)
+	}
+	for i := 0; i < 20; i++ {
+		tuner.tune()
+	}
+	if tuner.throttled() || tuner.peers(40) != 40 {
+		t.Fatalf("not restored: fanout %v, peers %d", tuner.txFanout(), tuner.peers(40))
+	}
+}
+
+func TestBandwidthTunerDisabled(t *testing.T) {
+	tuner := newBandwidthTuner(0, 0, nil)
+	if tuner != nil {
+		t.Fatal("tuner created without budget")
+	}
+	if tuner.throttled() || tuner.txFanout() != 1 || tuner.peers(50) != 50 {
+		t.Fatal("nil tuner restricts propagation")
+	}
+}
+
+func TestTxDirectPeers(t *testing.T) {
+	tests := []struct {
+		peers  int
+		fanout float64
+		want   int
+	}{
+		{0, 1, 0},
+		{1, 1, 1},
+		{50, 1, 8},
+		{50, 0.5, 4},
+		{50, 0.01, 1},
+		{100, 0.25, 3},
+	}
+	for _, test := range tests {
+		if have := txDirectPeers(test.peers, test.fanout); have != test.want {
+			t.Errorf("peers %d, fanout %v: have %d, want %d", test.peers, test.fanout, have, test.want)
+		}
+	}
+}
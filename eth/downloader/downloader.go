@@ -342,6 +342,15 @@ func (d *Downloader) UnregisterPeer(id string) error {
 	return nil
 }
 
+// PeerRoundTrip returns the estimated request round trip time of a registered
+// sync peer, or 0 if the peer is unknown.
+func (d *Downloader) PeerRoundTrip(id string) time.Duration {
+	if p := d.peers.Peer(id); p != nil {
+		return p.rates.RoundTrip()
+	}
+	return 0
+}
+
 // LegacySync tries to sync up our local blockchain with a remote peer, both
 // adding various sanity checks and wrapping it with various log entries.
 func (d *Downloader) LegacySync(id string, head common.Hash, name string, td *big.Int, ttd *big.Int, mode SyncMode) error {
@@ -80,6 +80,7 @@ var Defaults = Config{
 	RPCTxFeeCap:            1,                                         // 1 ether
 	BlobExtraReserve:       params.DefaultExtraReserveForBlobRequests, // Extra reserve threshold for blob, blob never expires when -1 is set, default 28800
 	EnableOpcodeOptimizing: false,
+	OpcodeCacheWarm:        4096,
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -195,6 +196,12 @@ type Config struct {
 	// Generate execution witnesses and self-check against them (testing purpose)
 	StatelessSelfValidation bool
 
+	// Serve execution witnesses of recent blocks to bsc/4 peers
+	ServeWitnesses bool
+
+	// Import blocks statelessly, verifying them against witnesses fetched from peers
+	StatelessVerify bool
+
 	// Enables tracking of state size
 	EnableStateSizeTracking bool
 
@@ -212,6 +219,14 @@ type Config struct {
 	// send-transaction variants. The unit is ether.
 	RPCTxFeeCap float64
 
+	// RPCResponseCache is the size in megabytes of the cache of the RPC responses
+	// anchored to finalized blocks (0 = disabled).
+	RPCResponseCache int
+
+	// TraceExport enables the background trace export jobs, served on the
+	// authenticated debug API and written into the data directory.
+	TraceExport bool
+
 	// OverridePassedForkTime
 	OverridePassedForkTime *uint64 `toml:",omitempty"`
 
@@ -251,6 +266,9 @@ type Config struct {
 
 	//opcode optimization setting
 	EnableOpcodeOptimizing bool
+	CheckOpcodeOptimizing  bool // Cross-check optimized execution against the plain interpreter
+	OpcodeCachePersist     bool // Persist optimized code across restarts
+	OpcodeCacheWarm        int  // Number of most called contracts to load from disk at startup
 	// incremental snapshot config
 	EnableIncrSnapshots       bool
 	IncrSnapshotPath          string
@@ -66,12 +66,16 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		EnablePreimageRecording   bool
 		EnableWitnessStats        bool
 		StatelessSelfValidation   bool
+		ServeWitnesses            bool
+		StatelessVerify           bool
 		EnableStateSizeTracking   bool
 		VMTrace                   string
 		VMTraceJsonConfig         string
 		RPCGasCap                 uint64
 		RPCEVMTimeout             time.Duration
 		RPCTxFeeCap               float64
+		RPCResponseCache          int
+		TraceExport               bool
 		OverridePassedForkTime    *uint64       `toml:",omitempty"`
 		OverrideLorentz           *uint64       `toml:",omitempty"`
 		OverrideMaxwell           *uint64       `toml:",omitempty"`
@@ -86,6 +90,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TxSyncMaxTimeout          time.Duration `toml:",omitempty"`
 		BlobExtraReserve          uint64
 		EnableOpcodeOptimizing    bool
+		CheckOpcodeOptimizing     bool
+		OpcodeCachePersist        bool
+		OpcodeCacheWarm           int
 		EnableIncrSnapshots       bool
 		IncrSnapshotPath          string
 		IncrSnapshotBlockInterval uint64
@@ -143,12 +150,16 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.EnableWitnessStats = c.EnableWitnessStats
 	enc.StatelessSelfValidation = c.StatelessSelfValidation
+	enc.ServeWitnesses = c.ServeWitnesses
+	enc.StatelessVerify = c.StatelessVerify
 	enc.EnableStateSizeTracking = c.EnableStateSizeTracking
 	enc.VMTrace = c.VMTrace
 	enc.VMTraceJsonConfig = c.VMTraceJsonConfig
 	enc.RPCGasCap = c.RPCGasCap
 	enc.RPCEVMTimeout = c.RPCEVMTimeout
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
+	enc.RPCResponseCache = c.RPCResponseCache
+	enc.TraceExport = c.TraceExport
 	enc.OverridePassedForkTime = c.OverridePassedForkTime
 	enc.OverrideLorentz = c.OverrideLorentz
 	enc.OverrideMaxwell = c.OverrideMaxwell
@@ -163,6 +174,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TxSyncMaxTimeout = c.TxSyncMaxTimeout
 	enc.BlobExtraReserve = c.BlobExtraReserve
 	enc.EnableOpcodeOptimizing = c.EnableOpcodeOptimizing
+	enc.CheckOpcodeOptimizing = c.CheckOpcodeOptimizing
+	enc.OpcodeCachePersist = c.OpcodeCachePersist
+	enc.OpcodeCacheWarm = c.OpcodeCacheWarm
 	enc.EnableIncrSnapshots = c.EnableIncrSnapshots
 	enc.IncrSnapshotPath = c.IncrSnapshotPath
 	enc.IncrSnapshotBlockInterval = c.IncrSnapshotBlockInterval
@@ -224,12 +238,16 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		EnablePreimageRecording   *bool
 		EnableWitnessStats        *bool
 		StatelessSelfValidation   *bool
+		ServeWitnesses            *bool
+		StatelessVerify           *bool
 		EnableStateSizeTracking   *bool
 		VMTrace                   *string
 		VMTraceJsonConfig         *string
 		RPCGasCap                 *uint64
 		RPCEVMTimeout             *time.Duration
 		RPCTxFeeCap               *float64
+		RPCResponseCache          *int
+		TraceExport               *bool
 		OverridePassedForkTime    *uint64        `toml:",omitempty"`
 		OverrideLorentz           *uint64        `toml:",omitempty"`
 		OverrideMaxwell           *uint64        `toml:",omitempty"`
@@ -244,6 +262,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TxSyncMaxTimeout          *time.Duration `toml:",omitempty"`
 		BlobExtraReserve          *uint64
 		EnableOpcodeOptimizing    *bool
+		CheckOpcodeOptimizing     *bool
+		OpcodeCachePersist        *bool
+		OpcodeCacheWarm           *int
 		EnableIncrSnapshots       *bool
 		IncrSnapshotPath          *string
 		IncrSnapshotBlockInterval *uint64
@@ -400,6 +421,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.StatelessSelfValidation != nil {
 		c.StatelessSelfValidation = *dec.StatelessSelfValidation
 	}
+	if dec.ServeWitnesses != nil {
+		c.ServeWitnesses = *dec.ServeWitnesses
+	}
+	if dec.StatelessVerify != nil {
+		c.StatelessVerify = *dec.StatelessVerify
+	}
 	if dec.EnableStateSizeTracking != nil {
 		c.EnableStateSizeTracking = *dec.EnableStateSizeTracking
 	}
@@ -418,6 +445,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCTxFeeCap != nil {
 		c.RPCTxFeeCap = *dec.RPCTxFeeCap
 	}
+	if dec.RPCResponseCache != nil {
+		c.RPCResponseCache = *dec.RPCResponseCache
+	}
+	if dec.TraceExport != nil {
+		c.TraceExport = *dec.TraceExport
+	}
 	if dec.OverridePassedForkTime != nil {
 		c.OverridePassedForkTime = dec.OverridePassedForkTime
 	}
@@ -460,6 +493,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.EnableOpcodeOptimizing != nil {
 		c.EnableOpcodeOptimizing = *dec.EnableOpcodeOptimizing
 	}
+	if dec.CheckOpcodeOptimizing != nil {
+		c.CheckOpcodeOptimizing = *dec.CheckOpcodeOptimizing
+	}
+	if dec.OpcodeCachePersist != nil {
+		c.OpcodeCachePersist = *dec.OpcodeCachePersist
+	}
+	if dec.OpcodeCacheWarm != nil {
+		c.OpcodeCacheWarm = *dec.OpcodeCacheWarm
+	}
 	if dec.EnableIncrSnapshots != nil {
 		c.EnableIncrSnapshots = *dec.EnableIncrSnapshots
 	}
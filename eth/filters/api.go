@@ -22,6 +22,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -79,6 +81,7 @@ type FilterAPI struct {
 	timeout       time.Duration
 	logQueryLimit int
 	rangeLimit    bool
+	cache         *ethapi.ResponseCache // Cache of the responses anchored to finalized blocks, optional
 }
 
 // NewFilterAPI returns a new FilterAPI instance.
@@ -90,6 +93,7 @@ func NewFilterAPI(system *FilterSystem, rangeLimit bool) *FilterAPI {
 		timeout:       system.cfg.Timeout,
 		rangeLimit:    rangeLimit,
 		logQueryLimit: system.cfg.LogQueryLimit,
+		cache:         ethapi.ResponseCacheOf(system.backend),
 	}
 	go api.timeoutLoop(system.cfg.Timeout)
 
@@ -634,11 +638,51 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 	}
 
 	// Run the filter and return all the logs
-	logs, err := filter.Logs(ctx)
-	if err != nil {
-		return nil, err
+	return ethapi.CachedResponse(ctx, api.cache, logsCacheKey(crit), func() ([]*types.Log, *types.Header, error) {
+		logs, err := filter.Logs(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Anchor the logs to the last block of the range, if explicitly given
+		var anchor *types.Header
+		if crit.BlockHash != nil {
+			anchor, _ = api.sys.backend.HeaderByHash(ctx, *crit.BlockHash)
+		} else if crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 {
+			anchor, _ = api.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(crit.ToBlock.Int64()))
+		}
+		return returnLogs(logs), anchor, nil
+	})
+}
+
+// logsCacheKey returns the cache key of a log query, with the addresses and
+// the alternatives of the topic positions sorted as their order is irrelevant.
+// Queries using block tags are not cacheable, so an empty key is returned.
+func logsCacheKey(crit FilterCriteria) string {
+	var key strings.Builder
+	key.WriteString("eth_getLogs/")
+	switch {
+	case crit.BlockHash != nil:
+		fmt.Fprintf(&key, "%x", *crit.BlockHash)
+	case crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 && crit.ToBlock != nil && crit.ToBlock.Sign() >= 0:
+		fmt.Fprintf(&key, "%d-%d", crit.FromBlock, crit.ToBlock)
+	default:
+		return ""
+	}
+	addresses := slices.Clone(crit.Addresses)
+	slices.SortFunc(addresses, common.Address.Cmp)
+	key.WriteString("/")
+	for _, addr := range addresses {
+		fmt.Fprintf(&key, "%x,", addr)
+	}
+	for _, topics := range crit.Topics {
+		topics = slices.Clone(topics)
+		slices.SortFunc(topics, common.Hash.Cmp)
+		key.WriteString("/")
+		for _, topic := range topics {
+			fmt.Fprintf(&key, "%x,", topic)
+		}
 	}
-	return returnLogs(logs), err
+	return key.String()
 }
 
 // UninstallFilter removes the filter with the given filter id.
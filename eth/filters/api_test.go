@@ -19,6 +19,7 @@ package filters
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -183,3 +184,38 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
 }
+
+// Tests that the cache keys of log queries are independent of the ordering of
+// the addresses and topics, and disabled for the queries with block tags.
+func TestLogsCacheKey(t *testing.T) {
+	var (
+		from, to   = big.NewInt(100), big.NewInt(200)
+		addr1      = common.HexToAddress("0x1")
+		addr2      = common.HexToAddress("0x2")
+		topic1     = common.HexToHash("0x1")
+		topic2     = common.HexToHash("0x2")
+		latest     = big.NewInt(int64(rpc.LatestBlockNumber))
+		blockHash  = common.HexToHash("0xabcd")
+		reordered1 = FilterCriteria{FromBlock: from, ToBlock: to, Addresses: []common.Address{addr1, addr2}, Topics: [][]common.Hash{{topic1, topic2}}}
+		reordered2 = FilterCriteria{FromBlock: from, ToBlock: to, Addresses: []common.Address{addr2, addr1}, Topics: [][]common.Hash{{topic2, topic1}}}
+	)
+	if key1, key2 := logsCacheKey(reordered1), logsCacheKey(reordered2); key1 == "" || key1 != key2 {
+		t.Errorf("reordered criteria key mismatch: %q != %q", key1, key2)
+	}
+	if key := logsCacheKey(FilterCriteria{BlockHash: &blockHash}); key == "" {
+		t.Error("block hash query not cacheable")
+	}
+	moved := FilterCriteria{FromBlock: from, ToBlock: to, Topics: [][]common.Hash{{}, {topic1}}}
+	if logsCacheKey(moved) == logsCacheKey(FilterCriteria{FromBlock: from, ToBlock: to, Topics: [][]common.Hash{{topic1}}}) {
+		t.Error("topic positions not distinguished")
+	}
+	for _, crit := range []FilterCriteria{
+		{FromBlock: from, ToBlock: latest},
+		{FromBlock: from},
+		{},
+	} {
+		if key := logsCacheKey(crit); key != "" {
+			t.Errorf("tagged query cacheable: %q", key)
+		}
+	}
+}
@@ -132,6 +132,13 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 			}
 			return hdr.Number.Uint64(), nil
 		default:
+			if tag := rpc.BlockNumber(number); tag.IsFastFinalityTag() {
+				hdr, _ := f.sys.backend.HeaderByNumber(ctx, tag)
+				if hdr == nil {
+					return 0, fmt.Errorf("%s header not found", tag)
+				}
+				return hdr.Number.Uint64(), nil
+			}
 			if number < 0 {
 				return 0, errors.New("negative block number")
 			}
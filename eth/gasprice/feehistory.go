@@ -203,6 +203,10 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, reqEnd rpc.BlockNum
 			resolved, err = oracle.backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
 		case rpc.EarliestBlockNumber:
 			resolved, err = oracle.backend.HeaderByNumber(ctx, rpc.EarliestBlockNumber)
+		default:
+			if reqEnd.IsFastFinalityTag() {
+				resolved, err = oracle.backend.HeaderByNumber(ctx, reqEnd)
+			}
 		}
 		if resolved == nil || err != nil {
 			return nil, nil, 0, 0, err
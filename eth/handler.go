@@ -32,6 +32,7 @@ import (
 
 	"github.com/dchest/siphash"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core"
@@ -51,6 +52,7 @@ import (
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 const (
@@ -61,12 +63,27 @@ const (
 	// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
 	chainHeadChanSize = 128
 
+	// witnessCacheLimit is the number of recently built witnesses to cache.
+	witnessCacheLimit = 32
+
+	// witnessServeDepth is the number of recent blocks whose witnesses are
+	// built on request. Older ones are only served if still cached.
+	witnessServeDepth = 16
+
+	// witnessQueueSize is the number of witness builds allowed to be queued up,
+	// with further requests ignored until the builder catches up.
+	witnessQueueSize = 16
+
 	// voteChanSize is the size of channel listening to NewVotesEvent.
 	voteChanSize = 256
 
 	// deltaTdThreshold is the threshold of TD difference for peers to broadcast votes.
 	deltaTdThreshold = 1000
 
+	// voteRelayProposers is the number of upcoming in-turn proposers whose peers
+	// get the local votes first in targeted vote relay.
+	voteRelayProposers = 3
+
 	// txMaxBroadcastSize is the max size of a transaction that will be broadcasted.
 	// All transactions with a higher size will be announced and need to be fetched
 	// by the peer.
@@ -76,6 +93,7 @@ const (
 var (
 	syncChallengeTimeout        = 15 * time.Second // Time allowance for a node to reply to the sync progress challenge
 	accountBlacklistPeerCounter = metrics.NewRegisteredCounter("eth/count/blacklist", nil)
+	voteTargetedRelayMeter      = metrics.NewRegisteredMeter("eth/vote/relay/targeted", nil)
 )
 
 // txPool defines the methods needed from a transaction pool implementation to
@@ -123,6 +141,14 @@ type votePool interface {
 	// SubscribeNewVoteEvent should return an event subscription of
 	// NewVotesEvent and send events to the given channel.
 	SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Subscription
+
+	// IsLocalVote reports whether the vote was signed by the local validator.
+	IsLocalVote(hash common.Hash) bool
+
+	// MarkVoteRelayed and MarkVoteSeen record the propagation of the local
+	// votes, being sent to peers and being received back from peers.
+	MarkVoteRelayed(hash common.Hash, peers int, targeted int)
+	MarkVoteSeen(hash common.Hash)
 }
 
 // handlerConfig is the collection of initialization parameters to create a full
@@ -133,6 +159,7 @@ type handlerConfig struct {
 	Chain                     *core.BlockChain // Blockchain to serve data from
 	TxPool                    txPool           // Transaction pool to propagate from
 	VotePool                  votePool
+	TxLifecycle               *txpool.LifecycleTracker
 	Network                   uint64                 // Network identifier to adfvertise
 	Sync                      ethconfig.SyncMode     // Whether to snap or full sync
 	BloomCache                uint64                 // Megabytes to alloc for snap sync bloom
@@ -146,6 +173,18 @@ type handlerConfig struct {
 	EVNNodeIdsWhitelist       []enode.ID
 	ProxyedValidatorAddresses []common.Address
 	ProxyedNodeIds            []enode.ID
+	ServeWitnesses            bool   // Whether to serve execution witnesses to bsc/4 peers
+	TargetedVoteRelay         bool   // Whether to push local votes to the upcoming proposers first
+	UploadBudget              uint64 // Upload bandwidth budget in bytes per second, zero for none
+	DownloadBudget            uint64 // Download bandwidth budget in bytes per second, zero for none
+
+	// TrafficRates reports the current subprotocol traffic rates, required with
+	// a bandwidth budget.
+	TrafficRates func() (ingress, egress float64)
+
+	// SetPeerLimit passes the peer limit under the bandwidth budget on to the
+	// p2p server, so it doesn't dial peers beyond it, zero for none.
+	SetPeerLimit func(limit int)
 }
 
 type handler struct {
@@ -164,6 +203,7 @@ type handler struct {
 
 	database             ethdb.Database
 	txpool               txPool
+	txLifecycle          *txpool.LifecycleTracker
 	votepool             votePool
 	maliciousVoteMonitor *monitor.MaliciousVoteMonitor
 	chain                *core.BlockChain
@@ -191,6 +231,12 @@ type handler struct {
 
 	requiredBlocks map[uint64]common.Hash
 
+	serveWitnesses bool
+	witnessCache   *lru.Cache[common.Hash, rlp.RawValue] // Recently built witnesses, as peers tend to ask for the same ones
+	witnessCh      chan *types.Block                     // Blocks queued up for the witness builder
+	witnessQueued  map[common.Hash]struct{}              // Blocks queued up or being built, to avoid duplicate builds
+	witnessLock    sync.Mutex                            // Protects the queued witness set
+
 	// channels for fetcher, syncer, txsyncLoop
 	quitSync chan struct{}
 	stopCh   chan struct{}
@@ -200,6 +246,11 @@ type handler struct {
 
 	handlerStartCh chan struct{}
 	handlerDoneCh  chan struct{}
+
+	targetedVoteRelay bool // Whether to push local votes to the upcoming proposers first
+
+	bandwidth    *bandwidthTuner // Adapts the propagation to the bandwidth budget, nil without budget
+	setPeerLimit func(limit int) // Limits the peers of the p2p server under the budget, nil if not
 }
 
 // newHandler returns a handler for all Ethereum chain management protocol.
@@ -218,14 +269,22 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		eventMux:                   config.EventMux,
 		database:                   config.Database,
 		txpool:                     config.TxPool,
+		txLifecycle:                config.TxLifecycle,
 		votepool:                   config.VotePool,
 		chain:                      config.Chain,
 		peers:                      config.PeerSet,
 		txBroadcastKey:             newBroadcastChoiceKey(),
 		peersPerIP:                 make(map[string]int),
 		requiredBlocks:             config.RequiredBlocks,
+		serveWitnesses:             config.ServeWitnesses,
+		witnessCache:               lru.NewCache[common.Hash, rlp.RawValue](witnessCacheLimit),
+		witnessCh:                  make(chan *types.Block, witnessQueueSize),
+		witnessQueued:              make(map[common.Hash]struct{}),
 		directBroadcast:            config.DirectBroadcast,
 		enableEVNFeatures:          config.EnableEVNFeatures,
+		targetedVoteRelay:          config.TargetedVoteRelay,
+		bandwidth:                  newBandwidthTuner(config.UploadBudget, config.DownloadBudget, config.TrafficRates),
+		setPeerLimit:               config.SetPeerLimit,
 		evnNodeIdsWhitelistMap:     make(map[enode.ID]struct{}),
 		proxyedValidatorAddressMap: make(map[common.Address]struct{}),
 		proxyedNodeIdsMap:          make(map[enode.ID]struct{}),
@@ -243,6 +302,9 @@ func newHandler(config *handlerConfig) (*handler, error) {
 	for _, nodeID := range config.ProxyedNodeIds {
 		h.proxyedNodeIdsMap[nodeID] = struct{}{}
 	}
+	if h.chain.Stateless() {
+		h.chain.SetWitnessFetcher(h.fetchWitness)
+	}
 	if h.chain.NoTries() {
 	} else if config.Sync == ethconfig.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the snap
@@ -257,7 +319,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		if fullBlock.Number.Uint64() == 0 && snapBlock.Number.Uint64() > 0 {
 			h.snapSync.Store(true)
 			log.Warn("Switch sync mode from full sync to snap sync", "reason", "snap sync incomplete")
-		} else if !h.chain.HasState(fullBlock.Root) {
+		} else if !h.chain.Stateless() && !h.chain.HasState(fullBlock.Root) {
 			h.snapSync.Store(true)
 			log.Warn("Switch sync mode from full sync to snap sync", "reason", "head state missing")
 		}
@@ -502,7 +564,7 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 	// Ignore maxPeers if this is a trusted peer
 	peerInfo := peer.Peer.Info()
 	if !peerInfo.Network.Trusted {
-		if reject || h.peers.len() >= h.maxPeers {
+		if reject || h.peers.len() >= h.peerLimit() {
 			return p2p.DiscTooManyPeers
 		}
 	}
@@ -689,6 +751,10 @@ func (h *handler) unregisterPeer(id string) {
 	if peer.snapExt != nil {
 		h.downloader.SnapSyncer.Unregister(id)
 	}
+	// Keep the measured round trip in the peer's history before dropping it
+	if rtt := h.downloader.PeerRoundTrip(id); rtt > 0 {
+		peer.RecordRoundTrip(rtt)
+	}
 	h.downloader.UnregisterPeer(id)
 	h.txFetcher.Drop(id)
 
@@ -763,6 +829,19 @@ func (h *handler) Start(maxPeers int, maxPeersPerIP int) {
 	// start peer handler tracker
 	h.wg.Add(1)
 	go h.protoTracker()
+
+	// adapt the propagation to the bandwidth budget
+	if h.bandwidth != nil {
+		h.bandwidth.start(maxPeers)
+		h.wg.Add(1)
+		go h.bandwidthLoop()
+	}
+
+	// build the witnesses served to stateless peers
+	if h.serveWitnesses && !h.chain.Stateless() {
+		h.wg.Add(1)
+		go h.witnessBuildLoop()
+	}
 }
 
 func (h *handler) startMaliciousVoteMonitor() {
@@ -819,6 +898,11 @@ func (h *handler) BroadcastBlock(block *types.Block, propagate bool) {
 	}
 	hash := block.Hash()
 	peers := h.peers.peersWithoutBlock(hash)
+	if len(h.proxyedNodeIdsMap) > 0 {
+		// Serve the proxyed peers first, they are the validators behind a sentry
+		// or the sentries of a validator.
+		slices.SortStableFunc(peers, cmpProxyed)
+	}
 
 	// If propagation is requested, send to a subset of the peer
 	if propagate {
@@ -836,7 +920,7 @@ func (h *handler) BroadcastBlock(block *types.Block, propagate bool) {
 
 		// Step 1: Select target peers for initial broadcast.
 		limit := totalPeers
-		if !h.directBroadcast &&
+		if (!h.directBroadcast || h.bandwidth.throttled()) &&
 			!(h.networkID == 714 /*RialtoChainConfig.ChainID*/ && block.NumberU64() == 1) { // Populate TD from every receiver on startup to establish proper sync.
 			limit = int(math.Sqrt(float64(totalPeers)))
 		}
@@ -902,6 +986,18 @@ func (h *handler) BroadcastBlock(block *types.Block, propagate bool) {
 	}
 }
 
+// cmpProxyed orders proxyed peers before the others.
+func cmpProxyed(a, b *ethPeer) int {
+	switch pa, pb := a.ProxyedPeerFlag.Load(), b.ProxyedPeerFlag.Load(); {
+	case pa == pb:
+		return 0
+	case pa:
+		return -1
+	default:
+		return 1
+	}
+}
+
 // needFullBroadcastInEVN checks if the block should be broadcast to EVN peers
 // if the block is mined by self or received from proxyed validator, just broadcast to all EVN peers
 // if not, skip it.
@@ -951,19 +1047,38 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 	var (
 		blobTxs  int // Number of blob transactions to announce only
 		largeTxs int // Number of large transactions to announce only
+		condTxs  int // Number of conditional transactions to relay to trusted peers only
 
 		directCount int // Number of transactions sent directly to peers (duplicates included)
 		annCount    int // Number of transactions announced across all peers (duplicates included)
 
-		txset = make(map[*ethPeer][]common.Hash) // Set peer->hash to transfer directly
-		annos = make(map[*ethPeer][]common.Hash) // Set peer->hash to announce
+		txset = make(map[*ethPeer][]common.Hash)        // Set peer->hash to transfer directly
+		annos = make(map[*ethPeer][]common.Hash)        // Set peer->hash to announce
+		conds = make(map[*ethPeer][]*types.Transaction) // Set peer->conditional txs to relay
 
 		signer = types.LatestSigner(h.chain.Config())
 		choice = newBroadcastChoice(h.nodeID, h.txBroadcastKey)
 		peers  = h.peers.allNonEVNPeers()
 	)
+	choice.fanout = h.bandwidth.txFanout()
 
 	for _, tx := range txs {
+		// Conditional transactions are only meaningful alongside their conditions,
+		// relay them exclusively to trusted peers able to receive them intact.
+		if tx.Conditions() != nil {
+			condTxs++
+			for _, peer := range peers {
+				if peer.bscExt == nil || peer.bscExt.Version() < bsc.Bsc3 || !peer.Peer.Peer.Trusted() {
+					continue
+				}
+				if peer.KnownTransaction(tx.Hash()) {
+					continue
+				}
+				peer.MarkTransaction(tx.Hash())
+				conds[peer] = append(conds[peer], tx)
+			}
+			continue
+		}
 		var directSet map[*ethPeer]struct{}
 		switch {
 		case tx.Type() == types.BlobTxType:
@@ -999,8 +1114,11 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		annCount += len(hashes)
 		peer.AsyncSendPooledTransactionHashes(hashes)
 	}
-	log.Debug("Distributed transactions", "plaintxs", len(txs)-blobTxs-largeTxs, "blobtxs", blobTxs, "largetxs", largeTxs,
-		"bcastpeers", len(txset), "bcastcount", directCount, "annpeers", len(annos), "anncount", annCount)
+	for peer, txs := range conds {
+		peer.bscExt.AsyncSendConditionalTransactions(txs)
+	}
+	log.Debug("Distributed transactions", "plaintxs", len(txs)-blobTxs-largeTxs-condTxs, "blobtxs", blobTxs, "largetxs", largeTxs,
+		"condtxs", condTxs, "condpeers", len(conds), "bcastpeers", len(txset), "bcastcount", directCount, "annpeers", len(annos), "anncount", annCount)
 }
 
 // ReannounceTransactions will announce a batch of local pending transactions
@@ -1008,6 +1126,9 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 func (h *handler) ReannounceTransactions(txs types.Transactions) {
 	hashes := make([]common.Hash, 0, txs.Len())
 	for _, tx := range txs {
+		if tx.Conditions() != nil {
+			continue
+		}
 		hashes = append(hashes, tx.Hash())
 	}
 
@@ -1022,23 +1143,52 @@ func (h *handler) ReannounceTransactions(txs types.Transactions) {
 }
 
 // BroadcastVote will propagate a batch of votes to all peers
-// which are not known to already have the given vote.
+// which are not known to already have the given vote. With targeted relay,
+// the votes of the local validator go to the peers of the upcoming in-turn
+// proposers first.
 func (h *handler) BroadcastVote(vote *types.VoteEnvelope) {
 	var (
 		directCount int // Count of announcements made
 		directPeers int
+		targeted    int // Count of upcoming proposer peers sent to first
 
+		hash    = vote.Hash()
+		local   = h.votepool.IsLocalVote(hash)
+		sent    = make(map[*ethPeer]struct{})            // Set of peers already sent the vote
 		voteMap = make(map[*ethPeer]*types.VoteEnvelope) // Set peer->hash to transfer directly
 	)
 
-	// Broadcast vote to a batch of peers not knowing about it
-	peers := h.peers.peersWithoutVote(vote.Hash())
+	// Broadcast vote to a batch of peers not knowing about it, serving the
+	// proxyed peers first.
+	peers := h.peers.peersWithoutVote(hash)
+	for _, peer := range peers {
+		if peer.bscExt != nil && peer.ProxyedPeerFlag.Load() {
+			peer.bscExt.AsyncSendVotes([]*types.VoteEnvelope{vote})
+			sent[peer] = struct{}{}
+			directPeers++
+			directCount++
+		}
+	}
+	if local && h.targetedVoteRelay {
+		for _, peer := range h.proposerPeers(peers) {
+			if _, ok := sent[peer]; ok {
+				continue
+			}
+			peer.bscExt.AsyncSendVotes([]*types.VoteEnvelope{vote})
+			sent[peer] = struct{}{}
+			targeted++
+		}
+		voteTargetedRelayMeter.Mark(int64(targeted))
+	}
 	headBlock := h.chain.CurrentBlock()
 	currentTD := h.chain.GetTd(headBlock.Hash(), headBlock.Number.Uint64())
 	for _, peer := range peers {
 		if peer.bscExt == nil {
 			continue
 		}
+		if _, ok := sent[peer]; ok {
+			continue
+		}
 		if peer.ProxyedPeerFlag.Load() || peer.EVNPeerFlag.Load() {
 			voteMap[peer] = vote
 			continue
@@ -1056,7 +1206,25 @@ func (h *handler) BroadcastVote(vote *types.VoteEnvelope) {
 		votes := []*types.VoteEnvelope{_vote}
 		peer.bscExt.AsyncSendVotes(votes)
 	}
-	log.Debug("Vote broadcast", "vote packs", directPeers, "broadcast vote", directCount)
+	if local {
+		h.votepool.MarkVoteRelayed(hash, targeted+directPeers, targeted)
+	}
+	log.Debug("Vote broadcast", "vote packs", directPeers, "broadcast vote", directCount, "targeted", targeted)
+}
+
+// proposerPeers selects the peers run by the upcoming in-turn proposers, as
+// registered in the validator node IDs, out of the given peers.
+func (h *handler) proposerPeers(peers []*ethPeer) []*ethPeer {
+	p, ok := h.chain.Engine().(*parlia.Parlia)
+	if !ok {
+		return nil
+	}
+	proposers, err := p.NextInTurnValidators(h.chain, h.chain.CurrentHeader(), voteRelayProposers)
+	if err != nil {
+		log.Debug("Failed to retrieve upcoming proposers", "err", err)
+		return nil
+	}
+	return h.peers.validatorPeers(peers, proposers)
 }
 
 // minedBroadcastLoop sends mined blocks to connected peers.
@@ -1284,6 +1452,7 @@ func (st *blockRangeState) currentRange() eth.BlockRangeUpdatePacket {
 type broadcastChoice struct {
 	self   enode.ID
 	key    [16]byte
+	fanout float64 // Scale of the square root of the peers to send to
 	buffer map[*ethPeer]struct{}
 	tmp    []broadcastPeer
 }
@@ -1302,6 +1471,7 @@ func newBroadcastChoice(self enode.ID, key [16]byte) *broadcastChoice {
 	return &broadcastChoice{
 		self:   self,
 		key:    key,
+		fanout: 1,
 		buffer: make(map[*ethPeer]struct{}),
 	}
 }
@@ -1327,7 +1497,7 @@ func (bc *broadcastChoice) choosePeers(peers []*ethPeer, txSender common.Address
 
 	// Take top n.
 	clear(bc.buffer)
-	n := int(math.Ceil(math.Sqrt(float64(len(bc.tmp)))))
+	n := txDirectPeers(len(bc.tmp), bc.fanout)
 	for i := range n {
 		bc.buffer[bc.tmp[i].p] = struct{}{}
 	}
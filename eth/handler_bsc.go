@@ -1,14 +1,36 @@
 package eth
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
+const (
+	// witnessFetchAttempts is the number of times the peers are asked for a
+	// witness before giving up on it.
+	witnessFetchAttempts = 3
+
+	// witnessRetryInterval is the time given to the peers to build a witness
+	// before asking them again.
+	witnessRetryInterval = 500 * time.Millisecond
+)
+
+// errNoWitnessPeer is returned if none of the connected peers served the
+// witness of a block.
+var errNoWitnessPeer = errors.New("no peer served the witness")
+
 // bscHandler implements the bsc.Backend interface to handle the various network
 // packets that are sent as broadcasts.
 type bscHandler handler
@@ -33,6 +55,25 @@ func (h *bscHandler) PeerInfo(id enode.ID) interface{} {
 	return nil
 }
 
+// Witness retrieves the RLP encoded execution witness of a block if it was
+// already built. Witnesses of recent blocks missing from the cache are queued
+// up for the background builder, to be served on a later request. Nil is
+// returned if witnesses are not served or not available yet.
+func (h *bscHandler) Witness(hash common.Hash) rlp.RawValue {
+	if !h.serveWitnesses || h.chain.Stateless() {
+		return nil
+	}
+	if witness, ok := h.witnessCache.Get(hash); ok {
+		return witness
+	}
+	block := h.chain.GetBlockByHash(hash)
+	if block == nil || block.NumberU64()+witnessServeDepth <= h.chain.CurrentBlock().Number.Uint64() {
+		return nil
+	}
+	(*handler)(h).queueWitness(block)
+	return nil
+}
+
 // Handle is invoked from a peer's message handler when it receives a new remote
 // message that the handler couldn't consume and serve itself.
 func (h *bscHandler) Handle(peer *bsc.Peer, packet bsc.Packet) error {
@@ -42,6 +83,9 @@ func (h *bscHandler) Handle(peer *bsc.Peer, packet bsc.Packet) error {
 	case *bsc.VotesPacket:
 		return h.handleVotesBroadcast(peer, packet.Votes)
 
+	case *bsc.ConditionalTransactionsPacket:
+		return h.handleConditionalTransactions(peer, packet.Txs)
+
 	default:
 		return fmt.Errorf("unexpected bsc packet type: %T", packet)
 	}
@@ -53,9 +97,164 @@ func (h *bscHandler) Handle(peer *bsc.Peer, packet bsc.Packet) error {
 func (h *bscHandler) handleVotesBroadcast(peer *bsc.Peer, votes []*types.VoteEnvelope) error {
 	// Here we only put the first vote, to avoid ddos attack by sending a large batch of votes.
 	// This won't abandon any valid vote, because one vote is sent every time referring to func voteBroadcastLoop
-	if len(votes) > 0 {
+	if h.votepool != nil && len(votes) > 0 {
+		h.votepool.MarkVoteSeen(votes[0].Hash())
 		h.votepool.PutVote(votes[0])
 	}
 
 	return nil
 }
+
+// handleConditionalTransactions is invoked from a peer's message handler when it
+// relays a batch of conditional transactions. These are only accepted from
+// trusted peers, anything else is silently discarded.
+func (h *bscHandler) handleConditionalTransactions(peer *bsc.Peer, ctxs []*bsc.ConditionalTransaction) error {
+	if !peer.Peer.Trusted() {
+		peer.Log().Debug("Ignoring conditional transactions from untrusted peer", "count", len(ctxs))
+		return nil
+	}
+	if !h.synced.Load() {
+		return nil
+	}
+	var (
+		txs    = make([]*types.Transaction, 0, len(ctxs))
+		hashes = make([]common.Hash, 0, len(ctxs))
+	)
+	for _, ctx := range ctxs {
+		tx, err := ctx.Unwrap()
+		if err != nil {
+			return fmt.Errorf("invalid transaction conditions: %v", err)
+		}
+		if err := txpool.ValidateConditionsBasics(tx.Conditions()); err != nil {
+			peer.Log().Debug("Discarding conditional transaction", "hash", tx.Hash(), "err", err)
+			continue
+		}
+		txs = append(txs, tx)
+		hashes = append(hashes, tx.Hash())
+	}
+	if p := h.peers.peer(peer.ID()); p != nil {
+		for _, hash := range hashes {
+			p.MarkTransaction(hash)
+		}
+	}
+	h.txLifecycle.Seen(hashes, peer.ID(), txpool.TxOriginBroadcast)
+	h.txpool.Add(txs, false)
+	return nil
+}
+
+// queueWitness schedules building the witness of a block in the background,
+// unless it is already queued up or the builder is overloaded.
+func (h *handler) queueWitness(block *types.Block) {
+	h.witnessLock.Lock()
+	defer h.witnessLock.Unlock()
+
+	if _, ok := h.witnessQueued[block.Hash()]; ok {
+		return
+	}
+	select {
+	case h.witnessCh <- block:
+		h.witnessQueued[block.Hash()] = struct{}{}
+	default:
+		log.Debug("Witness builder overloaded", "number", block.NumberU64(), "hash", block.Hash())
+	}
+}
+
+// witnessBuildLoop builds the witnesses of the new chain heads and of the
+// recent blocks requested by peers, off the peer message handlers.
+func (h *handler) witnessBuildLoop() {
+	defer h.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	headSub := h.chain.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			// Stateless peers ask for the witnesses of the new blocks right away,
+			// have them ready unless still catching up with the network.
+			if !h.synced.Load() {
+				continue
+			}
+			if block := h.chain.GetBlock(ev.Header.Hash(), ev.Header.Number.Uint64()); block != nil {
+				h.queueWitness(block)
+			}
+		case block := <-h.witnessCh:
+			h.buildWitness(block)
+
+			h.witnessLock.Lock()
+			delete(h.witnessQueued, block.Hash())
+			h.witnessLock.Unlock()
+
+		case <-headSub.Err():
+			return
+		case <-h.stopCh:
+			return
+		}
+	}
+}
+
+// buildWitness builds and caches the witness of a block on top of the parent
+// state, if still available.
+func (h *handler) buildWitness(block *types.Block) {
+	hash := block.Hash()
+	if h.witnessCache.Contains(hash) || block.NumberU64() == 0 {
+		return
+	}
+	parent := h.chain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil || !h.chain.HasState(parent.Root) {
+		return
+	}
+	witness, err := h.chain.BuildWitness(block)
+	if err != nil {
+		log.Debug("Failed to build witness", "number", block.NumberU64(), "hash", hash, "err", err)
+		return
+	}
+	enc, err := rlp.EncodeToBytes(witness)
+	if err != nil {
+		return
+	}
+	h.witnessCache.Add(hash, enc)
+}
+
+// fetchWitness retrieves the execution witness of a block from the bsc/4 peers,
+// used by the stateless mode to import blocks. The peers are asked one by one
+// until one of them serves a decodable witness. As the peers build witnesses
+// in the background, they are asked again a few times before giving up.
+func (h *handler) fetchWitness(block *types.Block) (*stateless.Witness, error) {
+	for attempt := 0; attempt < witnessFetchAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(witnessRetryInterval):
+			case <-h.quitSync:
+				return nil, errNoWitnessPeer
+			}
+		}
+		if witness := h.fetchWitnessOnce(block); witness != nil {
+			return witness, nil
+		}
+	}
+	return nil, errNoWitnessPeer
+}
+
+// fetchWitnessOnce asks each bsc/4 peer for the witness of the block once.
+func (h *handler) fetchWitnessOnce(block *types.Block) *stateless.Witness {
+	hash := block.Hash()
+	for _, peer := range h.peers.witnessPeers() {
+		witnesses, err := peer.bscExt.RequestWitnesses([]common.Hash{hash})
+		if err != nil {
+			peer.Log().Debug("Failed to request witness", "number", block.NumberU64(), "hash", hash, "err", err)
+			continue
+		}
+		if len(witnesses) == 0 || bytes.Equal(witnesses[0], rlp.EmptyString) {
+			continue
+		}
+		witness := new(stateless.Witness)
+		if err := rlp.DecodeBytes(witnesses[0], witness); err != nil {
+			peer.Log().Debug("Invalid witness received", "number", block.NumberU64(), "hash", hash, "err", err)
+			continue
+		}
+		return witness
+	}
+	return nil
+}
@@ -2,17 +2,21 @@ package eth
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 type testBscHandler struct {
@@ -23,7 +27,8 @@ func (h *testBscHandler) Chain() *core.BlockChain { panic("no backing chain") }
 func (h *testBscHandler) RunPeer(peer *bsc.Peer, handler bsc.Handler) error {
 	panic("not used in tests")
 }
-func (h *testBscHandler) PeerInfo(enode.ID) interface{} { panic("not used in tests") }
+func (h *testBscHandler) PeerInfo(enode.ID) interface{}    { panic("not used in tests") }
+func (h *testBscHandler) Witness(common.Hash) rlp.RawValue { return nil }
 func (h *testBscHandler) Handle(peer *bsc.Peer, packet bsc.Packet) error {
 	switch packet := packet.(type) {
 	case *bsc.VotesPacket:
@@ -250,3 +255,256 @@ func testRecvVotes(t *testing.T, protocol uint) {
 		t.Errorf("no NewVotesEvent received within 2 seconds")
 	}
 }
+
+// Tests that execution witnesses are only served when enabled, that they are
+// built in the background for recent blocks only, and that the served witnesses
+// belong to the requested block.
+func TestServeWitnesses(t *testing.T) {
+	handler := newTestHandlerWithBlocks(witnessServeDepth + 4)
+	defer handler.close()
+
+	backend := (*bscHandler)(handler.handler)
+	block := handler.chain.GetBlockByNumber(witnessServeDepth + 3)
+
+	if enc := backend.Witness(block.Hash()); enc != nil {
+		t.Fatalf("witness served while disabled")
+	}
+	// Enable serving after the handler was started, so run the builder manually
+	handler.handler.serveWitnesses = true
+	handler.handler.wg.Add(1)
+	go handler.handler.witnessBuildLoop()
+
+	if enc := backend.Witness(block.Hash()); enc != nil {
+		t.Fatalf("witness served before being built")
+	}
+	var enc rlp.RawValue
+	for i := 0; i < 100 && enc == nil; i++ {
+		time.Sleep(20 * time.Millisecond)
+		enc = backend.Witness(block.Hash())
+	}
+	if enc == nil {
+		t.Fatalf("witness not served")
+	}
+	witness := new(stateless.Witness)
+	if err := rlp.DecodeBytes(enc, witness); err != nil {
+		t.Fatalf("failed to decode witness: %v", err)
+	}
+	if have, want := witness.Headers[0].Hash(), block.ParentHash(); have != want {
+		t.Errorf("witness parent mismatch: have %x, want %x", have, want)
+	}
+	if enc := backend.Witness(common.Hash{0x01}); enc != nil {
+		t.Errorf("witness served for unknown block")
+	}
+	// Witnesses of old blocks are not built on request
+	old := handler.chain.GetBlockByNumber(3)
+	backend.Witness(old.Hash())
+	time.Sleep(100 * time.Millisecond)
+	if enc := backend.Witness(old.Hash()); enc != nil {
+		t.Errorf("witness served for old block")
+	}
+}
+
+// Tests that vote broadcasts are ignored rather than crashing the node when
+// the vote pool is disabled.
+func TestHandleVotesWithoutVotePool(t *testing.T) {
+	h := (*bscHandler)(&handler{})
+	votes := []*types.VoteEnvelope{{Data: &types.VoteData{TargetNumber: 1}}}
+	if err := h.handleVotesBroadcast(nil, votes); err != nil {
+		t.Fatalf("failed to handle votes: %v", err)
+	}
+}
+
+// Tests that conditional transactions relayed by trusted peers reach the pool
+// with their conditions intact, including ones not yet includable, and that the
+// ones relayed by untrusted peers are discarded.
+func TestRecvConditionalTransactions(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler()
+	defer handler.close()
+	handler.handler.synced.Store(true)
+
+	protos := []p2p.Protocol{{Name: "bsc", Version: bsc.Bsc3}}
+	caps := []p2p.Cap{{Name: "bsc", Version: bsc.Bsc3}}
+
+	relay := func(id enode.ID, trusted bool, nonce uint64) *types.Transaction {
+		p2pSrc, p2pSink := p2p.MsgPipe()
+		defer p2pSrc.Close()
+		defer p2pSink.Close()
+
+		peer := p2p.NewPeerWithProtocols(id, protos, "", caps)
+		if trusted {
+			peer.UpdateTrustFlagTest()
+		}
+		local := bsc.NewPeer(bsc.Bsc3, peer, p2pSrc)
+		defer local.Close()
+
+		// Condition the transaction on a future block, the pool holds it back
+		// until then but the relay must accept it
+		minBlock := hexutil.Uint64(handler.chain.CurrentBlock().Number.Uint64() + 10)
+		tx := types.NewTransaction(nonce, common.Address{}, big.NewInt(0), 100000, big.NewInt(0), nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, testKey)
+		tx.SetConditions(&types.TransactionOpts{BlockNumberMin: &minBlock})
+
+		ctx, err := bsc.NewConditionalTransaction(tx)
+		if err != nil {
+			t.Fatalf("failed to wrap transaction: %v", err)
+		}
+		// Round-trip the packet through its wire encoding
+		enc, err := rlp.EncodeToBytes(&bsc.ConditionalTransactionsPacket{Txs: []*bsc.ConditionalTransaction{ctx}})
+		if err != nil {
+			t.Fatalf("failed to encode packet: %v", err)
+		}
+		packet := new(bsc.ConditionalTransactionsPacket)
+		if err := rlp.DecodeBytes(enc, packet); err != nil {
+			t.Fatalf("failed to decode packet: %v", err)
+		}
+		if err := (*bscHandler)(handler.handler).Handle(local, packet); err != nil {
+			t.Fatalf("failed to handle packet: %v", err)
+		}
+		return tx
+	}
+	tx := relay(enode.ID{1}, true, 0)
+	pooled := handler.txpool.Get(tx.Hash())
+	if pooled == nil {
+		t.Fatalf("transaction relayed by trusted peer not added to pool")
+	}
+	if opts := pooled.Conditions(); opts == nil || opts.BlockNumberMin == nil || *opts.BlockNumberMin != *tx.Conditions().BlockNumberMin {
+		t.Errorf("conditions lost in relay: have %v, want %v", opts, tx.Conditions())
+	}
+	if tx := relay(enode.ID{2}, false, 1); handler.txpool.Has(tx.Hash()) {
+		t.Errorf("transaction relayed by untrusted peer added to pool")
+	}
+}
+
+// Tests that only the first vote of a broadcast is processed, the rest of the
+// batch is neither pooled nor counted as seen.
+func TestHandleVotesFirstOnly(t *testing.T) {
+	pool := newTestVotePool()
+	h := (*bscHandler)(&handler{votepool: pool})
+
+	votes := []*types.VoteEnvelope{
+		{Data: &types.VoteData{TargetNumber: 1}},
+		{Data: &types.VoteData{TargetNumber: 2}},
+	}
+	if err := h.handleVotesBroadcast(nil, votes); err != nil {
+		t.Fatalf("failed to handle votes: %v", err)
+	}
+	if len(pool.seen) != 1 || pool.seen[0] != votes[0].Hash() {
+		t.Errorf("seen votes mismatch: have %v, want %v", pool.seen, votes[0].Hash())
+	}
+	if len(pool.pool) != 1 || pool.pool[votes[0].Hash()] == nil {
+		t.Errorf("pooled votes mismatch: have %d", len(pool.pool))
+	}
+}
+
+// connectTestHandlers connects two handlers with eth and bsc peers running over
+// in-memory pipes, as seen by each other under the given node IDs.
+func connectTestHandlers(t *testing.T, a *testHandler, aID enode.ID, b *testHandler, bID enode.ID) {
+	t.Helper()
+
+	protos := []p2p.Protocol{{Name: "eth", Version: eth.ETH68}, {Name: "bsc", Version: bsc.Bsc1}}
+	caps := []p2p.Cap{{Name: "eth", Version: eth.ETH68}, {Name: "bsc", Version: bsc.Bsc1}}
+
+	ethA, ethB := p2p.MsgPipe()
+	bscA, bscB := p2p.MsgPipe()
+	t.Cleanup(func() {
+		ethA.Close()
+		ethB.Close()
+		bscA.Close()
+		bscB.Close()
+	})
+	run := func(h *handler, remote enode.ID, ethRW, bscRW p2p.MsgReadWriter) {
+		peer := p2p.NewPeerWithProtocols(remote, protos, "", caps)
+		go h.runBscExtension(bsc.NewPeer(bsc.Bsc1, peer, bscRW), func(peer *bsc.Peer) error {
+			return bsc.Handle((*bscHandler)(h), peer)
+		})
+		go h.runEthPeer(eth.NewPeer(eth.ETH68, peer, ethRW, nil), func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(h), peer)
+		})
+	}
+	run(a.handler, bID, ethA, bscA)
+	run(b.handler, aID, ethB, bscB)
+
+	for start := time.Now(); a.handler.peers.peer(bID.String()) == nil || b.handler.peers.peer(aID.String()) == nil; time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("handlers %v and %v failed to connect", aID, bID)
+		}
+	}
+}
+
+// hasVote waits for a vote to reach the vote pool of a handler.
+func (h *testHandler) hasVote(vote *types.VoteEnvelope, wait time.Duration) bool {
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		h.votepool.lock.RLock()
+		_, ok := h.votepool.pool[vote.Hash()]
+		h.votepool.lock.RUnlock()
+		if ok || time.Since(start) > wait {
+			return ok
+		}
+	}
+}
+
+// Tests the relay of a sentry between the validator behind it and outsiders,
+// running the handlers over in-memory pipes. The validator is one block ahead,
+// too far for the votes to be relayed to an ordinary peer, and an outsider at
+// the same height checks that only the validator gets the priority relay.
+func TestSentryRelay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		validatorID = enode.ID{1}
+		sentryID    = enode.ID{2}
+		outsiderID  = enode.ID{3}
+		aheadID     = enode.ID{4}
+
+		validator = newTestHandlerWithProxyed(1, []enode.ID{sentryID})
+		sentry    = newTestHandlerWithProxyed(0, []enode.ID{validatorID})
+		outsider  = newTestHandler()
+		ahead     = newTestHandlerWithBlocks(1)
+	)
+	// Close the handlers after the pipes, closed by the connect cleanups
+	for _, h := range []*testHandler{validator, sentry, outsider, ahead} {
+		t.Cleanup(h.close)
+	}
+	connectTestHandlers(t, validator, validatorID, sentry, sentryID)
+	connectTestHandlers(t, sentry, sentryID, outsider, outsiderID)
+	connectTestHandlers(t, sentry, sentryID, ahead, aheadID)
+
+	// Flag the proxyed peers right away instead of waiting for the tracker
+	validator.handler.peers.setProxyedPeers(validator.handler.proxyedNodeIdsMap)
+	sentry.handler.peers.setProxyedPeers(sentry.handler.proxyedNodeIdsMap)
+
+	// The votes of the validator reach the outsiders through the sentry
+	vote := &types.VoteEnvelope{Data: &types.VoteData{TargetNumber: 1}}
+	validator.votepool.PutVote(vote)
+	if !outsider.hasVote(vote, 5*time.Second) {
+		t.Fatal("validator vote not relayed to outsider")
+	}
+	// The votes of the outsiders reach the validator despite its distance, but
+	// not an ordinary peer at the same distance
+	vote = &types.VoteEnvelope{Data: &types.VoteData{TargetNumber: 2}}
+	outsider.votepool.PutVote(vote)
+	if !validator.hasVote(vote, 5*time.Second) {
+		t.Fatal("outsider vote not relayed to validator")
+	}
+	if ahead.hasVote(vote, 200*time.Millisecond) {
+		t.Error("outsider vote relayed to distant ordinary peer")
+	}
+	// A propagated block goes to the validator first, filling the square root
+	// of the sentry peers on its own
+	block := validator.chain.GetBlockByNumber(1)
+	sentry.handler.BroadcastBlock(block, true)
+
+	for start := time.Now(); !validator.handler.peers.peer(sentryID.String()).KnownBlock(block.Hash()); time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("block not propagated to validator")
+		}
+	}
+	time.Sleep(200 * time.Millisecond)
+	for _, h := range []*testHandler{outsider, ahead} {
+		if h.handler.peers.peer(sentryID.String()).KnownBlock(block.Hash()) {
+			t.Error("block propagated to outsider before the validator filled the sqrt share")
+		}
+	}
+}
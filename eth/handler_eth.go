@@ -24,6 +24,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/log"
@@ -79,6 +80,9 @@ func (h *ethHandler) Handle(peer *eth.Peer, packet eth.Packet) error {
 		if err := handleTransactions(peer, txs, true); err != nil {
 			return fmt.Errorf("Transactions: %v", err)
 		}
+		if h.txLifecycle != nil {
+			h.txLifecycle.Seen(txHashes(txs), peer.ID(), txpool.TxOriginBroadcast)
+		}
 		return h.txFetcher.Enqueue(peer.ID(), txs, false)
 
 	case *eth.PooledTransactionsPacket:
@@ -89,6 +93,9 @@ func (h *ethHandler) Handle(peer *eth.Peer, packet eth.Packet) error {
 		if err := handleTransactions(peer, txs, false); err != nil {
 			return fmt.Errorf("PooledTransactions: %v", err)
 		}
+		if h.txLifecycle != nil {
+			h.txLifecycle.Seen(txHashes(txs), peer.ID(), txpool.TxOriginAnnouncement)
+		}
 		return h.txFetcher.Enqueue(peer.ID(), txs, true)
 
 	default:
@@ -130,6 +137,15 @@ func handleTransactions(peer *eth.Peer, list []*types.Transaction, directBroadca
 	return nil
 }
 
+// txHashes returns the hashes of the given transactions.
+func txHashes(txs []*types.Transaction) []common.Hash {
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	return hashes
+}
+
 // handleBlockAnnounces is invoked from a peer's message handler when it transmits a
 // batch of block announcements for the local node to process.
 func (h *ethHandler) handleBlockAnnounces(peer *eth.Peer, hashes []common.Hash, numbers []uint64) error {
@@ -178,6 +194,7 @@ func (h *ethHandler) handleBlockBroadcast(peer *eth.Peer, packet *eth.NewBlockPa
 	blockFirstReceived := false
 	if stats.RecvNewBlockTime.Load() == 0 {
 		blockFirstReceived = true
+		peer.RecordUsefulBlock()
 		stats.RecvNewBlockTime.Store(time.Now().UnixMilli())
 		addr := peer.RemoteAddr()
 		if addr != nil {
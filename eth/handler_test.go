@@ -209,6 +209,12 @@ func newTestHandler() *testHandler {
 // newTestHandlerWithBlocks creates a new handler for testing purposes, with a
 // given number of initial blocks.
 func newTestHandlerWithBlocks(blocks int) *testHandler {
+	return newTestHandlerWithProxyed(blocks, nil)
+}
+
+// newTestHandlerWithProxyed creates a new handler for testing purposes, with a
+// given number of initial blocks and the given proxyed nodes.
+func newTestHandlerWithProxyed(blocks int, proxyed []enode.ID) *testHandler {
 	// Create a database pre-initialize with a genesis block
 	db := rawdb.NewMemoryDatabase()
 	gspec := &core.Genesis{
@@ -225,13 +231,14 @@ func newTestHandlerWithBlocks(blocks int) *testHandler {
 	votepool := newTestVotePool()
 
 	handler, _ := newHandler(&handlerConfig{
-		Database:   db,
-		Chain:      chain,
-		TxPool:     txpool,
-		VotePool:   votepool,
-		Network:    1,
-		Sync:       ethconfig.SnapSync,
-		BloomCache: 1,
+		Database:       db,
+		Chain:          chain,
+		TxPool:         txpool,
+		VotePool:       votepool,
+		Network:        1,
+		Sync:           ethconfig.SnapSync,
+		BloomCache:     1,
+		ProxyedNodeIds: proxyed,
 	})
 	handler.Start(1000, 3)
 
@@ -361,6 +368,7 @@ func (b *testHandler) close() {
 // complex validation and consensus rules.
 type testVotePool struct {
 	pool map[common.Hash]*types.VoteEnvelope // Hash map of collected votes
+	seen []common.Hash                       // Votes marked as seen from peers
 
 	voteFeed event.Feed   // Notification feed to allow waiting for inclusion
 	lock     sync.RWMutex // Protects the vote pool
@@ -400,6 +408,16 @@ func (t *testVotePool) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.
 	return t.voteFeed.Subscribe(ch)
 }
 
+func (t *testVotePool) IsLocalVote(hash common.Hash) bool                         { return false }
+func (t *testVotePool) MarkVoteRelayed(hash common.Hash, peers int, targeted int) {}
+
+func (t *testVotePool) MarkVoteSeen(hash common.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.seen = append(t.seen, hash)
+}
+
 var (
 	emptyBlob          = kzg4844.Blob{}
 	emptyBlobCommit, _ = kzg4844.BlobToCommitment(&emptyBlob)
@@ -476,6 +476,45 @@ func (ps *peerSet) peersWithoutVote(hash common.Hash) []*ethPeer {
 	return list
 }
 
+// validatorPeers selects the peers run by the given validators out of a list
+// of peers, according to the validator node IDs registered on chain.
+func (ps *peerSet) validatorPeers(peers []*ethPeer, validators []common.Address) []*ethPeer {
+	ps.lock.RLock()
+	nodeIDs := make(map[enode.ID]struct{})
+	for _, validator := range validators {
+		for _, id := range ps.validatorNodeIDsMap[validator] {
+			nodeIDs[id] = struct{}{}
+		}
+	}
+	ps.lock.RUnlock()
+
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+	var list []*ethPeer
+	for _, p := range peers {
+		if _, ok := nodeIDs[p.NodeID()]; ok && p.bscExt != nil {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// witnessPeers retrieves a list of peers able to serve execution witnesses,
+// i.e. running at least the bsc/4 protocol.
+func (ps *peerSet) witnessPeers() []*ethPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*ethPeer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		if p.bscExt != nil && p.bscExt.Version() >= bsc.Bsc4 {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
 // len returns if the current number of `eth` peers in the set. Since the `snap`
 // peers are tied to the existence of an `eth` connection, that will always be a
 // subset of `eth`.
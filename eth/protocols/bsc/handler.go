@@ -26,6 +26,11 @@ const (
 	// the wire is maxMessageSize (10MB); 8MB leaves headroom for outer RLP/p2p
 	// framing. Each entry's measured size includes sidecars.
 	softResponseLimit = 8 * 1024 * 1024
+
+	// MaxRequestWitnessesCount is the maximum number of witnesses to serve per
+	// GetWitnesses request. Witnesses are expensive to build and large, so the
+	// practical limit is usually softResponseLimit.
+	MaxRequestWitnessesCount = 8
 )
 
 // Handler is a callback to invoke from an outside runner after the boilerplate
@@ -49,6 +54,10 @@ type Backend interface {
 	// the remote peer. Only packets not consumed by the protocol handler will
 	// be forwarded to the backend.
 	Handle(peer *Peer, packet Packet) error
+
+	// Witness retrieves the RLP encoded execution witness of the given block to
+	// serve to a remote peer, or nil if it's not available or not served.
+	Witness(hash common.Hash) rlp.RawValue
 }
 
 // MakeProtocols constructs the P2P protocol definitions for `bsc`.
@@ -107,6 +116,24 @@ var bsc2 = map[uint64]msgHandler{
 	BlocksByRangeMsg:    handleBlocksByRange,
 }
 
+var bsc3 = map[uint64]msgHandler{
+	BscCapMsg:                  handleBscCap, // ignore capability message for backward compatibility
+	VotesMsg:                   handleVotes,
+	GetBlocksByRangeMsg:        handleGetBlocksByRange,
+	BlocksByRangeMsg:           handleBlocksByRange,
+	ConditionalTransactionsMsg: handleConditionalTransactions,
+}
+
+var bsc4 = map[uint64]msgHandler{
+	BscCapMsg:                  handleBscCap, // ignore capability message for backward compatibility
+	VotesMsg:                   handleVotes,
+	GetBlocksByRangeMsg:        handleGetBlocksByRange,
+	BlocksByRangeMsg:           handleBlocksByRange,
+	ConditionalTransactionsMsg: handleConditionalTransactions,
+	GetWitnessesMsg:            handleGetWitnesses,
+	WitnessesMsg:               handleWitnesses,
+}
+
 // handleBscCap ignores the capability message for backward compatibility.
 // Old nodes send BscCapMsg as part of their handshake, we just ignore it
 // since P2P layer already negotiated the protocol version.
@@ -134,7 +161,11 @@ func handleMessage(backend Backend, peer *Peer) error {
 	defer msg.Discard()
 
 	var handlers = bsc1
-	if peer.Version() >= Bsc2 {
+	if peer.Version() >= Bsc4 {
+		handlers = bsc4
+	} else if peer.Version() >= Bsc3 {
+		handlers = bsc3
+	} else if peer.Version() >= Bsc2 {
 		handlers = bsc2
 	}
 
@@ -237,6 +268,66 @@ func handleBlocksByRange(backend Backend, msg Decoder, peer *Peer) error {
 	return nil
 }
 
+func handleGetWitnesses(backend Backend, msg Decoder, peer *Peer) error {
+	req := new(GetWitnessesPacket)
+	if err := msg.Decode(req); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	if len(req.Hashes) > MaxRequestWitnessesCount {
+		return fmt.Errorf("msg %v, invalid count: %v", GetWitnessesMsg, len(req.Hashes))
+	}
+	var (
+		witnesses    = make([]rlp.RawValue, 0, len(req.Hashes))
+		responseSize int
+	)
+	for _, hash := range req.Hashes {
+		var witness rlp.RawValue
+		if peer.witnessLimit.Allow() {
+			witness = backend.Witness(hash)
+		}
+		if witness == nil {
+			witness = rlp.EmptyString
+		}
+		if len(witnesses) > 0 && responseSize+len(witness) > softResponseLimit {
+			break // already have at least one witness; next entry would overflow
+		}
+		witnesses = append(witnesses, witness)
+		responseSize += len(witness)
+	}
+	log.Debug("reply GetWitnesses msg", "from", peer.id, "req", len(req.Hashes), "witnesses", len(witnesses), "responseSize", responseSize)
+	return p2p.Send(peer.rw, WitnessesMsg, &WitnessesPacket{
+		RequestId: req.RequestId,
+		Witnesses: witnesses,
+	})
+}
+
+func handleWitnesses(backend Backend, msg Decoder, peer *Peer) error {
+	res := new(WitnessesPacket)
+	if err := msg.Decode(res); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	err := peer.dispatcher.DispatchResponse(&Response{
+		requestID: res.RequestId,
+		data:      res,
+		code:      WitnessesMsg,
+	})
+	log.Debug("receive Witnesses response", "from", peer.id, "requestId", res.RequestId, "witnesses", len(res.Witnesses), "err", err)
+	return nil
+}
+
+func handleConditionalTransactions(backend Backend, msg Decoder, peer *Peer) error {
+	ann := new(ConditionalTransactionsPacket)
+	if err := msg.Decode(ann); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	for i, tx := range ann.Txs {
+		if tx == nil || tx.Tx == nil {
+			return fmt.Errorf("%w: conditional transaction %d is nil", errDecode, i)
+		}
+	}
+	return backend.Handle(peer, ann)
+}
+
 // NodeInfo represents a short summary of the `bsc` sub-protocol metadata
 // known about the host peer.
 type NodeInfo struct{}
@@ -1,6 +1,8 @@
 package bsc
 
 import (
+	"bytes"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -8,12 +10,15 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 // mockBackend implements the Backend interface for testing
 type mockBackend struct {
-	chain *core.BlockChain
+	chain     *core.BlockChain
+	witnesses map[common.Hash]rlp.RawValue
 }
 
 func (b *mockBackend) Chain() *core.BlockChain {
@@ -32,6 +37,10 @@ func (b *mockBackend) Handle(peer *Peer, packet Packet) error {
 	return nil
 }
 
+func (b *mockBackend) Witness(hash common.Hash) rlp.RawValue {
+	return b.witnesses[hash]
+}
+
 // mockMsg implements the Decoder interface for testing
 type mockMsg struct {
 	code uint64
@@ -45,6 +54,8 @@ func (m *mockMsg) Decode(val interface{}) error {
 		*v = *m.data.(*GetBlocksByRangePacket)
 	case *BlocksByRangePacket:
 		*v = *m.data.(*BlocksByRangePacket)
+	case *GetWitnessesPacket:
+		*v = *m.data.(*GetWitnessesPacket)
 	}
 	return nil
 }
@@ -247,3 +258,99 @@ func TestHandleBlocksByRange(t *testing.T) {
 		})
 	}
 }
+
+// Tests that witnesses are requested and served between bsc/4 peers, with the
+// unavailable ones replaced by empty entries.
+func TestRequestWitnesses(t *testing.T) {
+	var (
+		known   = common.HexToHash("0x01")
+		unknown = common.HexToHash("0x02")
+		witness = rlp.RawValue{0xc3, 0x01, 0x02, 0x03}
+		server  = &mockBackend{witnesses: map[common.Hash]rlp.RawValue{known: witness}}
+	)
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	local := NewPeer(Bsc4, p2p.NewPeer(enode.ID{1}, "local", nil), app)
+	remote := NewPeer(Bsc4, p2p.NewPeer(enode.ID{2}, "remote", nil), net)
+	defer local.Close()
+	defer remote.Close()
+
+	go Handle(new(mockBackend), local)
+	go Handle(server, remote)
+
+	witnesses, err := local.RequestWitnesses([]common.Hash{known, unknown})
+	if err != nil {
+		t.Fatalf("failed to request witnesses: %v", err)
+	}
+	if len(witnesses) != 2 {
+		t.Fatalf("witness count mismatch: have %d, want 2", len(witnesses))
+	}
+	if !bytes.Equal(witnesses[0], witness) {
+		t.Errorf("witness mismatch: have %x, want %x", witnesses[0], witness)
+	}
+	if !bytes.Equal(witnesses[1], rlp.EmptyString) {
+		t.Errorf("unavailable witness mismatch: have %x", witnesses[1])
+	}
+	// Older peers don't support witnesses at all
+	old := NewPeer(Bsc3, p2p.NewPeer(enode.ID{3}, "old", nil), app)
+	defer old.Close()
+	if _, err := old.RequestWitnesses([]common.Hash{known}); !errors.Is(err, errWitnessesUnsupported) {
+		t.Errorf("error mismatch: have %v, want %v", err, errWitnessesUnsupported)
+	}
+}
+
+func TestHandleGetWitnessesLimit(t *testing.T) {
+	hashes := make([]common.Hash, MaxRequestWitnessesCount+1)
+	msg := &mockMsg{code: GetWitnessesMsg, data: &GetWitnessesPacket{RequestId: 1, Hashes: hashes}}
+	if err := handleGetWitnesses(new(mockBackend), msg, newMockPeer().Peer); err == nil {
+		t.Error("oversized witness request accepted")
+	}
+}
+
+// Tests that the witnesses served to a peer are rate limited, with the requests
+// over the limit answered by empty entries.
+func TestServeWitnessesRateLimit(t *testing.T) {
+	var (
+		known   = common.HexToHash("0x01")
+		witness = rlp.RawValue{0xc3, 0x01, 0x02, 0x03}
+		server  = &mockBackend{witnesses: map[common.Hash]rlp.RawValue{known: witness}}
+	)
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	local := NewPeer(Bsc4, p2p.NewPeer(enode.ID{1}, "local", nil), app)
+	remote := NewPeer(Bsc4, p2p.NewPeer(enode.ID{2}, "remote", nil), net)
+	defer local.Close()
+	defer remote.Close()
+
+	go Handle(new(mockBackend), local)
+	go Handle(server, remote)
+
+	hashes := make([]common.Hash, MaxRequestWitnessesCount)
+	for i := range hashes {
+		hashes[i] = known
+	}
+	served := func() int {
+		witnesses, err := local.RequestWitnesses(hashes)
+		if err != nil {
+			t.Fatalf("failed to request witnesses: %v", err)
+		}
+		var n int
+		for _, w := range witnesses {
+			if bytes.Equal(w, witness) {
+				n++
+			}
+		}
+		return n
+	}
+	if n := served(); n != MaxRequestWitnessesCount {
+		t.Fatalf("served witness count mismatch: have %d, want %d", n, MaxRequestWitnessesCount)
+	}
+	// The budget is exhausted, only the refill since the last request is served
+	if n := served(); n > 1 {
+		t.Errorf("served witness count over the limit: have %d, want at most 1", n)
+	}
+}
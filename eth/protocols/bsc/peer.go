@@ -10,6 +10,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -20,6 +22,10 @@ const (
 	// voteBufferSize is the maximum number of batch votes can be hold before sending
 	voteBufferSize = 21 * 2
 
+	// conditionalTxBufferSize is the maximum number of conditional transaction
+	// batches that can be held before sending.
+	conditionalTxBufferSize = 64
+
 	// used to avoid of DDOS attack
 	// It's the max number of received votes per second from one peer
 	// 21 validators exist now, so 21 votes will be produced every one block interval
@@ -28,16 +34,29 @@ const (
 
 	// the time span of one period
 	secondsPerPeriod = float64(30)
+
+	// witnessRequestTimeout is the time allowed for a remote peer to deliver
+	// the requested witnesses.
+	witnessRequestTimeout = 2 * time.Second
+
+	// witnessServeRate is the number of witnesses served to one peer per second,
+	// a couple of times the block rate to leave room for retries. Requests over
+	// the limit are answered with empty entries.
+	witnessServeRate = 4
 )
 
+var errWitnessesUnsupported = errors.New("peer doesn't support witnesses")
+
 // Peer is a collection of relevant information we have about a `bsc` peer.
 type Peer struct {
 	id            string                     // Unique ID for the peer, cached
 	knownVotes    *knownCache                // Set of vote hashes known to be known by this peer
 	voteBroadcast chan []*types.VoteEnvelope // Channel used to queue votes propagation requests
+	txBroadcast   chan []*types.Transaction  // Channel used to queue conditional transaction propagation requests
 	periodBegin   time.Time                  // Begin time of the latest period for votes counting
 	periodCounter uint                       // Votes number in the latest period
 	dispatcher    *Dispatcher                // Message request-response dispatcher
+	witnessLimit  *rate.Limiter              // Rate limiter of the witnesses served to the peer
 
 	*p2p.Peer                   // The embedded P2P package peer
 	rw        p2p.MsgReadWriter // Input/output streams for bsc
@@ -54,8 +73,10 @@ func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 		id:            id,
 		knownVotes:    newKnownCache(maxKnownVotes),
 		voteBroadcast: make(chan []*types.VoteEnvelope, voteBufferSize),
+		txBroadcast:   make(chan []*types.Transaction, conditionalTxBufferSize),
 		periodBegin:   time.Now(),
 		periodCounter: 0,
+		witnessLimit:  rate.NewLimiter(witnessServeRate, MaxRequestWitnessesCount),
 		Peer:          p,
 		rw:            rw,
 		version:       version,
@@ -64,6 +85,9 @@ func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 	}
 	peer.dispatcher = NewDispatcher(peer)
 	go peer.broadcastVotes()
+	if version >= Bsc3 {
+		go peer.broadcastConditionalTransactions()
+	}
 	return peer
 }
 
@@ -124,6 +148,37 @@ func (p *Peer) AsyncSendVotes(votes []*types.VoteEnvelope) {
 	}
 }
 
+// sendConditionalTransactions propagates a batch of conditional transactions
+// together with their conditions to the remote peer.
+func (p *Peer) sendConditionalTransactions(txs []*types.Transaction) error {
+	packet := &ConditionalTransactionsPacket{Txs: make([]*ConditionalTransaction, 0, len(txs))}
+	for _, tx := range txs {
+		ctx, err := NewConditionalTransaction(tx)
+		if err != nil {
+			p.Log().Debug("Failed to encode transaction conditions", "hash", tx.Hash(), "err", err)
+			continue
+		}
+		packet.Txs = append(packet.Txs, ctx)
+	}
+	return p2p.Send(p.rw, ConditionalTransactionsMsg, packet)
+}
+
+// AsyncSendConditionalTransactions queues a batch of conditional transactions for
+// propagation to a remote peer. If the peer's broadcast queue is full or the peer
+// doesn't support conditional transactions, the event is silently dropped.
+func (p *Peer) AsyncSendConditionalTransactions(txs []*types.Transaction) {
+	if p.version < Bsc3 {
+		return
+	}
+	select {
+	case p.txBroadcast <- txs:
+	case <-p.term:
+		p.Log().Debug("Dropping conditional transaction propagation for closed peer", "count", len(txs))
+	default:
+		p.Log().Debug("Dropping conditional transaction propagation for abnormal peer", "count", len(txs))
+	}
+}
+
 // IsOverLimitAfterReceivingVotes increments the per-period vote counter by n
 // and reports whether the rolling 30s budget has been exceeded.
 // The budget is `secondsPerPeriod * receiveRateLimitPerSecond` votes per peer.
@@ -158,6 +213,23 @@ func (p *Peer) broadcastVotes() {
 	}
 }
 
+// broadcastConditionalTransactions is a write loop that schedules conditional
+// transaction broadcasts to the remote peer.
+func (p *Peer) broadcastConditionalTransactions() {
+	for {
+		select {
+		case txs := <-p.txBroadcast:
+			if err := p.sendConditionalTransactions(txs); err != nil {
+				return
+			}
+			p.Log().Trace("Sent conditional transactions", "count", len(txs))
+
+		case <-p.term:
+			return
+		}
+	}
+}
+
 // knownCache is a cache for known hashes.
 type knownCache struct {
 	hashes mapset.Set[common.Hash]
@@ -215,3 +287,33 @@ func (p *Peer) RequestBlocksByRange(startHeight uint64, startHash common.Hash, c
 
 	return ret.Blocks, nil
 }
+
+// RequestWitnesses send GetWitnessesMsg to fetch the execution witnesses of the
+// given blocks. Witnesses are large, so the request is allowed considerably more
+// time than the other ones. The witnesses the remote peer hasn't built yet are
+// returned as empty entries.
+func (p *Peer) RequestWitnesses(hashes []common.Hash) ([]rlp.RawValue, error) {
+	if p.version < Bsc4 {
+		return nil, errWitnessesUnsupported
+	}
+	requestID := p.dispatcher.GenRequestID()
+	res, err := p.dispatcher.DispatchRequest(&Request{
+		code:      GetWitnessesMsg,
+		want:      WitnessesMsg,
+		requestID: requestID,
+		data: &GetWitnessesPacket{
+			RequestId: requestID,
+			Hashes:    hashes,
+		},
+		timeout: witnessRequestTimeout,
+	})
+	log.Debug("RequestWitnesses result", "requestID", requestID, "ret", res == nil, "err", err)
+	if err != nil {
+		return nil, err
+	}
+	ret, ok := res.(*WitnessesPacket)
+	if !ok {
+		return nil, errors.New("unexpected response type")
+	}
+	return ret.Witnesses, nil
+}
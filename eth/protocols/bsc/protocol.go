@@ -1,6 +1,7 @@
 package bsc
 
 import (
+	"encoding/json"
 	"errors"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -12,6 +13,8 @@ import (
 const (
 	Bsc1 = 1
 	Bsc2 = 2
+	Bsc3 = 3
+	Bsc4 = 4
 )
 
 // ProtocolName is the official short name of the `bsc` protocol used during
@@ -20,11 +23,11 @@ const ProtocolName = "bsc"
 
 // ProtocolVersions are the supported versions of the `bsc` protocol (first
 // is primary).
-var ProtocolVersions = []uint{Bsc1, Bsc2}
+var ProtocolVersions = []uint{Bsc1, Bsc2, Bsc3, Bsc4}
 
 // protocolLengths are the number of implemented message corresponding to
 // different protocol versions.
-var protocolLengths = map[uint]uint64{Bsc1: 2, Bsc2: 4}
+var protocolLengths = map[uint]uint64{Bsc1: 2, Bsc2: 4, Bsc3: 5, Bsc4: 7}
 
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 10 * 1024 * 1024
@@ -34,6 +37,11 @@ const (
 	VotesMsg            = 0x01
 	GetBlocksByRangeMsg = 0x02 // it can request (StartBlockHeight-Count, StartBlockHeight] range blocks from remote peer
 	BlocksByRangeMsg    = 0x03 // the replied blocks from remote peer
+
+	ConditionalTransactionsMsg = 0x04 // transactions with their inclusion conditions, only relayed between trusted peers
+
+	GetWitnessesMsg = 0x05 // request the execution witnesses of blocks by hash
+	WitnessesMsg    = 0x06 // the replied witnesses from remote peer
 )
 
 var defaultExtra = []byte{0x00}
@@ -105,6 +113,42 @@ type BlocksByRangePacket struct {
 func (*BlocksByRangePacket) Name() string { return "BlocksByRange" }
 func (*BlocksByRangePacket) Kind() byte   { return BlocksByRangeMsg }
 
+// ConditionalTransaction is a transaction together with the inclusion conditions
+// it was submitted with. The conditions are JSON encoded, since they contain
+// maps which are not supported by RLP.
+type ConditionalTransaction struct {
+	Tx         *types.Transaction
+	Conditions []byte
+}
+
+// NewConditionalTransaction wraps a transaction with its attached conditions.
+func NewConditionalTransaction(tx *types.Transaction) (*ConditionalTransaction, error) {
+	conditions, err := json.Marshal(tx.Conditions())
+	if err != nil {
+		return nil, err
+	}
+	return &ConditionalTransaction{Tx: tx, Conditions: conditions}, nil
+}
+
+// Unwrap decodes the conditions and attaches them to the transaction.
+func (c *ConditionalTransaction) Unwrap() (*types.Transaction, error) {
+	opts := new(types.TransactionOpts)
+	if err := json.Unmarshal(c.Conditions, opts); err != nil {
+		return nil, err
+	}
+	c.Tx.SetConditions(opts)
+	return c.Tx, nil
+}
+
+// ConditionalTransactionsPacket is the network packet for relaying conditional
+// transactions to trusted peers.
+type ConditionalTransactionsPacket struct {
+	Txs []*ConditionalTransaction
+}
+
+func (*ConditionalTransactionsPacket) Name() string { return "ConditionalTransactions" }
+func (*ConditionalTransactionsPacket) Kind() byte   { return ConditionalTransactionsMsg }
+
 // BlocksByRangeRLPPacket mirrors BlocksByRangePacket on the wire but carries
 // pre-encoded entries, letting the server reuse RLP bytes already produced for
 // size accounting and avoid a redundant encode pass in p2p.Send.
@@ -112,3 +156,24 @@ type BlocksByRangeRLPPacket struct {
 	RequestId uint64
 	Blocks    []rlp.RawValue
 }
+
+// GetWitnessesPacket requests the execution witnesses of the given blocks.
+type GetWitnessesPacket struct {
+	RequestId uint64
+	Hashes    []common.Hash
+}
+
+func (*GetWitnessesPacket) Name() string { return "GetWitnesses" }
+func (*GetWitnessesPacket) Kind() byte   { return GetWitnessesMsg }
+
+// WitnessesPacket is the reply to GetWitnessesPacket. The witnesses are RLP
+// encoded stateless.Witness objects in the order of the requested hashes, with
+// empty strings in place of the ones the remote peer doesn't serve. The reply
+// may be cut short if the witnesses exceed the response size limit.
+type WitnessesPacket struct {
+	RequestId uint64
+	Witnesses []rlp.RawValue
+}
+
+func (*WitnessesPacket) Name() string { return "Witnesses" }
+func (*WitnessesPacket) Kind() byte   { return WitnessesMsg }
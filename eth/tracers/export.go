@@ -0,0 +1,512 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// defaultExportSegment is the number of blocks exported into a single file
+	// if not configured otherwise.
+	defaultExportSegment = 1000
+
+	// maxRunningExports is the maximum number of trace export jobs running
+	// concurrently.
+	maxRunningExports = 4
+)
+
+// Trace export job states.
+const (
+	ExportRunning   = "running"
+	ExportDone      = "done"
+	ExportFailed    = "failed"
+	ExportCancelled = "cancelled"
+)
+
+var (
+	errExportNotFound  = errors.New("trace export job not found")
+	errExportInterrupt = errors.New("trace export interrupted")
+)
+
+// TraceExportConfig holds the parameters of a trace export job.
+type TraceExportConfig struct {
+	TraceConfig
+	SegmentSize *hexutil.Uint64 // Number of blocks exported into a single file
+}
+
+// TraceExportStatus is the progress of a trace export job.
+type TraceExportStatus struct {
+	ID          string         `json:"id"`
+	Start       hexutil.Uint64 `json:"start"`       // First block to export
+	End         hexutil.Uint64 `json:"end"`         // Last block to export
+	Next        hexutil.Uint64 `json:"next"`        // First block not written into a file yet
+	SegmentSize hexutil.Uint64 `json:"segmentSize"` // Number of blocks exported into a single file
+	State       string         `json:"state"`
+	Error       string         `json:"error,omitempty"`
+	Dir         string         `json:"dir"`   // Directory the files are written into
+	Files       []string       `json:"files"` // Files written so far, in block order
+}
+
+// exportJob is a trace export job, persisted in its directory to be resumed
+// after a restart.
+type exportJob struct {
+	Status TraceExportStatus `json:"status"`
+	Config *TraceConfig      `json:"config"`
+
+	quit      chan error // Closed to interrupt the job
+	quitOnce  sync.Once
+	cancelled bool // Whether the job was cancelled by the user, protected by the exporter lock
+}
+
+// interrupt stops the tracing of the job.
+func (job *exportJob) interrupt() {
+	job.quitOnce.Do(func() { close(job.quit) })
+}
+
+// Exporter runs trace export jobs in the background, writing the traces of a
+// block range into gzip compressed newline-delimited JSON files. The jobs are
+// independent of the RPC connection which started them and are resumed from
+// the last written file after a restart.
+type Exporter struct {
+	api  *API
+	dir  string
+	jobs map[string]*exportJob
+
+	closed bool
+	wg     sync.WaitGroup
+	lock   sync.Mutex
+}
+
+// NewExporter creates a trace exporter writing into the given directory.
+func NewExporter(backend Backend, dir string) *Exporter {
+	return &Exporter{
+		api:  NewAPI(backend),
+		dir:  dir,
+		jobs: make(map[string]*exportJob),
+	}
+}
+
+// Start loads the persisted jobs and resumes the unfinished ones. It
+// implements node.Lifecycle.
+func (e *Exporter) Start() error {
+	if err := os.MkdirAll(e.dir, 0755); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		blob, err := os.ReadFile(filepath.Join(e.dir, entry.Name(), "job.json"))
+		if err != nil {
+			continue
+		}
+		job := &exportJob{quit: make(chan error)}
+		if err := json.Unmarshal(blob, job); err != nil || job.Status.ID != entry.Name() {
+			log.Warn("Skipping invalid trace export job", "dir", entry.Name(), "err", err)
+			continue
+		}
+		job.Status.Dir = filepath.Join(e.dir, job.Status.ID)
+		e.jobs[job.Status.ID] = job
+
+		if job.Status.State == ExportRunning {
+			log.Info("Resuming trace export", "id", job.Status.ID, "next", uint64(job.Status.Next), "end", uint64(job.Status.End))
+			e.wg.Add(1)
+			go e.run(job)
+		}
+	}
+	return nil
+}
+
+// Stop interrupts the running jobs, leaving them to be resumed on the next
+// start. It implements node.Lifecycle.
+func (e *Exporter) Stop() error {
+	e.lock.Lock()
+	e.closed = true
+	for _, job := range e.jobs {
+		job.interrupt()
+	}
+	e.lock.Unlock()
+
+	e.wg.Wait()
+	return nil
+}
+
+// start validates the requested range and configuration and starts a new job.
+func (e *Exporter) start(ctx context.Context, start, end rpc.BlockNumber, config *TraceExportConfig) (string, error) {
+	if config == nil || config.Tracer == nil {
+		return "", errors.New("tracer required")
+	}
+	if DefaultDirectory.IsJS(*config.Tracer) {
+		return "", errors.New("only native tracers can be used for exports")
+	}
+	from, err := e.api.blockByNumber(ctx, start)
+	if err != nil {
+		return "", err
+	}
+	to, err := e.api.blockByNumber(ctx, end)
+	if err != nil {
+		return "", err
+	}
+	// The genesis block has nothing to trace
+	first := max(from.NumberU64(), 1)
+	if to.NumberU64() < first {
+		return "", fmt.Errorf("end block (#%d) needs to come after start block (#%d)", to.NumberU64(), first)
+	}
+	segment := uint64(defaultExportSegment)
+	if config.SegmentSize != nil {
+		segment = uint64(*config.SegmentSize)
+	}
+	if segment == 0 {
+		return "", errors.New("segment size must be positive")
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	job := &exportJob{
+		Status: TraceExportStatus{
+			ID:          hex.EncodeToString(id[:]),
+			Start:       hexutil.Uint64(first),
+			End:         hexutil.Uint64(to.NumberU64()),
+			Next:        hexutil.Uint64(first),
+			SegmentSize: hexutil.Uint64(segment),
+			State:       ExportRunning,
+			Files:       []string{},
+		},
+		Config: &config.TraceConfig,
+		quit:   make(chan error),
+	}
+	job.Status.Dir = filepath.Join(e.dir, job.Status.ID)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.closed {
+		return "", errExportInterrupt
+	}
+	var running int
+	for _, other := range e.jobs {
+		if other.Status.State == ExportRunning {
+			running++
+		}
+	}
+	if running >= maxRunningExports {
+		return "", fmt.Errorf("too many running trace exports (max %d)", maxRunningExports)
+	}
+	if err := os.MkdirAll(job.Status.Dir, 0755); err != nil {
+		return "", err
+	}
+	if err := job.persist(); err != nil {
+		return "", err
+	}
+	e.jobs[job.Status.ID] = job
+
+	log.Info("Starting trace export", "id", job.Status.ID, "start", first, "end", to.NumberU64(), "tracer", *config.Tracer)
+	e.wg.Add(1)
+	go e.run(job)
+	return job.Status.ID, nil
+}
+
+// status returns the progress of a job.
+func (e *Exporter) status(id string) (*TraceExportStatus, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	job, ok := e.jobs[id]
+	if !ok {
+		return nil, errExportNotFound
+	}
+	status := job.Status
+	status.Files = append([]string{}, job.Status.Files...)
+	return &status, nil
+}
+
+// cancel interrupts a running job for good.
+func (e *Exporter) cancel(id string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	job, ok := e.jobs[id]
+	if !ok {
+		return errExportNotFound
+	}
+	if job.Status.State != ExportRunning {
+		return fmt.Errorf("trace export job %s", job.Status.State)
+	}
+	job.cancelled = true
+	job.interrupt()
+	return nil
+}
+
+// run executes a job, recording its outcome once it terminates.
+func (e *Exporter) run(job *exportJob) {
+	defer e.wg.Done()
+
+	err := e.export(job)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	switch {
+	case job.cancelled:
+		job.Status.State = ExportCancelled
+	case err == nil:
+		job.Status.State = ExportDone
+	case errors.Is(err, errExportInterrupt):
+		// Shutting down, leave the job to be resumed
+		log.Info("Trace export interrupted", "id", job.Status.ID, "next", uint64(job.Status.Next))
+		return
+	default:
+		job.Status.State = ExportFailed
+		job.Status.Error = err.Error()
+	}
+	if err := job.persist(); err != nil {
+		log.Error("Failed to persist trace export job", "id", job.Status.ID, "err", err)
+	}
+	log.Info("Trace export terminated", "id", job.Status.ID, "state", job.Status.State, "next", uint64(job.Status.Next), "err", err)
+}
+
+// export traces the remaining blocks of the job, committing a file whenever
+// all blocks of a segment are written.
+func (e *Exporter) export(job *exportJob) error {
+	var (
+		ctx   = context.Background()
+		next  = uint64(job.Status.Next)
+		end   = uint64(job.Status.End)
+		start = uint64(job.Status.Start)
+		size  = uint64(job.Status.SegmentSize)
+	)
+	if next > end {
+		return nil
+	}
+	// The traced chain range excludes the start block
+	from, err := e.api.blockByNumber(ctx, rpc.BlockNumber(next-1))
+	if err != nil {
+		return err
+	}
+	to, err := e.api.blockByNumber(ctx, rpc.BlockNumber(end))
+	if err != nil {
+		return err
+	}
+	var (
+		results = e.api.traceChain(from, to, job.Config, job.quit)
+		seg     *exportSegment
+		last    = next - 1
+		failed  error
+	)
+	defer func() {
+		if seg != nil {
+			seg.discard()
+		}
+	}()
+	for res := range results {
+		// Keep draining the results after a failure until the tracer stops
+		if failed != nil {
+			continue
+		}
+		number := uint64(res.Block)
+		if seg != nil && number > seg.last {
+			if failed = e.commit(job, seg); failed != nil {
+				job.interrupt()
+				continue
+			}
+			seg = nil
+		}
+		if seg == nil {
+			first := start + (number-start)/size*size
+			if seg, failed = newExportSegment(job.Status.Dir, first, min(first+size-1, end)); failed != nil {
+				job.interrupt()
+				continue
+			}
+		}
+		if failed = seg.write(res); failed != nil {
+			job.interrupt()
+			continue
+		}
+		last = number
+	}
+	if failed != nil {
+		return failed
+	}
+	// The end block is always delivered unless the tracing was aborted
+	if last != end {
+		select {
+		case <-job.quit:
+			return errExportInterrupt
+		default:
+			return fmt.Errorf("chain tracing aborted at block #%d", last+1)
+		}
+	}
+	if seg != nil {
+		err := e.commit(job, seg)
+		seg = nil
+		return err
+	}
+	e.lock.Lock()
+	job.Status.Next = hexutil.Uint64(end + 1)
+	e.lock.Unlock()
+	return nil
+}
+
+// commit finalizes the file of a segment and advances the job past it.
+func (e *Exporter) commit(job *exportJob, seg *exportSegment) error {
+	name, err := seg.commit()
+	if err != nil {
+		return err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	job.Status.Next = hexutil.Uint64(seg.last + 1)
+	job.Status.Files = append(job.Status.Files, name)
+	return job.persist()
+}
+
+// persist writes the job into its directory. The caller must hold the exporter
+// lock.
+func (job *exportJob) persist() error {
+	blob, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(job.Status.Dir, "job.json")
+	if err := os.WriteFile(path+".tmp", blob, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// exportSegment is the file of a trace export segment being written. The file
+// is written under a temporary name and only renamed once complete.
+type exportSegment struct {
+	first, last uint64
+	path        string
+	file        *os.File
+	gz          *gzip.Writer
+	enc         *json.Encoder
+}
+
+func newExportSegment(dir string, first, last uint64) (*exportSegment, error) {
+	path := filepath.Join(dir, fmt.Sprintf("%d-%d.jsonl.gz", first, last))
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(file)
+	return &exportSegment{
+		first: first,
+		last:  last,
+		path:  path,
+		file:  file,
+		gz:    gz,
+		enc:   json.NewEncoder(gz),
+	}, nil
+}
+
+// write appends the traces of a block to the segment.
+func (s *exportSegment) write(res *blockTraceResult) error {
+	return s.enc.Encode(res)
+}
+
+// commit flushes the segment and moves it to its final name, returning it.
+func (s *exportSegment) commit() (string, error) {
+	if err := s.gz.Close(); err != nil {
+		s.discard()
+		return "", err
+	}
+	if err := s.file.Sync(); err != nil {
+		s.discard()
+		return "", err
+	}
+	if err := s.file.Close(); err != nil {
+		os.Remove(s.file.Name())
+		return "", err
+	}
+	if err := os.Rename(s.file.Name(), s.path); err != nil {
+		return "", err
+	}
+	return filepath.Base(s.path), nil
+}
+
+// discard drops the partially written segment.
+func (s *exportSegment) discard() {
+	s.file.Close()
+	os.Remove(s.file.Name())
+}
+
+// APIs returns the RPC services of the exporter. They write into the data
+// directory, so they are only served on the authenticated endpoints.
+func (e *Exporter) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace:     "debug",
+			Service:       NewExportAPI(e),
+			Authenticated: true,
+		},
+	}
+}
+
+// ExportAPI is the collection of trace export APIs.
+type ExportAPI struct {
+	exporter *Exporter
+}
+
+// NewExportAPI creates the trace export APIs of the given exporter.
+func NewExportAPI(exporter *Exporter) *ExportAPI {
+	return &ExportAPI{exporter: exporter}
+}
+
+// StartTraceExport starts a background job tracing the given block range,
+// both ends included, with a native tracer. The traces are written into gzip
+// compressed newline-delimited JSON files, one per segment of blocks. The job
+// keeps running after the client disconnects and is resumed after a restart.
+// It returns the identifier of the job.
+func (api *ExportAPI) StartTraceExport(ctx context.Context, start, end rpc.BlockNumber, config *TraceExportConfig) (string, error) {
+	return api.exporter.start(ctx, start, end, config)
+}
+
+// TraceExportStatus returns the progress of a trace export job.
+func (api *ExportAPI) TraceExportStatus(id string) (*TraceExportStatus, error) {
+	return api.exporter.status(id)
+}
+
+// CancelTraceExport stops a running trace export job. The files already
+// written are kept.
+func (api *ExportAPI) CancelTraceExport(id string) error {
+	return api.exporter.cancel(id)
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func init() {
+	DefaultDirectory.Register("exportTestTracer", func(ctx *Context, cfg json.RawMessage, chainConfig *params.ChainConfig) (*Tracer, error) {
+		return &Tracer{
+			Hooks:     &tracing.Hooks{},
+			GetResult: func() (json.RawMessage, error) { return json.RawMessage(`{}`), nil },
+			Stop:      func(err error) {},
+		}, nil
+	}, false)
+}
+
+func newExportTestBackend(t *testing.T, n int) *testBackend {
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  types.GenesisAlloc{accounts[0].addr: {Balance: big.NewInt(params.Ether)}},
+	}
+	signer := types.HomesteadSigner{}
+	return newTestBackend(t, n, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), accounts[1].addr, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, accounts[0].key)
+		b.AddTx(tx)
+	})
+}
+
+// waitExport waits until the given export job terminates.
+func waitExport(t *testing.T, api *ExportAPI, id string) *TraceExportStatus {
+	t.Helper()
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		status, err := api.TraceExportStatus(id)
+		if err != nil {
+			t.Fatalf("failed to retrieve export status: %v", err)
+		}
+		if status.State != ExportRunning {
+			return status
+		}
+	}
+	t.Fatal("trace export did not terminate")
+	return nil
+}
+
+// readExportFile returns the block numbers contained in an export file.
+func readExportFile(t *testing.T, path string) []uint64 {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open export file: %v", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("failed to decompress export file: %v", err)
+	}
+	var numbers []uint64
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var res blockTraceResult
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			t.Fatalf("failed to decode export line: %v", err)
+		}
+		if len(res.Traces) != 1 || res.Traces[0].Error != "" {
+			t.Errorf("block %d: trace mismatch: %s", res.Block, scanner.Bytes())
+		}
+		numbers = append(numbers, uint64(res.Block))
+	}
+	return numbers
+}
+
+func TestTraceExport(t *testing.T) {
+	backend := newExportTestBackend(t, 10)
+	defer backend.teardown()
+
+	exporter := NewExporter(backend, t.TempDir())
+	if err := exporter.Start(); err != nil {
+		t.Fatalf("failed to start exporter: %v", err)
+	}
+	defer exporter.Stop()
+
+	// The jobs write into the data directory, only authenticated clients
+	// may start them
+	for _, api := range exporter.APIs() {
+		if !api.Authenticated {
+			t.Errorf("export API %s served unauthenticated", api.Namespace)
+		}
+	}
+	var (
+		api     = NewExportAPI(exporter)
+		tracer  = "exportTestTracer"
+		segment = hexutil.Uint64(4)
+	)
+	id, err := api.StartTraceExport(context.Background(), 0, rpc.LatestBlockNumber, &TraceExportConfig{TraceConfig: TraceConfig{Tracer: &tracer}, SegmentSize: &segment})
+	if err != nil {
+		t.Fatalf("failed to start export: %v", err)
+	}
+	status := waitExport(t, api, id)
+	if status.State != ExportDone || status.Next != 11 {
+		t.Fatalf("export not finished: %+v", status)
+	}
+	want := map[string][]uint64{
+		"1-4.jsonl.gz":  {1, 2, 3, 4},
+		"5-8.jsonl.gz":  {5, 6, 7, 8},
+		"9-10.jsonl.gz": {9, 10},
+	}
+	if !slices.Equal(status.Files, []string{"1-4.jsonl.gz", "5-8.jsonl.gz", "9-10.jsonl.gz"}) {
+		t.Fatalf("file list mismatch: %v", status.Files)
+	}
+	for name, blocks := range want {
+		if have := readExportFile(t, filepath.Join(status.Dir, name)); !slices.Equal(have, blocks) {
+			t.Errorf("%s: blocks mismatch: have %v, want %v", name, have, blocks)
+		}
+	}
+	if err := api.CancelTraceExport(id); err == nil {
+		t.Error("cancelled finished export")
+	}
+	// JS tracers are rejected
+	js := "{result: function() { return 1 }, fault: function() {}}"
+	if _, err := api.StartTraceExport(context.Background(), 0, 1, &TraceExportConfig{TraceConfig: TraceConfig{Tracer: &js}}); err == nil {
+		t.Error("export with JS tracer accepted")
+	}
+}
+
+func TestTraceExportResume(t *testing.T) {
+	backend := newExportTestBackend(t, 10)
+	defer backend.teardown()
+
+	// Persist a job interrupted after its first segment
+	var (
+		dir    = t.TempDir()
+		tracer = "exportTestTracer"
+		job    = &exportJob{
+			Status: TraceExportStatus{
+				ID:          "interrupted",
+				Start:       1,
+				End:         10,
+				Next:        5,
+				SegmentSize: 4,
+				State:       ExportRunning,
+				Dir:         filepath.Join(dir, "interrupted"),
+				Files:       []string{"1-4.jsonl.gz"},
+			},
+			Config: &TraceConfig{Tracer: &tracer},
+		}
+	)
+	if err := os.MkdirAll(job.Status.Dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := job.persist(); err != nil {
+		t.Fatalf("failed to persist job: %v", err)
+	}
+	exporter := NewExporter(backend, dir)
+	if err := exporter.Start(); err != nil {
+		t.Fatalf("failed to start exporter: %v", err)
+	}
+	defer exporter.Stop()
+
+	status := waitExport(t, NewExportAPI(exporter), "interrupted")
+	if status.State != ExportDone {
+		t.Fatalf("export not finished: %+v", status)
+	}
+	if !slices.Equal(status.Files, []string{"1-4.jsonl.gz", "5-8.jsonl.gz", "9-10.jsonl.gz"}) {
+		t.Fatalf("file list mismatch: %v", status.Files)
+	}
+	if have := readExportFile(t, filepath.Join(status.Dir, "5-8.jsonl.gz")); !slices.Equal(have, []uint64{5, 6, 7, 8}) {
+		t.Errorf("blocks mismatch: have %v", have)
+	}
+	// The terminated state is persisted
+	blob, err := os.ReadFile(filepath.Join(status.Dir, "job.json"))
+	if err != nil {
+		t.Fatalf("failed to read job: %v", err)
+	}
+	var persisted exportJob
+	if err := json.Unmarshal(blob, &persisted); err != nil || persisted.Status.State != ExportDone {
+		t.Errorf("persisted state mismatch: %s", blob)
+	}
+}
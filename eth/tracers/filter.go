@@ -0,0 +1,225 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxFilterPrune is the maximum number of blocks pruned from the trace filter
+// index at once, spreading the pruning of a large backlog over many blocks.
+const maxFilterPrune = 1024
+
+// filterTrace is the part of a flat call trace needed to index it.
+type filterTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`
+		RefundAddress *common.Address `json:"refundAddress"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+	} `json:"result"`
+	BlockHash common.Hash `json:"blockHash"`
+}
+
+// parties returns the sender and the receiver of a trace. The receiver of a
+// contract creation is the created contract, the one of a selfdestruct is the
+// beneficiary.
+func (t *filterTrace) parties() (from, to *common.Address) {
+	switch t.Type {
+	case "create":
+		if t.Result != nil {
+			to = t.Result.Address
+		}
+		return t.Action.From, to
+	case "suicide":
+		return t.Action.Address, t.Action.RefundAddress
+	default:
+		return t.Action.From, t.Action.To
+	}
+}
+
+// FilterIndex indexes the flat call traces of the chain by the addresses they
+// move between, so they can be filtered without re-executing the blocks.
+type FilterIndex struct {
+	db      ethdb.KeyValueStore
+	history uint64 // Number of recent blocks to keep indexed, 0 keeps all
+}
+
+// NewFilterIndex creates a trace filter index on top of the given database,
+// keeping the given number of recent blocks indexed.
+func NewFilterIndex(db ethdb.KeyValueStore, history uint64) *FilterIndex {
+	return &FilterIndex{db: db, history: history}
+}
+
+// Tail returns the number of the oldest indexed block, or nil if the index
+// is empty.
+func (idx *FilterIndex) Tail() *uint64 {
+	return rawdb.ReadTraceFilterTail(idx.db)
+}
+
+// WriteBlock indexes the flat call traces of a block, replacing the ones of
+// a block previously indexed at the same height, and prunes the blocks which
+// fell out of the history.
+func (idx *FilterIndex) WriteBlock(number uint64, traces []json.RawMessage) error {
+	batch := idx.db.NewBatch()
+
+	// Drop the address entries of a reorged block, they may not be part of
+	// the new one
+	idx.deleteBlock(batch, number)
+
+	if len(traces) > 0 {
+		roles := make(map[common.Address]byte)
+		for _, raw := range traces {
+			var trace filterTrace
+			if err := json.Unmarshal(raw, &trace); err != nil {
+				return err
+			}
+			from, to := trace.parties()
+			if from != nil {
+				roles[*from] |= rawdb.TraceFilterFrom
+			}
+			if to != nil {
+				roles[*to] |= rawdb.TraceFilterTo
+			}
+		}
+		blob, err := json.Marshal(traces)
+		if err != nil {
+			return err
+		}
+		rawdb.WriteTraceFilterBlock(batch, number, blob)
+		for addr, role := range roles {
+			rawdb.WriteTraceFilterAddress(batch, addr, number, role)
+		}
+	}
+	tail := idx.Tail()
+	if tail == nil || *tail > number {
+		tail = &number
+	}
+	if idx.history > 0 && number >= idx.history {
+		limit := min(number-idx.history+1, *tail+maxFilterPrune)
+		for n := *tail; n < limit; n++ {
+			idx.deleteBlock(batch, n)
+		}
+		if limit > *tail {
+			log.Debug("Pruned trace filter index", "from", *tail, "to", limit-1)
+			tail = &limit
+		}
+	}
+	rawdb.WriteTraceFilterTail(batch, *tail)
+	return batch.Write()
+}
+
+// deleteBlock removes the indexed traces of a block along with its address
+// entries.
+func (idx *FilterIndex) deleteBlock(batch ethdb.Batch, number uint64) {
+	blob := rawdb.ReadTraceFilterBlock(idx.db, number)
+	if len(blob) == 0 {
+		return
+	}
+	var traces []filterTrace
+	if err := json.Unmarshal(blob, &traces); err != nil {
+		log.Warn("Failed to decode indexed traces", "number", number, "err", err)
+	}
+	for i := range traces {
+		from, to := traces[i].parties()
+		if from != nil {
+			rawdb.DeleteTraceFilterAddress(batch, *from, number)
+		}
+		if to != nil {
+			rawdb.DeleteTraceFilterAddress(batch, *to, number)
+		}
+	}
+	rawdb.DeleteTraceFilterBlock(batch, number)
+}
+
+// Filter returns the indexed traces of the blocks in the inclusive range
+// [from, to], sent by any of fromAddrs and received by any of toAddrs. An
+// empty address list matches any address. The canonical callback reports
+// whether the block a trace was recorded in is still part of the chain. The
+// scan stops once limit traces matched, 0 meaning no limit.
+func (idx *FilterIndex) Filter(from, to uint64, fromAddrs, toAddrs []common.Address, canonical func(number uint64, hash common.Hash) bool, limit uint64) ([]json.RawMessage, error) {
+	var numbers []uint64
+	switch {
+	case len(fromAddrs) > 0:
+		numbers = idx.addressBlocks(fromAddrs, from, to, rawdb.TraceFilterFrom)
+	case len(toAddrs) > 0:
+		numbers = idx.addressBlocks(toAddrs, from, to, rawdb.TraceFilterTo)
+	default:
+		for n := from; n <= to; n++ {
+			numbers = append(numbers, n)
+		}
+	}
+	var results []json.RawMessage
+	for _, number := range numbers {
+		blob := rawdb.ReadTraceFilterBlock(idx.db, number)
+		if len(blob) == 0 {
+			continue
+		}
+		var raws []json.RawMessage
+		if err := json.Unmarshal(blob, &raws); err != nil {
+			return nil, err
+		}
+		for i, raw := range raws {
+			var trace filterTrace
+			if err := json.Unmarshal(raw, &trace); err != nil {
+				return nil, err
+			}
+			if i == 0 && canonical != nil && !canonical(number, trace.BlockHash) {
+				break
+			}
+			sender, receiver := trace.parties()
+			if !matchAddress(fromAddrs, sender) || !matchAddress(toAddrs, receiver) {
+				continue
+			}
+			results = append(results, raw)
+			if limit > 0 && uint64(len(results)) >= limit {
+				return results, nil
+			}
+		}
+	}
+	return results, nil
+}
+
+// addressBlocks returns the numbers of the blocks in which any of the given
+// addresses appears in the given role.
+func (idx *FilterIndex) addressBlocks(addrs []common.Address, from, to uint64, role byte) []uint64 {
+	var numbers []uint64
+	for _, addr := range addrs {
+		numbers = append(numbers, rawdb.ReadTraceFilterAddressBlocks(idx.db, addr, from, to, role)...)
+	}
+	slices.Sort(numbers)
+	return slices.Compact(numbers)
+}
+
+// matchAddress reports whether addr is in addrs, an empty list matching any
+// address.
+func matchAddress(addrs []common.Address, addr *common.Address) bool {
+	if len(addrs) == 0 {
+		return true
+	}
+	return addr != nil && slices.Contains(addrs, *addr)
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxFilterRange is the maximum number of blocks scanned by a trace_filter
+// query.
+const maxFilterRange = 10000
+
+var errFilterIndexDisabled = errors.New("trace filter index is disabled, enable the traceFilter live tracer")
+
+// FilterArgs are the arguments of trace_filter.
+type FilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	After       *uint64          `json:"after"`
+	Count       *uint64          `json:"count"`
+}
+
+// FilterAPI serves the flat call traces recorded by the traceFilter live tracer.
+type FilterAPI struct {
+	backend Backend
+	index   *FilterIndex
+}
+
+// NewFilterAPI creates a new API definition for the trace filter methods,
+// serving the traces of the given index.
+func NewFilterAPI(backend Backend, index *FilterIndex) *FilterAPI {
+	return &FilterAPI{backend: backend, index: index}
+}
+
+// Filter returns the flat call traces of the given block range matching the
+// given senders and receivers, skipping the first After matches and returning
+// at most Count of them.
+func (api *FilterAPI) Filter(ctx context.Context, args FilterArgs) ([]json.RawMessage, error) {
+	index := api.index
+	if index == nil {
+		return nil, errFilterIndexDisabled
+	}
+	from, err := api.blockNumber(ctx, args.FromBlock, rpc.EarliestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.blockNumber(ctx, args.ToBlock, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	tail := index.Tail()
+	if tail == nil {
+		return []json.RawMessage{}, nil
+	}
+	if args.FromBlock == nil || *args.FromBlock == rpc.EarliestBlockNumber {
+		from = max(from, *tail)
+	}
+	if from < *tail {
+		return nil, fmt.Errorf("block %d is not indexed, the oldest indexed block is %d", from, *tail)
+	}
+	if to-from >= maxFilterRange {
+		return nil, fmt.Errorf("block range too large, max %d blocks", maxFilterRange)
+	}
+	canonical := func(number uint64, hash common.Hash) bool {
+		header, _ := api.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		return header != nil && header.Hash() == hash
+	}
+	// Stop scanning once the requested page is complete
+	var after, limit uint64
+	if args.After != nil {
+		after = *args.After
+	}
+	if args.Count != nil {
+		if *args.Count == 0 {
+			return []json.RawMessage{}, nil
+		}
+		limit = after + *args.Count
+		if limit < after {
+			limit = 0 // overflow, no limit
+		}
+	}
+	traces, err := index.Filter(from, to, args.FromAddress, args.ToAddress, canonical, limit)
+	if err != nil {
+		return nil, err
+	}
+	traces = traces[min(after, uint64(len(traces))):]
+	if traces == nil {
+		traces = []json.RawMessage{}
+	}
+	return traces, nil
+}
+
+// blockNumber resolves the given block number, or the default one if unset.
+func (api *FilterAPI) blockNumber(ctx context.Context, number *rpc.BlockNumber, def rpc.BlockNumber) (uint64, error) {
+	if number == nil {
+		number = &def
+	}
+	if *number == rpc.EarliestBlockNumber {
+		return 0, nil
+	}
+	header, err := api.backend.HeaderByNumber(ctx, *number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block %v not found", *number)
+	}
+	return header.Number.Uint64(), nil
+}
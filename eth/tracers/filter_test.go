@@ -0,0 +1,210 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func filterTestTrace(typ string, number uint64, from, to common.Address) json.RawMessage {
+	hash := common.BigToHash(common.Big1)
+	switch typ {
+	case "create":
+		return json.RawMessage(fmt.Sprintf(`{"type":"create","action":{"from":"%s"},"result":{"address":"%s"},"blockNumber":%d,"blockHash":"%s"}`, from.Hex(), to.Hex(), number, hash.Hex()))
+	case "suicide":
+		return json.RawMessage(fmt.Sprintf(`{"type":"suicide","action":{"address":"%s","refundAddress":"%s"},"blockNumber":%d,"blockHash":"%s"}`, from.Hex(), to.Hex(), number, hash.Hex()))
+	default:
+		return json.RawMessage(fmt.Sprintf(`{"type":"call","action":{"from":"%s","to":"%s"},"blockNumber":%d,"blockHash":"%s"}`, from.Hex(), to.Hex(), number, hash.Hex()))
+	}
+}
+
+func TestFilterIndex(t *testing.T) {
+	var (
+		alice = common.HexToAddress("0xa1")
+		bob   = common.HexToAddress("0xb0b")
+		carol = common.HexToAddress("0xca401")
+		index = NewFilterIndex(rawdb.NewMemoryDatabase(), 0)
+	)
+	blocks := map[uint64][]json.RawMessage{
+		1: {filterTestTrace("call", 1, alice, bob)},
+		2: {filterTestTrace("create", 2, bob, carol), filterTestTrace("call", 2, carol, alice)},
+		3: nil,
+		4: {filterTestTrace("suicide", 4, carol, bob)},
+	}
+	for number := uint64(1); number <= 4; number++ {
+		if err := index.WriteBlock(number, blocks[number]); err != nil {
+			t.Fatalf("failed to index block %d: %v", number, err)
+		}
+	}
+	if tail := index.Tail(); tail == nil || *tail != 1 {
+		t.Fatalf("tail mismatch: have %v, want 1", tail)
+	}
+	tests := []struct {
+		from, to uint64
+		fromAddr []common.Address
+		toAddr   []common.Address
+		want     []json.RawMessage
+	}{
+		{1, 4, nil, nil, []json.RawMessage{blocks[1][0], blocks[2][0], blocks[2][1], blocks[4][0]}},
+		{2, 3, nil, nil, blocks[2]},
+		{1, 4, []common.Address{bob}, nil, []json.RawMessage{blocks[2][0]}},
+		{1, 4, nil, []common.Address{bob}, []json.RawMessage{blocks[1][0], blocks[4][0]}},
+		{1, 4, []common.Address{alice, carol}, []common.Address{bob}, []json.RawMessage{blocks[1][0], blocks[4][0]}},
+		{1, 4, []common.Address{carol}, []common.Address{alice}, []json.RawMessage{blocks[2][1]}},
+		{3, 4, []common.Address{alice}, nil, nil},
+	}
+	for i, tt := range tests {
+		have, err := index.Filter(tt.from, tt.to, tt.fromAddr, tt.toAddr, nil, 0)
+		if err != nil {
+			t.Fatalf("test %d: filter failed: %v", i, err)
+		}
+		if fmt.Sprint(have) != fmt.Sprint(tt.want) {
+			t.Errorf("test %d: result mismatch:\nhave %s\nwant %s", i, have, tt.want)
+		}
+	}
+	// Traces of blocks no longer canonical are skipped
+	have, err := index.Filter(1, 4, nil, nil, func(number uint64, hash common.Hash) bool { return number != 2 }, 0)
+	if err != nil {
+		t.Fatalf("filter failed: %v", err)
+	}
+	if len(have) != 2 {
+		t.Errorf("non-canonical traces returned: %s", have)
+	}
+	// The scan stops once the limit is reached
+	have, err = index.Filter(1, 4, nil, nil, func(number uint64, hash common.Hash) bool {
+		if number > 2 {
+			t.Errorf("block %d scanned past the limit", number)
+		}
+		return true
+	}, 2)
+	if err != nil {
+		t.Fatalf("filter failed: %v", err)
+	}
+	if fmt.Sprint(have) != fmt.Sprint([]json.RawMessage{blocks[1][0], blocks[2][0]}) {
+		t.Errorf("limited result mismatch: %s", have)
+	}
+}
+
+func TestFilterIndexReorg(t *testing.T) {
+	var (
+		alice = common.HexToAddress("0xa1")
+		bob   = common.HexToAddress("0xb0b")
+		carol = common.HexToAddress("0xca401")
+		db    = rawdb.NewMemoryDatabase()
+		index = NewFilterIndex(db, 0)
+	)
+	if err := index.WriteBlock(1, []json.RawMessage{filterTestTrace("call", 1, alice, bob)}); err != nil {
+		t.Fatalf("failed to index block: %v", err)
+	}
+	reorged := filterTestTrace("call", 1, alice, carol)
+	if err := index.WriteBlock(1, []json.RawMessage{reorged}); err != nil {
+		t.Fatalf("failed to index block: %v", err)
+	}
+	if blocks := rawdb.ReadTraceFilterAddressBlocks(db, bob, 0, 1, rawdb.TraceFilterTo); len(blocks) != 0 {
+		t.Errorf("address of reorged block still indexed: %v", blocks)
+	}
+	have, err := index.Filter(1, 1, []common.Address{alice}, nil, nil, 0)
+	if err != nil {
+		t.Fatalf("filter failed: %v", err)
+	}
+	if len(have) != 1 || string(have[0]) != string(reorged) {
+		t.Errorf("result mismatch: have %s, want %s", have, reorged)
+	}
+}
+
+func TestFilterIndexPruning(t *testing.T) {
+	var (
+		alice = common.HexToAddress("0xa1")
+		bob   = common.HexToAddress("0xb0b")
+		db    = rawdb.NewMemoryDatabase()
+		index = NewFilterIndex(db, 3)
+	)
+	for number := uint64(1); number <= 6; number++ {
+		if err := index.WriteBlock(number, []json.RawMessage{filterTestTrace("call", number, alice, bob)}); err != nil {
+			t.Fatalf("failed to index block %d: %v", number, err)
+		}
+	}
+	if tail := index.Tail(); tail == nil || *tail != 4 {
+		t.Fatalf("tail mismatch: have %v, want 4", tail)
+	}
+	for number := uint64(1); number <= 6; number++ {
+		indexed := len(rawdb.ReadTraceFilterBlock(db, number)) != 0
+		if indexed != (number >= 4) {
+			t.Errorf("block %d: indexed %v", number, indexed)
+		}
+	}
+	if blocks := rawdb.ReadTraceFilterAddressBlocks(db, alice, 0, 6, rawdb.TraceFilterFrom); len(blocks) != 3 || blocks[0] != 4 {
+		t.Errorf("address entries mismatch: %v", blocks)
+	}
+}
+
+func TestFilterAPI(t *testing.T) {
+	var (
+		alice   = common.HexToAddress("0xa1")
+		bob     = common.HexToAddress("0xb0b")
+		backend = newTestBackend(t, 3, &core.Genesis{Config: params.TestChainConfig}, nil)
+		index   = NewFilterIndex(rawdb.NewMemoryDatabase(), 0)
+		traces  []json.RawMessage
+	)
+	defer backend.teardown()
+
+	for number := uint64(1); number <= 3; number++ {
+		hash := backend.chain.GetHeaderByNumber(number).Hash()
+		block := []json.RawMessage{
+			json.RawMessage(fmt.Sprintf(`{"type":"call","action":{"from":"%s","to":"%s"},"blockNumber":%d,"blockHash":"%s","traceAddress":[]}`, alice.Hex(), bob.Hex(), number, hash.Hex())),
+			json.RawMessage(fmt.Sprintf(`{"type":"call","action":{"from":"%s","to":"%s"},"blockNumber":%d,"blockHash":"%s","traceAddress":[0]}`, bob.Hex(), alice.Hex(), number, hash.Hex())),
+		}
+		if err := index.WriteBlock(number, block); err != nil {
+			t.Fatalf("failed to index block %d: %v", number, err)
+		}
+		traces = append(traces, block...)
+	}
+	u64 := func(n uint64) *uint64 { return &n }
+	tests := []struct {
+		after, count *uint64
+		want         []json.RawMessage
+	}{
+		{nil, nil, traces},
+		{u64(1), nil, traces[1:]},
+		{nil, u64(2), traces[:2]},
+		{u64(3), u64(2), traces[3:5]},
+		{u64(5), u64(2), traces[5:]},
+		{u64(6), u64(2), nil},
+		{u64(1), u64(0), nil},
+	}
+	api := NewFilterAPI(backend, index)
+	for i, tt := range tests {
+		have, err := api.Filter(context.Background(), FilterArgs{After: tt.after, Count: tt.count})
+		if err != nil {
+			t.Fatalf("test %d: filter failed: %v", i, err)
+		}
+		if fmt.Sprint(have) != fmt.Sprint(tt.want) {
+			t.Errorf("test %d: result mismatch:\nhave %s\nwant %s", i, have, tt.want)
+		}
+	}
+	if _, err := NewFilterAPI(backend, nil).Filter(context.Background(), FilterArgs{}); err != errFilterIndexDisabled {
+		t.Errorf("error mismatch: have %v, want %v", err, errFilterIndexDisabled)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/parlia/parliatest"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type parliaSupplyInfo struct {
+	GenesisAlloc *hexutil.Big `json:"genesisAlloc,omitempty"`
+	Fees         *hexutil.Big `json:"fees,omitempty"`
+	Rewards      *struct {
+		Validator *hexutil.Big `json:"validator,omitempty"`
+		System    *hexutil.Big `json:"system,omitempty"`
+		Finality  *hexutil.Big `json:"finality,omitempty"`
+	} `json:"rewards,omitempty"`
+	Burn *struct {
+		System       *hexutil.Big `json:"system,omitempty"`
+		Selfdestruct *hexutil.Big `json:"selfdestruct,omitempty"`
+	} `json:"burn,omitempty"`
+	Number uint64 `json:"blockNumber"`
+}
+
+// runParliaSupplyTracer imports a Parlia chain of n blocks, each holding a
+// transfer paying a fee of params.TxGas gwei, and returns the supply summaries
+// reported by the tracer.
+func runParliaSupplyTracer(t *testing.T, alloc types.GenesisAlloc, n int) []parliaSupplyInfo {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		config = params.ParliaTestChainConfig
+		signer = types.LatestSigner(config)
+		gspec  = &core.Genesis{Config: config, Alloc: alloc}
+
+		valKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		validator = crypto.PubkeyToAddress(valKey.PublicKey)
+		output    = filepath.ToSlash(t.TempDir())
+	)
+	alloc[sender] = types.Account{Balance: big.NewInt(params.Ether)}
+
+	// The system transactions are signed by the validator
+	engine := parliatest.NewFaker(config)
+	engine.Authorize(validator, nil, func(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+		return types.SignTx(tx, signer, valKey)
+	})
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, n, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(validator)
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(sender), common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(params.GWei), nil), signer, key)
+		b.AddTx(tx)
+	})
+
+	tracer, err := tracers.LiveDirectory.New("parliaSupply", json.RawMessage(fmt.Sprintf(`{"path":"%s"}`, output)))
+	if err != nil {
+		t.Fatalf("failed to create parlia supply tracer: %v", err)
+	}
+	options := core.DefaultConfig().WithStateScheme(rawdb.PathScheme)
+	options.VmConfig = vm.Config{Tracer: tracer}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), gspec, parliatest.NewFaker(config), options)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	chain.Stop()
+
+	file, err := os.Open(filepath.Join(output, "parlia_supply.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open output file: %v", err)
+	}
+	defer file.Close()
+
+	var infos []parliaSupplyInfo
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var info parliaSupplyInfo
+		if err := json.Unmarshal(scanner.Bytes(), &info); err != nil {
+			t.Fatalf("failed to unmarshal result: %v", err)
+		}
+		infos = append(infos, info)
+	}
+	if len(infos) != n+1 {
+		t.Fatalf("summary count mismatch: have %d, want %d", len(infos), n+1)
+	}
+	return infos
+}
+
+func TestParliaSupplyRewards(t *testing.T) {
+	// The validator contract burns a tenth of every deposit:
+	// CALL(gas, 0xdead, callvalue / 10, 0, 0, 0, 0)
+	validatorCode := common.FromHex("0x6000600060006000600a340461dead5af100")
+	infos := runParliaSupplyTracer(t, types.GenesisAlloc{
+		common.HexToAddress(systemcontracts.ValidatorContract): {Code: validatorCode, Balance: common.Big0},
+	}, 2)
+
+	if infos[0].GenesisAlloc.ToInt().Cmp(big.NewInt(params.Ether)) != 0 {
+		t.Errorf("genesis alloc mismatch: have %v", infos[0].GenesisAlloc)
+	}
+	fee := new(big.Int).SetUint64(params.TxGas * params.GWei)
+	for _, info := range infos[1:] {
+		if info.Fees.ToInt().Cmp(fee) != 0 {
+			t.Errorf("block %d: fees mismatch: have %v, want %v", info.Number, info.Fees, fee)
+		}
+		if info.Rewards == nil || info.Rewards.Validator.ToInt().Cmp(fee) != 0 || info.Rewards.System != nil {
+			t.Errorf("block %d: rewards mismatch: %+v", info.Number, info.Rewards)
+		}
+		if burn := new(big.Int).Div(fee, big.NewInt(10)); info.Burn == nil || info.Burn.System.ToInt().Cmp(burn) != 0 {
+			t.Errorf("block %d: burn mismatch: have %+v, want %v", info.Number, info.Burn, burn)
+		}
+	}
+}
+
+// Tests that the shares of the deposits moved into the system reward contract
+// are reported as system rewards, and the finality rewards it pays back to the
+// validator contract every 200 blocks as finality rewards.
+func TestParliaSupplySystemAndFinalityRewards(t *testing.T) {
+	var (
+		// The validator contract accepts the finality rewards paid by the
+		// system reward contract, burns a tenth of every deposit and moves a
+		// sixteenth of it into the system reward contract. On the
+		// distributeFinalityReward call it claims from the system reward
+		// contract:
+		//
+		//   if caller == 0x1002 { stop }
+		//   if callvalue != 0 {
+		//     CALL(gas, 0xdead, callvalue / 10, 0, 0, 0, 0)
+		//     CALL(gas, 0x1002, callvalue / 16, 0, 0, 0, 0)
+		//     stop
+		//   }
+		//   if calldata[:4] == 0x300c3567 { CALL(gas, 0x1002, 0, 0, 0, 0, 0) }
+		validatorCode = common.FromHex("0x336110021460535734156032576000600060006000600a340461dead5af1506000600060006000601034046110025af150005b60003560e01c63300c35671415605357600060006000600060006110025af1505b00")
+
+		// The system reward contract pays 1000 wei to the validator contract
+		// calling it without value:
+		//
+		//   if caller == 0x1000 && callvalue == 0 { CALL(gas, caller, 1000, 0, 0, 0, 0) }
+		systemRewardCode = common.FromHex("0x336110001415601c5734601c5760006000600060006103e8335af1505b00")
+	)
+	infos := runParliaSupplyTracer(t, types.GenesisAlloc{
+		common.HexToAddress(systemcontracts.ValidatorContract):    {Code: validatorCode, Balance: common.Big0},
+		common.HexToAddress(systemcontracts.SystemRewardContract): {Code: systemRewardCode, Balance: big.NewInt(params.Ether)},
+	}, 200)
+
+	if want := big.NewInt(2 * params.Ether); infos[0].GenesisAlloc.ToInt().Cmp(want) != 0 {
+		t.Errorf("genesis alloc mismatch: have %v, want %v", infos[0].GenesisAlloc, want)
+	}
+	var (
+		fee    = new(big.Int).SetUint64(params.TxGas * params.GWei)
+		burn   = new(big.Int).Div(fee, big.NewInt(10))
+		system = new(big.Int).Div(fee, big.NewInt(16))
+	)
+	for _, info := range infos[1:] {
+		if info.Rewards == nil || info.Rewards.Validator.ToInt().Cmp(fee) != 0 || info.Rewards.System.ToInt().Cmp(system) != 0 {
+			t.Fatalf("block %d: rewards mismatch: %+v", info.Number, info.Rewards)
+		}
+		if info.Burn == nil || info.Burn.System.ToInt().Cmp(burn) != 0 {
+			t.Fatalf("block %d: burn mismatch: have %+v, want %v", info.Number, info.Burn, burn)
+		}
+		switch {
+		case info.Number == 200 && (info.Rewards.Finality == nil || info.Rewards.Finality.ToInt().Cmp(big.NewInt(1000)) != 0):
+			t.Errorf("block %d: finality reward mismatch: have %v, want 1000", info.Number, info.Rewards.Finality)
+		case info.Number != 200 && info.Rewards.Finality != nil:
+			t.Errorf("block %d: unexpected finality reward %v", info.Number, info.Rewards.Finality)
+		}
+	}
+}
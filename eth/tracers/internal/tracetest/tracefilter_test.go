@@ -0,0 +1,98 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers/live"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestTraceFilterTracer(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.AllEthashProtocolChanges
+		gspec   = &core.Genesis{Config: &config, Alloc: types.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}}}
+		engine  = beacon.New(ethash.NewFaker())
+		signer  = types.LatestSigner(gspec.Config)
+		indexed = filepath.ToSlash(t.TempDir())
+	)
+	tracer, index, err := live.NewTraceFilterTracer(json.RawMessage(fmt.Sprintf(`{"path":"%s","history":0}`, indexed)))
+	if err != nil {
+		t.Fatalf("failed to create trace filter tracer: %v", err)
+	}
+	options := core.DefaultConfig().WithStateScheme(rawdb.PathScheme)
+	options.VmConfig = vm.Config{Tracer: tracer}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), gspec, engine, options)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	var receivers []common.Address
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 3, func(i int, b *core.BlockGen) {
+		receiver := common.BigToAddress(big.NewInt(int64(0x100 + i)))
+		receivers = append(receivers, receiver)
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(sender), receiver, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, key)
+		b.AddTx(tx)
+	})
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+
+	traces, err := index.Filter(0, 3, []common.Address{sender}, nil, nil, 0)
+	if err != nil {
+		t.Fatalf("filter failed: %v", err)
+	}
+	if len(traces) != 3 {
+		t.Fatalf("trace count mismatch: have %d, want 3", len(traces))
+	}
+	traces, err = index.Filter(0, 3, nil, []common.Address{receivers[1]}, nil, 0)
+	if err != nil {
+		t.Fatalf("filter failed: %v", err)
+	}
+	if len(traces) != 1 {
+		t.Fatalf("trace count mismatch: have %d, want 1", len(traces))
+	}
+	var trace struct {
+		BlockNumber     uint64      `json:"blockNumber"`
+		TransactionHash common.Hash `json:"transactionHash"`
+		Action          struct {
+			Value string `json:"value"`
+		} `json:"action"`
+	}
+	if err := json.Unmarshal(traces[0], &trace); err != nil {
+		t.Fatalf("failed to decode trace: %v", err)
+	}
+	if trace.BlockNumber != 2 || trace.TransactionHash != blocks[1].Transactions()[0].Hash() || trace.Action.Value != "0x3e8" {
+		t.Errorf("trace mismatch: %s", traces[0])
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func init() {
+	tracers.LiveDirectory.Register("parliaSupply", newParliaSupplyTracer)
+}
+
+var (
+	validatorContract    = common.HexToAddress(systemcontracts.ValidatorContract)
+	systemRewardContract = common.HexToAddress(systemcontracts.SystemRewardContract)
+
+	// burnAddress receives the share of the block rewards burnt by the
+	// validator contract.
+	burnAddress = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+)
+
+// parliaSupplyRewards are the block rewards moved by the Parlia system
+// transactions. The validator rewards are the gross amount deposited to the
+// validator contract, the shares it forwards to the system reward contract
+// and to the burn address are reported again as system rewards and burn.
+type parliaSupplyRewards struct {
+	Validator *hexutil.Big `json:"validator,omitempty"` // Deposited to the validator contract
+	System    *hexutil.Big `json:"system,omitempty"`    // Moved into the system reward contract
+	Finality  *hexutil.Big `json:"finality,omitempty"`  // Claimed from the system reward contract for finality votes
+}
+
+type parliaSupplyBurn struct {
+	System       *hexutil.Big `json:"system,omitempty"`       // Sent to the burn address by the system transactions
+	Selfdestruct *hexutil.Big `json:"selfdestruct,omitempty"` // Balance of contracts selfdestructing to themselves
+}
+
+// parliaSupplyInfo is the supply summary of a block.
+type parliaSupplyInfo struct {
+	GenesisAlloc *hexutil.Big         `json:"genesisAlloc,omitempty"`
+	Fees         *hexutil.Big         `json:"fees,omitempty"` // Transaction and blob fees collected by the system address
+	Rewards      *parliaSupplyRewards `json:"rewards,omitempty"`
+	Burn         *parliaSupplyBurn    `json:"burn,omitempty"`
+
+	// Block info
+	Number     uint64      `json:"blockNumber"`
+	Hash       common.Hash `json:"hash"`
+	ParentHash common.Hash `json:"parentHash"`
+}
+
+// parliaSupplyFlows accumulates the reward flows of a call frame, which are
+// only accounted if the frame and all its parents succeed.
+type parliaSupplyFlows struct {
+	validator, system, finality, burn big.Int
+}
+
+func (f *parliaSupplyFlows) add(other *parliaSupplyFlows) {
+	f.validator.Add(&f.validator, &other.validator)
+	f.system.Add(&f.system, &other.system)
+	f.finality.Add(&f.finality, &other.finality)
+	f.burn.Add(&f.burn, &other.burn)
+}
+
+type parliaSupplyTracer struct {
+	block        parliaSupplyFlows
+	fees         big.Int
+	selfdestruct big.Int
+	info         parliaSupplyInfo
+
+	systemTx bool                // Whether a system transaction is executing
+	frames   []parliaSupplyFlows // Flows of the call frames of the current system transaction
+	logger   *lumberjack.Logger
+}
+
+type parliaSupplyTracerConfig struct {
+	Path    string `json:"path"`    // Path to the directory where the tracer logs will be stored
+	MaxSize int    `json:"maxSize"` // MaxSize is the maximum size in megabytes of the tracer log file before it gets rotated. It defaults to 100 megabytes.
+}
+
+func newParliaSupplyTracer(cfg json.RawMessage) (*tracing.Hooks, error) {
+	var config parliaSupplyTracerConfig
+	if err := json.Unmarshal(cfg, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	if config.Path == "" {
+		return nil, errors.New("parlia supply tracer output path is required")
+	}
+
+	// Store traces in a rotating file
+	logger := &lumberjack.Logger{
+		Filename: filepath.Join(config.Path, "parlia_supply.jsonl"),
+	}
+	if config.MaxSize > 0 {
+		logger.MaxSize = config.MaxSize
+	}
+
+	t := &parliaSupplyTracer{logger: logger}
+	return &tracing.Hooks{
+		OnBlockStart:    t.onBlockStart,
+		OnBlockEnd:      t.onBlockEnd,
+		OnGenesisBlock:  t.onGenesisBlock,
+		OnSystemTxStart: t.onSystemTxStart,
+		OnSystemTxEnd:   t.onSystemTxEnd,
+		OnBalanceChange: t.onBalanceChange,
+		OnEnter:         t.onEnter,
+		OnExit:          t.onExit,
+		OnClose:         t.onClose,
+	}, nil
+}
+
+func (s *parliaSupplyTracer) reset(b *types.Block) {
+	s.block = parliaSupplyFlows{}
+	s.fees.SetUint64(0)
+	s.selfdestruct.SetUint64(0)
+	s.info = parliaSupplyInfo{
+		Number:     b.NumberU64(),
+		Hash:       b.Hash(),
+		ParentHash: b.ParentHash(),
+	}
+}
+
+func (s *parliaSupplyTracer) onBlockStart(ev tracing.BlockEvent) {
+	s.reset(ev.Block)
+}
+
+func (s *parliaSupplyTracer) onBlockEnd(err error) {
+	// Blocks failing to process are not part of the chain
+	if err != nil {
+		return
+	}
+	s.info.Fees = nonZero(&s.fees)
+	if rewards := (parliaSupplyRewards{nonZero(&s.block.validator), nonZero(&s.block.system), nonZero(&s.block.finality)}); rewards != (parliaSupplyRewards{}) {
+		s.info.Rewards = &rewards
+	}
+	if burn := (parliaSupplyBurn{nonZero(&s.block.burn), nonZero(&s.selfdestruct)}); burn != (parliaSupplyBurn{}) {
+		s.info.Burn = &burn
+	}
+	s.write(s.info)
+}
+
+func (s *parliaSupplyTracer) onGenesisBlock(b *types.Block, alloc types.GenesisAlloc) {
+	s.reset(b)
+
+	// Initialize supply with total allocation in genesis block
+	total := new(big.Int)
+	for _, account := range alloc {
+		total.Add(total, account.Balance)
+	}
+	s.info.GenesisAlloc = nonZero(total)
+	s.write(s.info)
+}
+
+func (s *parliaSupplyTracer) onSystemTxStart() {
+	s.systemTx = true
+	s.frames = s.frames[:0]
+}
+
+func (s *parliaSupplyTracer) onSystemTxEnd() {
+	s.systemTx = false
+}
+
+func (s *parliaSupplyTracer) onBalanceChange(a common.Address, prevBalance, newBalance *big.Int, reason tracing.BalanceChangeReason) {
+	diff := new(big.Int).Sub(newBalance, prevBalance)
+
+	switch reason {
+	case tracing.BalanceIncreaseRewardTransactionFee:
+		s.fees.Add(&s.fees, diff)
+	case tracing.BalanceDecreaseSelfdestructBurn:
+		// BalanceDecreaseSelfdestructBurn is non-reversible as it happens
+		// at the end of the transaction.
+		s.selfdestruct.Sub(&s.selfdestruct, diff)
+	}
+}
+
+func (s *parliaSupplyTracer) onEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if !s.systemTx {
+		return
+	}
+	var flows parliaSupplyFlows
+	if op := vm.OpCode(typ); value != nil && value.Sign() > 0 && op != vm.DELEGATECALL && op != vm.STATICCALL && from != to {
+		switch {
+		case to == burnAddress:
+			flows.burn.Set(value)
+		case to == systemRewardContract:
+			flows.system.Set(value)
+		case from == systemRewardContract && to == validatorContract:
+			flows.finality.Set(value)
+		case depth == 0 && to == validatorContract:
+			flows.validator.Set(value)
+		}
+	}
+	s.frames = append(s.frames, flows)
+}
+
+func (s *parliaSupplyTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if !s.systemTx || len(s.frames) == 0 {
+		return
+	}
+	frame := s.frames[len(s.frames)-1]
+	s.frames = s.frames[:len(s.frames)-1]
+
+	// Flows of reverted frames, including the ones of their subcalls, never
+	// happened.
+	if reverted {
+		return
+	}
+	if len(s.frames) == 0 {
+		s.block.add(&frame)
+		return
+	}
+	s.frames[len(s.frames)-1].add(&frame)
+}
+
+func (s *parliaSupplyTracer) onClose() {
+	if err := s.logger.Close(); err != nil {
+		log.Warn("failed to close parlia supply tracer log file", "error", err)
+	}
+}
+
+func (s *parliaSupplyTracer) write(info parliaSupplyInfo) {
+	out, _ := json.Marshal(info)
+	if _, err := s.logger.Write(append(out, '\n')); err != nil {
+		log.Warn("failed to write to parlia supply tracer log file", "error", err)
+	}
+}
+
+// nonZero returns a copy of the given amount, or nil if it is zero.
+func nonZero(amount *big.Int) *hexutil.Big {
+	if amount.Sign() == 0 {
+		return nil
+	}
+	return (*hexutil.Big)(new(big.Int).Set(amount))
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TraceFilterTracer is the name of the live tracer maintaining the index
+// serving trace_filter.
+const TraceFilterTracer = "traceFilter"
+
+func init() {
+	tracers.LiveDirectory.Register(TraceFilterTracer, func(cfg json.RawMessage) (*tracing.Hooks, error) {
+		hooks, _, err := NewTraceFilterTracer(cfg)
+		return hooks, err
+	})
+}
+
+type traceFilterTracer struct {
+	db          ethdb.KeyValueStore
+	index       *tracers.FilterIndex
+	chainConfig *params.ChainConfig
+
+	block    *types.Block
+	txIndex  int               // Index of the next transaction in the block
+	txTracer *tracers.Tracer   // Native flatCallTracer of the current transaction
+	traces   []json.RawMessage // Flat call traces of the block
+}
+
+type traceFilterTracerConfig struct {
+	Path    string  `json:"path"`    // Path to the directory where the index database will be stored
+	History *uint64 `json:"history"` // Number of recent blocks to keep indexed, 0 keeps all
+	Cache   int     `json:"cache"`   // Megabytes of memory allocated to the index database
+}
+
+// TraceFilterConfig fills the unset fields of a traceFilter tracer config with
+// the given defaults, which are derived from the node configuration.
+func TraceFilterConfig(cfg json.RawMessage, path string, history uint64) (json.RawMessage, error) {
+	var config traceFilterTracerConfig
+	if err := json.Unmarshal(cfg, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	if config.Path == "" {
+		config.Path = path
+	}
+	if config.History == nil {
+		config.History = &history
+	}
+	return json.Marshal(config)
+}
+
+// NewTraceFilterTracer creates the traceFilter live tracer, returning the index
+// it maintains along with its hooks so the index can be served to trace_filter.
+func NewTraceFilterTracer(cfg json.RawMessage) (*tracing.Hooks, *tracers.FilterIndex, error) {
+	var config traceFilterTracerConfig
+	if err := json.Unmarshal(cfg, &config); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	if config.Path == "" {
+		return nil, nil, errors.New("trace filter tracer output path is required")
+	}
+	if config.Cache <= 0 {
+		config.Cache = 16
+	}
+	db, err := pebble.New(config.Path, config.Cache, 16, "eth/db/tracefilter/", false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open trace filter index: %v", err)
+	}
+	var history uint64
+	if config.History != nil {
+		history = *config.History
+	}
+	t := &traceFilterTracer{
+		db:    db,
+		index: tracers.NewFilterIndex(db, history),
+	}
+	log.Info("Enabled trace filter index", "path", config.Path, "history", history)
+	return &tracing.Hooks{
+		OnBlockchainInit: t.onBlockchainInit,
+		OnBlockStart:     t.onBlockStart,
+		OnBlockEnd:       t.onBlockEnd,
+		OnTxStart:        t.onTxStart,
+		OnTxEnd:          t.onTxEnd,
+		OnEnter:          t.onEnter,
+		OnExit:           t.onExit,
+		OnClose:          t.onClose,
+	}, t.index, nil
+}
+
+func (t *traceFilterTracer) onBlockchainInit(chainConfig *params.ChainConfig) {
+	t.chainConfig = chainConfig
+}
+
+func (t *traceFilterTracer) onBlockStart(ev tracing.BlockEvent) {
+	t.block = ev.Block
+	t.txIndex = 0
+	t.traces = t.traces[:0]
+}
+
+func (t *traceFilterTracer) onBlockEnd(err error) {
+	// Blocks failing to process are not part of the chain
+	if err != nil || t.block == nil {
+		return
+	}
+	if err := t.index.WriteBlock(t.block.NumberU64(), t.traces); err != nil {
+		log.Warn("failed to index block traces", "number", t.block.NumberU64(), "err", err)
+	}
+	t.block = nil
+}
+
+func (t *traceFilterTracer) onTxStart(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	if t.block == nil {
+		return
+	}
+	ctx := &tracers.Context{
+		BlockHash:   t.block.Hash(),
+		BlockNumber: t.block.Number(),
+		TxIndex:     t.txIndex,
+		TxHash:      tx.Hash(),
+	}
+	txTracer, err := tracers.DefaultDirectory.New("flatCallTracer", ctx, json.RawMessage(`{"convertParityErrors":true}`), t.chainConfig)
+	if err != nil {
+		log.Warn("failed to create flat call tracer", "err", err)
+		return
+	}
+	t.txTracer = txTracer
+	t.txTracer.OnTxStart(vm, tx, from)
+}
+
+func (t *traceFilterTracer) onTxEnd(receipt *types.Receipt, err error) {
+	if t.txTracer == nil {
+		return
+	}
+	defer func() { t.txTracer = nil }()
+
+	// Transactions failing validation are not included in the block
+	if err != nil {
+		return
+	}
+	t.txIndex++
+	t.txTracer.OnTxEnd(receipt, err)
+
+	res, err := t.txTracer.GetResult()
+	if err != nil {
+		log.Warn("failed to collect flat call traces", "tx", receipt.TxHash, "err", err)
+		return
+	}
+	var traces []json.RawMessage
+	if err := json.Unmarshal(res, &traces); err != nil {
+		log.Warn("failed to decode flat call traces", "tx", receipt.TxHash, "err", err)
+		return
+	}
+	t.traces = append(t.traces, traces...)
+}
+
+func (t *traceFilterTracer) onEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.txTracer != nil {
+		t.txTracer.OnEnter(depth, typ, from, to, input, gas, value)
+	}
+}
+
+func (t *traceFilterTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.txTracer != nil {
+		t.txTracer.OnExit(depth, output, gasUsed, err, reverted)
+	}
+}
+
+func (t *traceFilterTracer) onClose() {
+	if err := t.db.Close(); err != nil {
+		log.Warn("failed to close trace filter index", "err", err)
+	}
+}
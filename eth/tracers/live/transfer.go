@@ -0,0 +1,209 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	// Force-load the native tracers, the transfers of a transaction are
+	// collected by the transferTracer
+	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
+)
+
+func init() {
+	tracers.LiveDirectory.Register("transfer", newTransferTracer)
+}
+
+// transferTx holds the asset movements of a transaction, as reported by the
+// native transferTracer.
+type transferTx struct {
+	Hash      common.Hash     `json:"txHash"`
+	Index     int             `json:"txIndex"`
+	Transfers json.RawMessage `json:"transfers"`
+}
+
+// transferReward is a block reward moved to the validator by Parlia outside of
+// any transaction.
+type transferReward struct {
+	Kind  string         `json:"kind"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value *hexutil.Big   `json:"value"`
+}
+
+// transferBlock is the index entry of a block.
+type transferBlock struct {
+	Number       uint64           `json:"blockNumber"`
+	Hash         common.Hash      `json:"hash"`
+	Transactions []transferTx     `json:"transactions,omitempty"`
+	Rewards      []transferReward `json:"rewards,omitempty"`
+}
+
+type transferTracer struct {
+	block       transferBlock
+	txIndex     int // Index of the next transaction in the block
+	tx          *types.Transaction
+	txTracer    *tracers.Tracer // Native transferTracer of the current transaction
+	logger      *lumberjack.Logger
+	chainConfig *params.ChainConfig
+}
+
+type transferTracerConfig struct {
+	Path    string `json:"path"`    // Path to the directory where the transfer index will be stored
+	MaxSize int    `json:"maxSize"` // MaxSize is the maximum size in megabytes of the index file before it gets rotated. It defaults to 100 megabytes.
+}
+
+func newTransferTracer(cfg json.RawMessage) (*tracing.Hooks, error) {
+	var config transferTracerConfig
+	if err := json.Unmarshal(cfg, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	if config.Path == "" {
+		return nil, errors.New("transfer tracer output path is required")
+	}
+
+	// Store the index in a rotating file
+	logger := &lumberjack.Logger{
+		Filename: filepath.Join(config.Path, "transfers.jsonl"),
+	}
+	if config.MaxSize > 0 {
+		logger.MaxSize = config.MaxSize
+	}
+
+	t := &transferTracer{logger: logger}
+	return &tracing.Hooks{
+		OnBlockchainInit: t.onBlockchainInit,
+		OnBlockStart:     t.onBlockStart,
+		OnBlockEnd:       t.onBlockEnd,
+		OnTxStart:        t.onTxStart,
+		OnTxEnd:          t.onTxEnd,
+		OnEnter:          t.onEnter,
+		OnExit:           t.onExit,
+		OnLog:            t.onLog,
+		OnBalanceChange:  t.onBalanceChange,
+		OnClose:          t.onClose,
+	}, nil
+}
+
+func (t *transferTracer) onBlockchainInit(chainConfig *params.ChainConfig) {
+	t.chainConfig = chainConfig
+}
+
+func (t *transferTracer) onBlockStart(ev tracing.BlockEvent) {
+	t.block = transferBlock{
+		Number: ev.Block.NumberU64(),
+		Hash:   ev.Block.Hash(),
+	}
+	t.txIndex = 0
+}
+
+func (t *transferTracer) onBlockEnd(err error) {
+	// Blocks failing to process are not part of the chain
+	if err != nil {
+		return
+	}
+	out, _ := json.Marshal(t.block)
+	if _, err := t.logger.Write(append(out, '\n')); err != nil {
+		log.Warn("failed to write to transfer tracer log file", "error", err)
+	}
+}
+
+func (t *transferTracer) onTxStart(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	txTracer, err := tracers.DefaultDirectory.New("transferTracer", &tracers.Context{}, nil, t.chainConfig)
+	if err != nil {
+		log.Warn("failed to create transfer tracer", "error", err)
+		return
+	}
+	t.tx, t.txTracer = tx, txTracer
+	t.txTracer.OnTxStart(vm, tx, from)
+}
+
+func (t *transferTracer) onTxEnd(receipt *types.Receipt, err error) {
+	if t.txTracer == nil {
+		return
+	}
+	defer func() { t.tx, t.txTracer = nil, nil }()
+
+	// Transactions failing validation are not included in the block
+	if err != nil {
+		return
+	}
+	index := t.txIndex
+	t.txIndex++
+
+	res, err := t.txTracer.GetResult()
+	if err != nil {
+		log.Warn("failed to collect transfers", "tx", t.tx.Hash(), "error", err)
+		return
+	}
+	if string(res) == "[]" {
+		return
+	}
+	t.block.Transactions = append(t.block.Transactions, transferTx{Hash: t.tx.Hash(), Index: index, Transfers: res})
+}
+
+func (t *transferTracer) onEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.txTracer != nil {
+		t.txTracer.OnEnter(depth, typ, from, to, input, gas, value)
+	}
+}
+
+func (t *transferTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.txTracer != nil {
+		t.txTracer.OnExit(depth, output, gasUsed, err, reverted)
+	}
+}
+
+func (t *transferTracer) onLog(l *types.Log) {
+	if t.txTracer != nil {
+		t.txTracer.OnLog(l)
+	}
+}
+
+func (t *transferTracer) onBalanceChange(a common.Address, prevBalance, newBalance *big.Int, reason tracing.BalanceChangeReason) {
+	// The fees collected by the system address are handed to the validator
+	// before the system transactions distribute them further.
+	if reason != tracing.BalanceIncreaseBSCDistributeReward {
+		return
+	}
+	t.block.Rewards = append(t.block.Rewards, transferReward{
+		Kind:  "reward",
+		From:  consensus.SystemAddress,
+		To:    a,
+		Value: (*hexutil.Big)(new(big.Int).Sub(newBalance, prevBalance)),
+	})
+}
+
+func (t *transferTracer) onClose() {
+	if err := t.logger.Close(); err != nil {
+		log.Warn("failed to close transfer tracer log file", "error", err)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("transferTracer", newTransferTracer, false)
+}
+
+// Kinds of asset movements reported by the transferTracer.
+const (
+	transferNative       = "native"       // Value moved by a call or contract creation
+	transferSelfdestruct = "selfdestruct" // Balance moved to the beneficiary of a SELFDESTRUCT
+	transferReward       = "reward"       // Value moved by a Parlia system transaction
+	transferERC20        = "erc20"
+	transferERC721       = "erc721"
+	transferERC1155      = "erc1155"
+)
+
+var (
+	// erc20TransferTopic is the topic of Transfer(address,address,uint256), shared
+	// by ERC-20 and ERC-721. The two are told apart by the indexed token id.
+	erc20TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	// erc1155SingleTopic is the topic of TransferSingle(address,address,address,uint256,uint256).
+	erc1155SingleTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+
+	// erc1155BatchTopic is the topic of TransferBatch(address,address,address,uint256[],uint256[]).
+	erc1155BatchTopic = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+
+	// erc1155BatchArgs are the non-indexed arguments of TransferBatch.
+	erc1155BatchArgs = func() abi.Arguments {
+		typ, _ := abi.NewType("uint256[]", "", nil)
+		return abi.Arguments{{Type: typ}, {Type: typ}}
+	}()
+
+	// bscSystemContracts are the receivers of Parlia system transactions.
+	bscSystemContracts = map[common.Address]bool{
+		common.HexToAddress(systemcontracts.ValidatorContract):          true,
+		common.HexToAddress(systemcontracts.SlashContract):              true,
+		common.HexToAddress(systemcontracts.SystemRewardContract):       true,
+		common.HexToAddress(systemcontracts.LightClientContract):        true,
+		common.HexToAddress(systemcontracts.RelayerHubContract):         true,
+		common.HexToAddress(systemcontracts.GovHubContract):             true,
+		common.HexToAddress(systemcontracts.TokenHubContract):           true,
+		common.HexToAddress(systemcontracts.RelayerIncentivizeContract): true,
+		common.HexToAddress(systemcontracts.CrossChainContract):         true,
+		common.HexToAddress(systemcontracts.StakeHubContract):           true,
+		common.HexToAddress(systemcontracts.GovernorContract):           true,
+		common.HexToAddress(systemcontracts.GovTokenContract):           true,
+		common.HexToAddress(systemcontracts.TimelockContract):           true,
+		common.HexToAddress(systemcontracts.TokenRecoverPortalContract): true,
+	}
+)
+
+// transfer is a single asset movement. Token is unset for BNB movements,
+// TokenID is only set for ERC-721 and ERC-1155 tokens and Value is unset
+// for ERC-721 tokens.
+type transfer struct {
+	Kind    string          `json:"kind"`
+	Token   *common.Address `json:"token,omitempty"`
+	From    common.Address  `json:"from"`
+	To      common.Address  `json:"to"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	TokenID *hexutil.Big    `json:"tokenId,omitempty"`
+}
+
+// transferTracer collects the asset movements of a transaction: BNB moved by
+// calls, contract creations and selfdestructs, and ERC-20, ERC-721 and ERC-1155
+// tokens moved according to their Transfer events. Movements of reverted call
+// frames are discarded. BNB moved by the Parlia system transactions, i.e. the
+// block reward distribution, is reported as kind "reward".
+//
+// Example:
+//
+//	> debug.traceTransaction("0x...", {tracer: "transferTracer"})
+//	[
+//	  {kind: "native", from: "0x...", to: "0x...", value: "0xde0b6b3a7640000"},
+//	  {kind: "erc20", token: "0x...", from: "0x...", to: "0x...", value: "0x64"},
+//	  {kind: "erc721", token: "0x...", from: "0x...", to: "0x...", tokenId: "0x1"}
+//	]
+type transferTracer struct {
+	frames    [][]transfer // Movements of the call frames currently executing
+	transfers []transfer   // Movements of the finished transaction
+	systemTx  bool         // Whether the current transaction is a system transaction
+	interrupt atomic.Bool  // Atomic flag to signal execution interruption
+	reason    error        // Textual reason for the interruption
+}
+
+// newTransferTracer returns a native go tracer which collects the asset
+// movements of a transaction.
+func newTransferTracer(ctx *tracers.Context, cfg json.RawMessage, chainConfig *params.ChainConfig) (*tracers.Tracer, error) {
+	t := &transferTracer{transfers: []transfer{}}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: t.OnTxStart,
+			OnEnter:   t.OnEnter,
+			OnExit:    t.OnExit,
+			OnLog:     t.OnLog,
+		},
+		GetResult: t.GetResult,
+		Stop:      t.Stop,
+	}, nil
+}
+
+func (t *transferTracer) OnTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	t.frames = t.frames[:0]
+	t.transfers = []transfer{}
+	t.systemTx = from == env.Coinbase && tx.To() != nil && bscSystemContracts[*tx.To()] && tx.GasPrice().Sign() == 0
+}
+
+// OnEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *transferTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.interrupt.Load() {
+		return
+	}
+	var frame []transfer
+	if value != nil && value.Sign() > 0 {
+		kind := transferNative
+		switch op := vm.OpCode(typ); {
+		case op == vm.DELEGATECALL || op == vm.STATICCALL:
+			kind = ""
+		case op == vm.CALLCODE:
+			// CALLCODE runs the callee code on the caller, the value is
+			// sent to the caller itself and no balance moves
+			kind = ""
+		case op == vm.SELFDESTRUCT:
+			kind = transferSelfdestruct
+		case t.systemTx:
+			kind = transferReward
+		}
+		if kind != "" {
+			frame = append(frame, transfer{Kind: kind, From: from, To: to, Value: (*hexutil.Big)(new(big.Int).Set(value))})
+		}
+	}
+	t.frames = append(t.frames, frame)
+}
+
+// OnExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *transferTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.interrupt.Load() || len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+
+	// Movements of reverted scopes, including the ones of their subcalls,
+	// never happened.
+	if reverted {
+		return
+	}
+	if len(t.frames) == 0 {
+		t.transfers = append(t.transfers, frame...)
+		return
+	}
+	t.frames[len(t.frames)-1] = append(t.frames[len(t.frames)-1], frame...)
+}
+
+// OnLog decodes the token transfer events emitted by the current scope.
+func (t *transferTracer) OnLog(log *types.Log) {
+	if t.interrupt.Load() || len(t.frames) == 0 || len(log.Topics) == 0 {
+		return
+	}
+	var (
+		token = log.Address
+		top   = &t.frames[len(t.frames)-1]
+	)
+	switch log.Topics[0] {
+	case erc20TransferTopic:
+		if len(log.Topics) < 3 {
+			return
+		}
+		from, to := topicAddress(log, 1), topicAddress(log, 2)
+		switch {
+		case len(log.Topics) == 3 && len(log.Data) == 32:
+			*top = append(*top, transfer{Kind: transferERC20, Token: &token, From: from, To: to, Value: wordBig(log.Data)})
+		case len(log.Topics) == 4 && len(log.Data) == 0:
+			*top = append(*top, transfer{Kind: transferERC721, Token: &token, From: from, To: to, TokenID: wordBig(log.Topics[3].Bytes())})
+		}
+	case erc1155SingleTopic:
+		if len(log.Topics) != 4 || len(log.Data) != 64 {
+			return
+		}
+		from, to := topicAddress(log, 2), topicAddress(log, 3)
+		*top = append(*top, transfer{Kind: transferERC1155, Token: &token, From: from, To: to, TokenID: wordBig(log.Data[:32]), Value: wordBig(log.Data[32:])})
+	case erc1155BatchTopic:
+		if len(log.Topics) != 4 {
+			return
+		}
+		values, err := erc1155BatchArgs.Unpack(log.Data)
+		if err != nil {
+			return
+		}
+		ids, amounts := values[0].([]*big.Int), values[1].([]*big.Int)
+		if len(ids) != len(amounts) {
+			return
+		}
+		from, to := topicAddress(log, 2), topicAddress(log, 3)
+		for i := range ids {
+			*top = append(*top, transfer{Kind: transferERC1155, Token: &token, From: from, To: to, TokenID: (*hexutil.Big)(ids[i]), Value: (*hexutil.Big)(amounts[i])})
+		}
+	}
+}
+
+// GetResult returns the json-encoded list of asset movements, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *transferTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.transfers)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *transferTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
+
+// topicAddress returns the address held by the given indexed event argument.
+func topicAddress(log *types.Log, index int) common.Address {
+	return common.BytesToAddress(log.Topics[index].Bytes())
+}
+
+// wordBig interprets a 32 byte abi word as an unsigned integer.
+func wordBig(word []byte) *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).SetBytes(word))
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+type testTransfer struct {
+	Kind    string          `json:"kind"`
+	Token   *common.Address `json:"token,omitempty"`
+	From    common.Address  `json:"from"`
+	To      common.Address  `json:"to"`
+	Value   string          `json:"value,omitempty"`
+	TokenID string          `json:"tokenId,omitempty"`
+}
+
+func addressTopic(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
+
+func word(v int64) []byte {
+	return common.LeftPadBytes(big.NewInt(v).Bytes(), 32)
+}
+
+func runTransferTracer(t *testing.T, coinbase common.Address, tx *types.Transaction, from common.Address, run func(hooks *tracing.Hooks)) []testTransfer {
+	tracer, err := tracers.DefaultDirectory.New("transferTracer", &tracers.Context{}, nil, params.MainnetChainConfig)
+	require.NoError(t, err)
+
+	tracer.OnTxStart(&tracing.VMContext{Coinbase: coinbase, BlockNumber: big.NewInt(1)}, tx, from)
+	run(tracer.Hooks)
+
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+	var transfers []testTransfer
+	require.NoError(t, json.Unmarshal(res, &transfers))
+	return transfers
+}
+
+func TestTransferTracer(t *testing.T) {
+	var (
+		alice = common.HexToAddress("0xa1")
+		bob   = common.HexToAddress("0xb0b")
+		carol = common.HexToAddress("0xca401")
+		token = common.HexToAddress("0x70e")
+		nft   = common.HexToAddress("0x7f7")
+		multi = common.HexToAddress("0x1155")
+
+		transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+		singleTopic   = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+		batchTopic    = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+	)
+	uint256Array, _ := abi.NewType("uint256[]", "", nil)
+	batchData, err := abi.Arguments{{Type: uint256Array}, {Type: uint256Array}}.Pack(
+		[]*big.Int{big.NewInt(7), big.NewInt(8)}, []*big.Int{big.NewInt(70), big.NewInt(80)})
+	require.NoError(t, err)
+
+	tx := types.NewTransaction(0, token, big.NewInt(5), 100000, big.NewInt(1), nil)
+	transfers := runTransferTracer(t, common.Address{}, tx, alice, func(hooks *tracing.Hooks) {
+		hooks.OnEnter(0, byte(vm.CALL), alice, token, nil, 100000, big.NewInt(5))
+		hooks.OnLog(&types.Log{Address: token, Topics: []common.Hash{transferTopic, addressTopic(alice), addressTopic(bob)}, Data: word(100)})
+
+		// A reverted subcall moving value and emitting events is discarded
+		hooks.OnEnter(1, byte(vm.CALL), token, carol, nil, 50000, big.NewInt(1))
+		hooks.OnLog(&types.Log{Address: token, Topics: []common.Hash{transferTopic, addressTopic(bob), addressTopic(carol)}, Data: word(1)})
+		hooks.OnExit(1, nil, 0, vm.ErrExecutionReverted, true)
+
+		// Calls not moving value are skipped
+		hooks.OnEnter(1, byte(vm.DELEGATECALL), token, nft, nil, 50000, big.NewInt(5))
+		hooks.OnLog(&types.Log{Address: nft, Topics: []common.Hash{transferTopic, addressTopic(alice), addressTopic(carol), common.BigToHash(big.NewInt(42))}})
+		hooks.OnExit(1, nil, 0, nil, false)
+		hooks.OnEnter(1, byte(vm.CALLCODE), token, nft, nil, 50000, big.NewInt(5))
+		hooks.OnExit(1, nil, 0, nil, false)
+
+		hooks.OnEnter(1, byte(vm.CALL), token, multi, nil, 50000, nil)
+		hooks.OnLog(&types.Log{Address: multi, Topics: []common.Hash{singleTopic, addressTopic(token), addressTopic(alice), addressTopic(bob)}, Data: append(word(3), word(30)...)})
+		hooks.OnLog(&types.Log{Address: multi, Topics: []common.Hash{batchTopic, addressTopic(token), addressTopic(bob), addressTopic(carol)}, Data: batchData})
+		hooks.OnEnter(2, byte(vm.SELFDESTRUCT), multi, carol, nil, 0, big.NewInt(9))
+		hooks.OnExit(2, nil, 0, nil, false)
+		hooks.OnExit(1, nil, 0, nil, false)
+
+		// Malformed events are ignored
+		hooks.OnLog(&types.Log{Address: token, Topics: []common.Hash{transferTopic}})
+		hooks.OnExit(0, nil, 0, nil, false)
+	})
+	require.Equal(t, []testTransfer{
+		{Kind: "native", From: alice, To: token, Value: "0x5"},
+		{Kind: "erc20", Token: &token, From: alice, To: bob, Value: "0x64"},
+		{Kind: "erc721", Token: &nft, From: alice, To: carol, TokenID: "0x2a"},
+		{Kind: "erc1155", Token: &multi, From: alice, To: bob, TokenID: "0x3", Value: "0x1e"},
+		{Kind: "erc1155", Token: &multi, From: bob, To: carol, TokenID: "0x7", Value: "0x46"},
+		{Kind: "erc1155", Token: &multi, From: bob, To: carol, TokenID: "0x8", Value: "0x50"},
+		{Kind: "selfdestruct", From: multi, To: carol, Value: "0x9"},
+	}, transfers)
+}
+
+func TestTransferTracerReverted(t *testing.T) {
+	var (
+		alice = common.HexToAddress("0xa1")
+		bob   = common.HexToAddress("0xb0b")
+	)
+	tx := types.NewTransaction(0, bob, big.NewInt(5), 21000, big.NewInt(1), nil)
+	transfers := runTransferTracer(t, common.Address{}, tx, alice, func(hooks *tracing.Hooks) {
+		hooks.OnEnter(0, byte(vm.CALL), alice, bob, nil, 21000, big.NewInt(5))
+		hooks.OnExit(0, nil, 0, vm.ErrExecutionReverted, true)
+	})
+	require.Empty(t, transfers)
+}
+
+func TestTransferTracerSystemTx(t *testing.T) {
+	var (
+		validator = common.HexToAddress("0xc0ffee")
+		contract  = common.HexToAddress(systemcontracts.ValidatorContract)
+		burn      = common.HexToAddress("0xdead")
+	)
+	tx := types.NewTransaction(0, contract, big.NewInt(10), 100000, common.Big0, nil)
+	transfers := runTransferTracer(t, validator, tx, validator, func(hooks *tracing.Hooks) {
+		hooks.OnEnter(0, byte(vm.CALL), validator, contract, nil, 100000, big.NewInt(10))
+		hooks.OnEnter(1, byte(vm.CALL), contract, burn, nil, 50000, big.NewInt(1))
+		hooks.OnExit(1, nil, 0, nil, false)
+		hooks.OnExit(0, nil, 0, nil, false)
+	})
+	require.Equal(t, []testTransfer{
+		{Kind: "reward", From: validator, To: contract, Value: "0xa"},
+		{Kind: "reward", From: contract, To: burn, Value: "0x1"},
+	}, transfers)
+}
@@ -30,9 +30,12 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	buildertypes "github.com/ethereum/go-ethereum/core/types/builder"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -208,6 +211,97 @@ func (w *Withdrawal) Amount(ctx context.Context) hexutil.Uint64 {
 	return hexutil.Uint64(w.amount)
 }
 
+// BlobSidecar represents the blobs, commitments and proofs of a single blob
+// transaction in a block.
+type BlobSidecar struct {
+	sidecar *types.BlobSidecar
+}
+
+func (s *BlobSidecar) TransactionHash(ctx context.Context) common.Hash {
+	return s.sidecar.TxHash
+}
+
+func (s *BlobSidecar) TransactionIndex(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(s.sidecar.TxIndex)
+}
+
+func (s *BlobSidecar) Blobs(ctx context.Context) []hexutil.Bytes {
+	ret := make([]hexutil.Bytes, 0, len(s.sidecar.Blobs))
+	for i := range s.sidecar.Blobs {
+		ret = append(ret, s.sidecar.Blobs[i][:])
+	}
+	return ret
+}
+
+func (s *BlobSidecar) Commitments(ctx context.Context) []hexutil.Bytes {
+	ret := make([]hexutil.Bytes, 0, len(s.sidecar.Commitments))
+	for i := range s.sidecar.Commitments {
+		ret = append(ret, s.sidecar.Commitments[i][:])
+	}
+	return ret
+}
+
+func (s *BlobSidecar) Proofs(ctx context.Context) []hexutil.Bytes {
+	ret := make([]hexutil.Bytes, 0, len(s.sidecar.Proofs))
+	for i := range s.sidecar.Proofs {
+		ret = append(ret, s.sidecar.Proofs[i][:])
+	}
+	return ret
+}
+
+// VoteAttestation represents the aggregated fast finality votes carried in a
+// Parlia block header.
+type VoteAttestation struct {
+	attestation *types.VoteAttestation
+	voters      []common.Address
+}
+
+func (v *VoteAttestation) VoteAddressSet(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.attestation.VoteAddressSet)
+}
+
+func (v *VoteAttestation) AggSignature(ctx context.Context) hexutil.Bytes {
+	return v.attestation.AggSignature[:]
+}
+
+func (v *VoteAttestation) SourceNumber(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.attestation.Data.SourceNumber)
+}
+
+func (v *VoteAttestation) SourceHash(ctx context.Context) common.Hash {
+	return v.attestation.Data.SourceHash
+}
+
+func (v *VoteAttestation) TargetNumber(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.attestation.Data.TargetNumber)
+}
+
+func (v *VoteAttestation) TargetHash(ctx context.Context) common.Hash {
+	return v.attestation.Data.TargetHash
+}
+
+func (v *VoteAttestation) Voters(ctx context.Context) []common.Address {
+	return v.voters
+}
+
+func (v *VoteAttestation) Extra(ctx context.Context) hexutil.Bytes {
+	return v.attestation.Extra
+}
+
+// BlockMevInfo represents the builder information encoded into a block.
+type BlockMevInfo struct {
+	version *string
+	builder *common.Address
+}
+
+func (m *BlockMevInfo) Version(ctx context.Context) *string {
+	return m.version
+}
+
+func (m *BlockMevInfo) Builder(ctx context.Context) *common.Address {
+	return m.builder
+}
+
 // Transaction represents an Ethereum transaction.
 // backend and hash are mandatory; all others will be fetched when required.
 type Transaction struct {
@@ -358,6 +452,23 @@ func (t *Transaction) BlobVersionedHashes(ctx context.Context) *[]common.Hash {
 	return &blobHashes
 }
 
+func (t *Transaction) SystemTx(ctx context.Context) (bool, error) {
+	tx, block := t.resolve(ctx)
+	// Pending tx
+	if tx == nil || block == nil {
+		return false, nil
+	}
+	posa, ok := t.r.backend.Engine().(consensus.PoSA)
+	if !ok {
+		return false, nil
+	}
+	header, err := block.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return false, err
+	}
+	return posa.IsSystemTransaction(tx, header)
+}
+
 func (t *Transaction) EffectiveTip(ctx context.Context) (*hexutil.Big, error) {
 	tx, block := t.resolve(ctx)
 	if tx == nil {
@@ -1099,6 +1210,141 @@ func (b *Block) ExcessBlobGas(ctx context.Context) (*hexutil.Uint64, error) {
 	return &ret, nil
 }
 
+func (b *Block) Sidecars(ctx context.Context) (*[]*BlobSidecar, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	// Pre-cancun blocks
+	if header.BlobGasUsed == nil {
+		return nil, nil
+	}
+	sidecars, err := b.r.backend.GetBlobSidecars(ctx, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*BlobSidecar, 0, len(sidecars))
+	for _, sidecar := range sidecars {
+		ret = append(ret, &BlobSidecar{sidecar: sidecar})
+	}
+	return &ret, nil
+}
+
+// parlia returns the Parlia consensus engine of the backend, or nil if the
+// chain runs a different engine.
+func (b *Block) parlia() *parlia.Parlia {
+	engine, _ := b.r.backend.Engine().(*parlia.Parlia)
+	return engine
+}
+
+func (b *Block) VoteAttestation(ctx context.Context) (*VoteAttestation, error) {
+	engine := b.parlia()
+	if engine == nil {
+		return nil, nil
+	}
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	attestation, voters, err := engine.GetVoteAttestation(b.r.backend.Chain(), header)
+	if err != nil || attestation == nil {
+		return nil, err
+	}
+	return &VoteAttestation{attestation: attestation, voters: voters}, nil
+}
+
+func (b *Block) Validators(ctx context.Context) (*[]common.Address, error) {
+	engine := b.parlia()
+	if engine == nil {
+		return nil, nil
+	}
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	validators, _, err := engine.GetValidatorSet(b.r.backend.Chain(), header)
+	if err != nil {
+		return nil, err
+	}
+	return &validators, nil
+}
+
+func (b *Block) TurnLength(ctx context.Context) (*hexutil.Uint64, error) {
+	engine := b.parlia()
+	if engine == nil {
+		return nil, nil
+	}
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	_, turnLength, err := engine.GetValidatorSet(b.r.backend.Chain(), header)
+	if err != nil {
+		return nil, err
+	}
+	ret := hexutil.Uint64(turnLength)
+	return &ret, nil
+}
+
+func (b *Block) Justified(ctx context.Context) (*Block, error) {
+	engine := b.parlia()
+	if engine == nil {
+		return nil, nil
+	}
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	number, hash, err := engine.GetJustifiedNumberAndHash(b.r.backend.Chain(), []*types.Header{header})
+	if err != nil {
+		return nil, err
+	}
+	num := rpc.BlockNumber(number)
+	return &Block{
+		r:            b.r,
+		numberOrHash: &rpc.BlockNumberOrHash{BlockNumber: &num, BlockHash: &hash},
+		hash:         hash,
+	}, nil
+}
+
+func (b *Block) Finalized(ctx context.Context) (*Block, error) {
+	engine := b.parlia()
+	if engine == nil {
+		return nil, nil
+	}
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	finalized := engine.GetFinalizedHeader(b.r.backend.Chain(), header)
+	if finalized == nil {
+		return nil, nil
+	}
+	var (
+		num  = rpc.BlockNumber(finalized.Number.Uint64())
+		hash = finalized.Hash()
+	)
+	return &Block{
+		r:            b.r,
+		numberOrHash: &rpc.BlockNumberOrHash{BlockNumber: &num, BlockHash: &hash},
+		header:       finalized,
+		hash:         hash,
+	}, nil
+}
+
+func (b *Block) MevInfo(ctx context.Context) (*BlockMevInfo, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	info := new(BlockMevInfo)
+	if version, builder, ok := buildertypes.HeaderMevInfo(header); ok {
+		name := version.String()
+		info.version, info.builder = &name, &builder
+	}
+	return info, nil
+}
+
 // BlockFilterCriteria encapsulates criteria passed to a `logs` accessor inside
 // a block.
 type BlockFilterCriteria struct {
@@ -1296,12 +1542,19 @@ type Resolver struct {
 func (r *Resolver) Block(ctx context.Context, args struct {
 	Number *Long
 	Hash   *common.Hash
+	Tag    *string
 }) (*Block, error) {
-	if args.Number != nil && args.Hash != nil {
-		return nil, errors.New("only one of number or hash must be specified")
+	if (args.Number != nil && args.Hash != nil) || (args.Tag != nil && (args.Number != nil || args.Hash != nil)) {
+		return nil, errors.New("only one of number, hash or tag must be specified")
 	}
 	var numberOrHash rpc.BlockNumberOrHash
-	if args.Number != nil {
+	if args.Tag != nil {
+		number, err := parseBlockTag(*args.Tag)
+		if err != nil {
+			return nil, err
+		}
+		numberOrHash = rpc.BlockNumberOrHashWithNumber(number)
+	} else if args.Number != nil {
 		if *args.Number < 0 {
 			return nil, nil
 		}
@@ -1401,6 +1654,8 @@ func (r *Resolver) SendRawTransaction(ctx context.Context, args struct{ Data hex
 type FilterCriteria struct {
 	FromBlock *Long             // beginning of the queried range, nil means genesis block
 	ToBlock   *Long             // end of the range, nil means latest block
+	FromTag   *string           // block tag alternative to FromBlock
+	ToTag     *string           // block tag alternative to ToBlock
 	Addresses *[]common.Address // restricts matches to events created by specific contracts
 
 	// The Topic list restricts matches to particular event topics. Each event has a list
@@ -1427,6 +1682,26 @@ func (r *Resolver) Logs(ctx context.Context, args struct{ Filter FilterCriteria
 	if args.Filter.ToBlock != nil {
 		end = int64(*args.Filter.ToBlock)
 	}
+	if args.Filter.FromTag != nil {
+		if args.Filter.FromBlock != nil {
+			return nil, errors.New("only one of fromBlock or fromTag must be specified")
+		}
+		number, err := parseBlockTag(*args.Filter.FromTag)
+		if err != nil {
+			return nil, err
+		}
+		begin = number.Int64()
+	}
+	if args.Filter.ToTag != nil {
+		if args.Filter.ToBlock != nil {
+			return nil, errors.New("only one of toBlock or toTag must be specified")
+		}
+		number, err := parseBlockTag(*args.Filter.ToTag)
+		if err != nil {
+			return nil, err
+		}
+		end = number.Int64()
+	}
 	if begin > 0 && end > 0 && begin > end {
 		return nil, errInvalidBlockRange
 	}
@@ -1555,3 +1830,16 @@ func (r *Resolver) Syncing(ctx context.Context) (*SyncState, error) {
 	// Otherwise gather the block sync stats
 	return &SyncState{progress}, nil
 }
+
+// parseBlockTag converts a block tag into its block number representation. The
+// pending block is served separately via the pending query.
+func parseBlockTag(tag string) (rpc.BlockNumber, error) {
+	var number rpc.BlockNumber
+	if err := number.UnmarshalJSON([]byte(tag)); err != nil {
+		return 0, fmt.Errorf("invalid block tag %q: %v", tag, err)
+	}
+	if number == rpc.PendingBlockNumber {
+		return 0, errors.New("pending block not supported, use the pending query")
+	}
+	return number, nil
+}
@@ -431,6 +431,111 @@ func TestWithdrawals(t *testing.T) {
 	}
 }
 
+// Tests that the BSC specific fields resolve gracefully on a non-Parlia chain.
+func TestBSCFields(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+
+		genesis = &core.Genesis{
+			Config:     params.AllEthashProtocolChanges,
+			GasLimit:   11500000,
+			Difficulty: common.Big1,
+			Alloc: types.GenesisAlloc{
+				addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		signer = types.LatestSigner(genesis.Config)
+		stack  = createNode(t)
+	)
+	defer stack.Close()
+
+	handler, _ := newGQLService(t, stack, true, genesis, 1, func(i int, gen *core.BlockGen) {
+		tx, _ := types.SignNewTx(key, signer, &types.LegacyTx{To: &common.Address{}, Gas: 100000, GasPrice: big.NewInt(params.InitialBaseFee)})
+		gen.AddTx(tx)
+	})
+	// start node
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+
+	for i, tt := range []struct {
+		body string
+		want string
+	}{
+		{
+			body: "{block(number: 1) { voteAttestation { voters } validators turnLength justified { number } finalized { number } sidecars { transactionIndex } } }",
+			want: `{"block":{"voteAttestation":null,"validators":null,"turnLength":null,"justified":null,"finalized":null,"sidecars":null}}`,
+		},
+		{
+			body: "{block(number: 1) { mevInfo { version builder } transactions { systemTx } } }",
+			want: `{"block":{"mevInfo":{"version":null,"builder":null},"transactions":[{"systemTx":false}]}}`,
+		},
+	} {
+		res := handler.Schema.Exec(context.Background(), tt.body, "", map[string]interface{}{})
+		if res.Errors != nil {
+			t.Fatalf("failed to execute query for testcase #%d: %v", i, res.Errors)
+		}
+		have, err := json.Marshal(res.Data)
+		if err != nil {
+			t.Fatalf("failed to encode graphql response for testcase #%d: %s", i, err)
+		}
+		if string(have) != tt.want {
+			t.Errorf("response unmatch for testcase #%d.\nhave:\n%s\nwant:\n%s", i, have, tt.want)
+		}
+	}
+}
+
+// Tests that the BSC specific fields resolve against the Parlia consensus state.
+func TestBSCFieldsParlia(t *testing.T) {
+	// Create a Parlia chain with a genesis validator set of three
+	config := *params.TestChainConfig
+	config.Parlia = &params.ParliaConfig{}
+
+	extra := make([]byte, 32+3*common.AddressLength+65)
+	for i := 0; i < 3; i++ {
+		extra[32+i*common.AddressLength] = byte(i + 1)
+	}
+	genesis := &core.Genesis{
+		Config:     &config,
+		GasLimit:   11500000,
+		Difficulty: common.Big1,
+		ExtraData:  extra,
+	}
+	stack := createNode(t)
+	defer stack.Close()
+
+	handler, _ := newGQLService(t, stack, false, genesis, 0, nil)
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	for i, tt := range []struct {
+		body string
+		want string
+	}{
+		{
+			body: "{block(number: 0) { validators turnLength } }",
+			want: `{"block":{"validators":["0x0100000000000000000000000000000000000000","0x0200000000000000000000000000000000000000","0x0300000000000000000000000000000000000000"],"turnLength":"0x1"}}`,
+		},
+		{
+			body: "{block(number: 0) { voteAttestation { voters } justified { number } finalized { number } } }",
+			want: `{"block":{"voteAttestation":null,"justified":{"number":"0x0"},"finalized":{"number":"0x0"}}}`,
+		},
+	} {
+		res := handler.Schema.Exec(context.Background(), tt.body, "", map[string]interface{}{})
+		if res.Errors != nil {
+			t.Fatalf("failed to execute query for testcase #%d: %v", i, res.Errors)
+		}
+		have, err := json.Marshal(res.Data)
+		if err != nil {
+			t.Fatalf("failed to encode graphql response for testcase #%d: %s", i, err)
+		}
+		if string(have) != tt.want {
+			t.Errorf("response unmatch for testcase #%d.\nhave:\n%s\nwant:\n%s", i, have, tt.want)
+		}
+	}
+}
+
 // TestGraphQLMaxDepth ensures that queries exceeding the configured maximum depth
 // are rejected to prevent resource exhaustion from deeply nested operations.
 func TestGraphQLMaxDepth(t *testing.T) {
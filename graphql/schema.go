@@ -89,6 +89,45 @@ const schema string = `
         amount: Long!
     }
 
+    # BlobSidecar holds the blobs of a blob transaction along with their KZG
+    # commitments and proofs.
+    type BlobSidecar {
+        # TransactionHash is the hash of the blob transaction.
+        transactionHash: Bytes32!
+        # TransactionIndex is the index of the blob transaction in the block.
+        transactionIndex: Long!
+        blobs: [Bytes!]!
+        commitments: [Bytes!]!
+        proofs: [Bytes!]!
+    }
+
+    # VoteAttestation is the aggregated fast finality vote carried in a Parlia
+    # block header.
+    type VoteAttestation {
+        # VoteAddressSet is the bitset of the validators who voted.
+        voteAddressSet: Long!
+        # AggSignature is the aggregated BLS signature of the votes.
+        aggSignature: Bytes!
+        # SourceNumber and SourceHash identify the latest justified block
+        # the votes were cast from.
+        sourceNumber: Long!
+        sourceHash: Bytes32!
+        # TargetNumber and TargetHash identify the block voted for.
+        targetNumber: Long!
+        targetHash: Bytes32!
+        # Voters are the validators who signed the attestation.
+        voters: [Address!]!
+        extra: Bytes!
+    }
+
+    # BlockMevInfo describes the builder a block was sourced from.
+    type BlockMevInfo {
+        # Version of the MEV info encoding, null if the block has none.
+        version: String
+        # Builder is the address of the builder, null if the block has none.
+        builder: Address
+    }
+
     # Transaction is an Ethereum transaction.
     type Transaction {
         # Hash is the hash of this transaction.
@@ -170,6 +209,9 @@ const schema string = `
         rawReceipt: Bytes!
         # BlobVersionedHashes is a set of hash outputs from the blobs in the transaction.
         blobVersionedHashes: [Bytes32!]
+        # SystemTx is true if this is a Parlia system transaction. It is false
+        # for pending transactions.
+        systemTx: Boolean!
     }
 
     # BlockFilterCriteria encapsulates log filter criteria for a filter applied
@@ -279,6 +321,22 @@ const schema string = `
         blobGasUsed: Long
         # ExcessBlobGas is a running total of blob gas consumed in excess of the target, prior to the block.
         excessBlobGas: Long
+        # Sidecars is the list of blob sidecars of the transactions in this block.
+        sidecars: [BlobSidecar!]
+        # VoteAttestation is the fast finality vote attestation carried in this
+        # block's header, null if there is none.
+        voteAttestation: VoteAttestation
+        # Validators is the Parlia validator set at this block.
+        validators: [Address!]
+        # TurnLength is the number of consecutive blocks a validator produces
+        # in its turn at this block.
+        turnLength: Long
+        # Justified is the latest justified block as seen from this block.
+        justified: Block
+        # Finalized is the latest finalized block as seen from this block.
+        finalized: Block
+        # MevInfo describes the builder this block was sourced from.
+        mevInfo: BlockMevInfo!
     }
 
     # CallData represents the data associated with a local contract call.
@@ -320,6 +378,12 @@ const schema string = `
         # ToBlock is the block at which to stop searching, inclusive. Defaults
         # to the latest block if not supplied.
         toBlock: Long
+        # FromTag is a block tag (e.g. "finalized:2/3") at which to start
+        # searching, mutually exclusive with fromBlock.
+        fromTag: String
+        # ToTag is a block tag (e.g. "voted") at which to stop searching,
+        # mutually exclusive with toBlock.
+        toTag: String
         # Addresses is a list of addresses that are of interest. If this list is
         # empty, results will not be filtered by address.
         addresses: [Address!]
@@ -363,9 +427,10 @@ const schema string = `
     }
 
     type Query {
-        # Block fetches an Ethereum block by number or by hash. If neither is
-        # supplied, the most recent known block is returned.
-        block(number: Long, hash: Bytes32): Block
+        # Block fetches an Ethereum block by number, by hash or by block tag
+        # (e.g. "safe", "voted" or "finalized:2/3"). If none is supplied, the
+        # most recent known block is returned.
+        block(number: Long, hash: Bytes32, tag: String): Block
         # Blocks returns all the blocks between two numbers, inclusive. If
         # to is not supplied, it defaults to the most recent known block.
         blocks(from: Long, to: Long): [Block!]!
@@ -41,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	buildertypes "github.com/ethereum/go-ethereum/core/types/builder"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -293,6 +294,25 @@ func (api *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// SenderScore is the admission state of a transaction sender, as tracked by the
+// transaction pool. The counters decay over time, so they are fractional.
+type SenderScore struct {
+	Score     float64 `json:"score"`     // Score in (0, 1], scaling the sender's throughput allowance
+	Admitted  float64 `json:"admitted"`  // Number of transactions admitted into the pool
+	Included  float64 `json:"included"`  // Number of transactions included in blocks
+	Dropped   float64 `json:"dropped"`   // Number of transactions dropped without inclusion
+	Replaced  float64 `json:"replaced"`  // Number of replacement transactions
+	Gapped    float64 `json:"gapped"`    // Number of transactions added with a nonce gap
+	Allowance uint64  `json:"allowance"` // Number of transactions admissible in the current window
+	Used      uint64  `json:"used"`      // Number of transactions admitted in the current window
+}
+
+// InspectScores retrieves the admission scores of all senders tracked by the
+// transaction pool. Nil is returned if sender scoring is disabled.
+func (api *TxPoolAPI) InspectScores() map[common.Address]SenderScore {
+	return api.b.TxPoolScores()
+}
+
 // EthereumAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type EthereumAccountAPI struct {
@@ -311,12 +331,13 @@ func (api *EthereumAccountAPI) Accounts() []common.Address {
 
 // BlockChainAPI provides an API to access Ethereum blockchain data.
 type BlockChainAPI struct {
-	b Backend
+	b     Backend
+	cache *ResponseCache // Cache of the responses anchored to finalized blocks, optional
 }
 
 // NewBlockChainAPI creates a new Ethereum blockchain API.
 func NewBlockChainAPI(b Backend) *BlockChainAPI {
-	return &BlockChainAPI{b}
+	return &BlockChainAPI{b: b, cache: ResponseCacheOf(b)}
 }
 
 // ChainId is the EIP-155 replay-protection chain id for the current Ethereum chain config.
@@ -485,27 +506,32 @@ func decodeHash(s string) (h common.Hash, inputLength int, err error) {
 //   - When number is -3 the chain finalized header is returned.
 //   - When number is -4 the chain safe header is returned.
 func (api *BlockChainAPI) GetHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error) {
-	header, err := api.b.HeaderByNumber(ctx, number)
-	if header != nil && err == nil {
-		response := api.rpcMarshalHeader(ctx, header)
-		if number == rpc.PendingBlockNumber {
-			// Pending header need to nil out a few fields
-			for _, field := range []string{"hash", "nonce", "miner"} {
-				response[field] = nil
+	return CachedResponse(ctx, api.cache, numberCacheKey("eth_getHeaderByNumber", number), func() (map[string]interface{}, *types.Header, error) {
+		header, err := api.b.HeaderByNumber(ctx, number)
+		if header != nil && err == nil {
+			response := api.rpcMarshalHeader(ctx, header)
+			if number == rpc.PendingBlockNumber {
+				// Pending header need to nil out a few fields
+				for _, field := range []string{"hash", "nonce", "miner"} {
+					response[field] = nil
+				}
 			}
+			return response, header, err
 		}
-		return response, err
-	}
-	return nil, err
+		return nil, nil, err
+	})
 }
 
 // GetHeaderByHash returns the requested header by hash.
 func (api *BlockChainAPI) GetHeaderByHash(ctx context.Context, hash common.Hash) map[string]interface{} {
-	header, _ := api.b.HeaderByHash(ctx, hash)
-	if header != nil {
-		return api.rpcMarshalHeader(ctx, header)
-	}
-	return nil
+	response, _ := CachedResponse(ctx, api.cache, fmt.Sprintf("eth_getHeaderByHash/%x", hash), func() (map[string]interface{}, *types.Header, error) {
+		header, _ := api.b.HeaderByHash(ctx, hash)
+		if header != nil {
+			return api.rpcMarshalHeader(ctx, header), header, nil
+		}
+		return nil, nil, nil
+	})
+	return response
 }
 
 // GetBlockByNumber returns the requested canonical block.
@@ -516,28 +542,37 @@ func (api *BlockChainAPI) GetHeaderByHash(ctx context.Context, hash common.Hash)
 //   - When fullTx is true all transactions in the block are returned, otherwise
 //     only the transaction hash is returned.
 func (api *BlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
-	block, err := api.b.BlockByNumber(ctx, number)
-	if block != nil && err == nil {
-		response, err := api.rpcMarshalBlock(ctx, block, true, fullTx)
-		if err == nil && number == rpc.PendingBlockNumber {
-			// Pending blocks need to nil out a few fields
-			for _, field := range []string{"hash", "nonce", "miner"} {
-				response[field] = nil
+	key := numberCacheKey("eth_getBlockByNumber", number)
+	if key != "" {
+		key = fmt.Sprintf("%s/%t", key, fullTx)
+	}
+	return CachedResponse(ctx, api.cache, key, func() (map[string]interface{}, *types.Header, error) {
+		block, err := api.b.BlockByNumber(ctx, number)
+		if block != nil && err == nil {
+			response, err := api.rpcMarshalBlock(ctx, block, true, fullTx)
+			if err == nil && number == rpc.PendingBlockNumber {
+				// Pending blocks need to nil out a few fields
+				for _, field := range []string{"hash", "nonce", "miner"} {
+					response[field] = nil
+				}
 			}
+			return response, block.Header(), err
 		}
-		return response, err
-	}
-	return nil, err
+		return nil, nil, err
+	})
 }
 
 // GetBlockByHash returns the requested block. When fullTx is true all transactions in the block are returned in full
 // detail, otherwise only the transaction hash is returned.
 func (api *BlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
-	block, err := api.b.BlockByHash(ctx, hash)
-	if block != nil {
-		return api.rpcMarshalBlock(ctx, block, true, fullTx)
-	}
-	return nil, err
+	return CachedResponse(ctx, api.cache, fmt.Sprintf("eth_getBlockByHash/%x/%t", hash, fullTx), func() (map[string]interface{}, *types.Header, error) {
+		block, err := api.b.BlockByHash(ctx, hash)
+		if block != nil {
+			response, err := api.rpcMarshalBlock(ctx, block, true, fullTx)
+			return response, block.Header(), err
+		}
+		return nil, nil, err
+	})
 }
 
 // BlockMevInfo describes a block's MEV builder attribution.
@@ -565,21 +600,9 @@ func (api *BlockChainAPI) GetBlockMevInfo(ctx context.Context, blockNrOrHash rpc
 		BlockHash:   header.Hash(),
 		Miner:       header.Coinbase,
 	}
-	if header.RequestsHash == nil {
-		return info, nil
-	}
-	version, builder, ok := buildertypes.DecodeBlockMevInfo(*header.RequestsHash)
-	if !ok {
-		return info, nil
+	if version, builder, ok := buildertypes.HeaderMevInfo(header); ok {
+		info.Version, info.Builder = version.String(), &builder
 	}
-	switch version {
-	case buildertypes.BlockMevInfoVersionBid:
-		info.Version = "v1"
-	case buildertypes.BlockMevInfoVersionBidBlock:
-		info.Version = "v2"
-	}
-	b := builder
-	info.Builder = &b
 	return info, nil
 }
 
@@ -591,12 +614,19 @@ func (api *BlockChainAPI) Health() bool {
 }
 
 func (api *BlockChainAPI) getFinalizedNumber(ctx context.Context, verifiedValidatorNum int64) (int64, error) {
-	parliaConfig := api.b.ChainConfig().Parlia
+	return FinalizedNumberByValidators(ctx, api.b, verifiedValidatorNum)
+}
+
+// FinalizedNumberByValidators returns max(fastFinalizedHeight, probabilisticFinalizedHeight),
+// where the latter is the highest block built upon by verifiedValidatorNum distinct
+// validators. See GetFinalizedHeader for the accepted thresholds.
+func FinalizedNumberByValidators(ctx context.Context, b Backend, verifiedValidatorNum int64) (int64, error) {
+	parliaConfig := b.ChainConfig().Parlia
 	if parliaConfig == nil {
 		return 0, fmt.Errorf("only parlia engine supported")
 	}
 
-	curValidators, err := api.b.CurrentValidators()
+	curValidators, err := b.CurrentValidators()
 	if err != nil { // impossible
 		return 0, err
 	}
@@ -611,12 +641,12 @@ func (api *BlockChainAPI) getFinalizedNumber(ctx context.Context, verifiedValida
 		return 0, fmt.Errorf("%d neither within the range [1,%d] nor the range [-3,-1]", verifiedValidatorNum, valLen)
 	}
 
-	fastFinalizedHeader, err := api.b.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	fastFinalizedHeader, err := b.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
 	if err != nil { // impossible
 		return 0, err
 	}
 
-	latestHeader, err := api.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	latestHeader, err := b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if err != nil { // impossible
 		return 0, err
 	}
@@ -625,7 +655,7 @@ func (api *BlockChainAPI) getFinalizedNumber(ctx context.Context, verifiedValida
 	confirmedValSet[lastHeader.Coinbase] = struct{}{}
 	epochLength := int(1000) // maxwellEpochLength
 	for count := 1; int64(len(confirmedValSet)) < verifiedValidatorNum && count <= epochLength && lastHeader.Number.Int64() > max(fastFinalizedHeader.Number.Int64(), 1); count++ {
-		lastHeader, err = api.b.HeaderByHash(ctx, lastHeader.ParentHash)
+		lastHeader, err = b.HeaderByHash(ctx, lastHeader.ParentHash)
 		if err != nil { // impossible
 			return 0, err
 		}
@@ -751,6 +781,20 @@ func (api *BlockChainAPI) GetStorageAt(ctx context.Context, address common.Addre
 
 // GetBlockReceipts returns the block receipts for the given block hash or number or tag.
 func (api *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	var key string
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		key = fmt.Sprintf("eth_getBlockReceipts/%x", hash)
+	} else if number, ok := blockNrOrHash.Number(); ok {
+		key = numberCacheKey("eth_getBlockReceipts", number)
+	}
+	return CachedResponse(ctx, api.cache, key, func() ([]map[string]interface{}, *types.Header, error) {
+		return api.getBlockReceipts(ctx, blockNrOrHash)
+	})
+}
+
+// getBlockReceipts retrieves the block receipts for the given block hash or number
+// or tag, along with the header of the block.
+func (api *BlockChainAPI) getBlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, *types.Header, error) {
 	var (
 		err      error
 		block    *types.Block
@@ -759,21 +803,21 @@ func (api *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rp
 	if blockNr, ok := blockNrOrHash.Number(); ok && blockNr == rpc.PendingBlockNumber {
 		block, receipts, _ = api.b.Pending()
 		if block == nil {
-			return nil, errors.New("pending receipts is not available")
+			return nil, nil, errors.New("pending receipts is not available")
 		}
 	} else {
 		block, err = api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
 		if block == nil || err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		receipts, err = api.b.GetReceipts(ctx, block.Hash())
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 	txs := block.Transactions()
 	if len(txs) != len(receipts) {
-		return nil, fmt.Errorf("receipts length mismatch: %d vs %d", len(txs), len(receipts))
+		return nil, nil, fmt.Errorf("receipts length mismatch: %d vs %d", len(txs), len(receipts))
 	}
 	// Derive the sender.
 	signer := types.MakeSigner(api.b.ChainConfig(), block.Number(), block.Time())
@@ -782,7 +826,7 @@ func (api *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rp
 	for i, receipt := range receipts {
 		result[i] = MarshalReceipt(receipt, block.Hash(), block.NumberU64(), signer, txs[i], i)
 	}
-	return result, nil
+	return result, block.Header(), nil
 }
 
 func (api *BlockChainAPI) GetBlobSidecars(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, fullBlob *bool) ([]map[string]interface{}, error) {
@@ -1979,6 +2023,7 @@ type TransactionAPI struct {
 	b         Backend
 	nonceLock *AddrLocker
 	signer    types.Signer
+	cache     *ResponseCache // Cache of the responses anchored to finalized blocks, optional
 }
 
 // NewTransactionAPI creates a new RPC service with methods for interacting with transactions.
@@ -1986,7 +2031,7 @@ func NewTransactionAPI(b Backend, nonceLock *AddrLocker) *TransactionAPI {
 	// The signer used by the API should always be the 'latest' known one because we expect
 	// signers to be backwards-compatible with old transactions.
 	signer := types.LatestSigner(b.ChainConfig())
-	return &TransactionAPI{b, nonceLock, signer}
+	return &TransactionAPI{b, nonceLock, signer, ResponseCacheOf(b)}
 }
 
 // GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
@@ -2072,25 +2117,33 @@ func (api *TransactionAPI) GetTransactionCount(ctx context.Context, address comm
 
 // GetTransactionByHash returns the transaction for the given hash
 func (api *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (*RPCTransaction, error) {
+	return CachedResponse(ctx, api.cache, fmt.Sprintf("eth_getTransactionByHash/%x", hash), func() (*RPCTransaction, *types.Header, error) {
+		return api.getTransactionByHash(ctx, hash)
+	})
+}
+
+// getTransactionByHash retrieves the transaction for the given hash, along with
+// the header of the including block if already included.
+func (api *TransactionAPI) getTransactionByHash(ctx context.Context, hash common.Hash) (*RPCTransaction, *types.Header, error) {
 	// Try to return an already finalized transaction
 	found, tx, blockHash, blockNumber, index := api.b.GetCanonicalTransaction(hash)
 	if !found {
 		// No finalized transaction, try to retrieve it from the pool
 		if tx := api.b.GetPoolTransaction(hash); tx != nil {
-			return NewRPCPendingTransaction(tx, api.b.CurrentHeader(), api.b.ChainConfig()), nil
+			return NewRPCPendingTransaction(tx, api.b.CurrentHeader(), api.b.ChainConfig()), nil, nil
 		}
 		// If also not in the pool there is a chance the tx indexer is still in progress.
 		if !api.b.TxIndexDone() {
-			return nil, NewTxIndexingError()
+			return nil, nil, NewTxIndexingError()
 		}
 		// If the transaction is not found in the pool and the indexer is done, return nil
-		return nil, nil
+		return nil, nil, nil
 	}
 	header, err := api.b.HeaderByHash(ctx, blockHash)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return newRPCTransaction(tx, blockHash, blockNumber, header.Time, index, header.BaseFee, api.b.ChainConfig()), nil
+	return newRPCTransaction(tx, blockHash, blockNumber, header.Time, index, header.BaseFee, api.b.ChainConfig()), header, nil
 }
 
 // GetRawTransactionByHash returns the bytes of the transaction for the given hash.
@@ -2228,21 +2281,60 @@ func (api *TransactionAPI) GetTransactionDataAndReceipt(ctx context.Context, has
 
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
 func (api *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
-	found, tx, blockHash, blockNumber, index := api.b.GetCanonicalTransaction(hash)
-	if !found {
-		// Make sure indexer is done.
-		if !api.b.TxIndexDone() {
-			return nil, NewTxIndexingError()
+	return CachedResponse(ctx, api.cache, fmt.Sprintf("eth_getTransactionReceipt/%x", hash), func() (map[string]interface{}, *types.Header, error) {
+		found, tx, blockHash, blockNumber, index := api.b.GetCanonicalTransaction(hash)
+		if !found {
+			// Make sure indexer is done.
+			if !api.b.TxIndexDone() {
+				return nil, nil, NewTxIndexingError()
+			}
+			// No such tx.
+			return nil, nil, nil
+		}
+		receipt, err := api.b.GetCanonicalReceipt(tx, blockHash, blockNumber, index)
+		if err != nil {
+			return nil, nil, err
 		}
-		// No such tx.
+		// Anchor the receipt to the including block, if still around
+		header, _ := api.b.HeaderByHash(ctx, blockHash)
+
+		// Derive the sender.
+		return MarshalReceipt(receipt, blockHash, blockNumber, api.signer, tx, int(index)), header, nil
+	})
+}
+
+// GetTransactionLifecycle returns what the local node observed about the given
+// transaction: when and from whom it was first seen, the pool transitions it
+// went through and the block it was included in. Nil is returned if lifecycle
+// tracking is disabled or the transaction is not tracked (anymore).
+func (api *TransactionAPI) GetTransactionLifecycle(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	lifecycle := api.b.TxLifecycle(hash)
+	if lifecycle == nil {
 		return nil, nil
 	}
-	receipt, err := api.b.GetCanonicalReceipt(tx, blockHash, blockNumber, index)
-	if err != nil {
-		return nil, err
+	events := make([]map[string]interface{}, len(lifecycle.Events))
+	for i, event := range lifecycle.Events {
+		events[i] = map[string]interface{}{
+			"event": event.Kind,
+			"time":  hexutil.Uint64(event.Time.UnixMilli()),
+		}
 	}
-	// Derive the sender.
-	return MarshalReceipt(receipt, blockHash, blockNumber, api.signer, tx, int(index)), nil
+	fields := map[string]interface{}{
+		"hash":      lifecycle.Hash,
+		"firstSeen": hexutil.Uint64(lifecycle.FirstSeen.UnixMilli()),
+		"origin":    lifecycle.Origin.String(),
+		"events":    events,
+	}
+	if lifecycle.Peer != "" {
+		fields["peer"] = lifecycle.Peer
+	}
+	if lifecycle.BlockHash != (common.Hash{}) {
+		fields["blockHash"] = lifecycle.BlockHash
+		fields["blockNumber"] = hexutil.Uint64(lifecycle.BlockNumber)
+		fields["includedAt"] = hexutil.Uint64(lifecycle.IncludedAt.UnixMilli())
+		fields["inclusionLatency"] = hexutil.Uint64(lifecycle.IncludedAt.Sub(lifecycle.FirstSeen).Milliseconds())
+	}
+	return fields, nil
 }
 
 // MarshalReceipt marshals a transaction receipt into a JSON object.
@@ -2548,20 +2640,28 @@ func (api *TransactionAPI) SendRawTransactionSync(ctx context.Context, input hex
 }
 
 // SendRawTransactionConditional will add the signed transaction to the transaction pool.
-// The sender/bundler is responsible for signing the transaction
+// The sender/bundler is responsible for signing the transaction.
+//
+// The conditions stay attached to the transaction while it's pooled: they are
+// re-validated on every chain head change and before inclusion by the miner,
+// and the transaction is evicted as soon as they can't be satisfied anymore.
 func (api *TransactionAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, opts types.TransactionOpts) (common.Hash, error) {
 	tx := new(types.Transaction)
 	if err := tx.UnmarshalBinary(input); err != nil {
 		return common.Hash{}, err
 	}
+	if tx.Type() == types.BlobTxType {
+		return common.Hash{}, errors.New("conditions are not supported for blob transactions")
+	}
 	header := api.b.CurrentHeader()
 	state, _, err := api.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(header.Number.Int64()))
 	if state == nil || err != nil {
 		return common.Hash{}, err
 	}
-	if err := TxOptsCheck(opts, header.Number.Uint64(), header.Time, state); err != nil {
+	if err := txpool.ValidateConditionsAdmission(&opts, header, state); err != nil {
 		return common.Hash{}, err
 	}
+	tx.SetConditions(&opts)
 	return SubmitTransaction(ctx, api.b, tx)
 }
 
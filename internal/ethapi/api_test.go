@@ -47,6 +47,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	buildertypes "github.com/ethereum/go-ethereum/core/types/builder"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -746,6 +747,12 @@ func (b testBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transactio
 func (b testBackend) SubscribeNewTxsEvent(events chan<- core.NewTxsEvent) event.Subscription {
 	panic("implement me")
 }
+func (b testBackend) TxLifecycle(txHash common.Hash) *txpool.TxLifecycle {
+	return nil
+}
+func (b testBackend) TxPoolScores() map[common.Address]SenderScore {
+	return nil
+}
 func (b testBackend) ChainConfig() *params.ChainConfig             { return b.chain.Config() }
 func (b testBackend) Engine() consensus.Engine                     { return b.chain.Engine() }
 func (b testBackend) CurrentValidators() ([]common.Address, error) { return []common.Address{}, nil }
@@ -0,0 +1,192 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	responseCacheHitMeter  = metrics.NewRegisteredMeter("rpc/cache/hit", nil)
+	responseCacheMissMeter = metrics.NewRegisteredMeter("rpc/cache/miss", nil)
+	responseCacheSizeGauge = metrics.NewRegisteredGauge("rpc/cache/size", nil)
+)
+
+// headerReader is the chain access needed by the response cache.
+type headerReader interface {
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
+}
+
+// cachedResponse is a response in the cache, along with the block anchoring it.
+type cachedResponse struct {
+	result any
+	number uint64      // Number of the block the response is anchored to
+	hash   common.Hash // Hash of the block the response is anchored to
+	size   int         // Approximate size of the response in bytes
+}
+
+// ResponseCache caches the responses of RPC methods which are fully determined
+// by finalized blocks and thus never change. Every response is anchored to the
+// highest block it depends on and is only cached if that block is finalized.
+// The anchors are rechecked against the canonical chain on every hit, so that
+// the responses are never served after a rewind of the chain.
+type ResponseCache struct {
+	chain   headerReader
+	limit   int // Maximum total size of the responses in bytes
+	size    int // Current total size of the responses in bytes
+	entries lru.BasicLRU[string, *cachedResponse]
+	lock    sync.Mutex
+}
+
+// NewResponseCache creates a response cache holding up to limit bytes of
+// responses anchored to the finalized blocks of the given chain.
+func NewResponseCache(chain headerReader, limit int) *ResponseCache {
+	return &ResponseCache{
+		chain:   chain,
+		limit:   limit,
+		entries: lru.NewBasicLRU[string, *cachedResponse](math.MaxInt),
+	}
+}
+
+// ResponseCacheOf retrieves the response cache of a backend, nil if the backend
+// does not provide any.
+func ResponseCacheOf(backend any) *ResponseCache {
+	if b, ok := backend.(interface{ ResponseCache() *ResponseCache }); ok {
+		return b.ResponseCache()
+	}
+	return nil
+}
+
+// Purge drops all the cached responses, used when the chain is rewound.
+func (c *ResponseCache) Purge() {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries.Purge()
+	c.size = 0
+	responseCacheSizeGauge.Update(0)
+}
+
+// get retrieves the cached response of a key, if still anchored to the
+// canonical chain.
+func (c *ResponseCache) get(ctx context.Context, key string) (any, bool) {
+	c.lock.Lock()
+	entry, ok := c.entries.Get(key)
+	c.lock.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+	if header, _ := c.chain.HeaderByNumber(ctx, rpc.BlockNumber(entry.number)); header == nil || header.Hash() != entry.hash {
+		c.remove(key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// add caches the response of a key if its anchor is a finalized canonical block.
+func (c *ResponseCache) add(ctx context.Context, key string, result any, anchor *types.Header) {
+	final, _ := c.chain.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	if final == nil || anchor.Number.Cmp(final.Number) > 0 {
+		return
+	}
+	if header, _ := c.chain.HeaderByNumber(ctx, rpc.BlockNumber(anchor.Number.Int64())); header == nil || header.Hash() != anchor.Hash() {
+		return
+	}
+	blob, err := json.Marshal(result)
+	if err != nil || len(blob) > c.limit {
+		return
+	}
+	entry := &cachedResponse{
+		result: result,
+		number: anchor.Number.Uint64(),
+		hash:   anchor.Hash(),
+		size:   len(key) + len(blob),
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if old, ok := c.entries.Peek(key); ok {
+		c.size -= old.size
+	}
+	c.entries.Add(key, entry)
+	c.size += entry.size
+	for c.size > c.limit {
+		_, evicted, ok := c.entries.RemoveOldest()
+		if !ok {
+			break
+		}
+		c.size -= evicted.size
+	}
+	responseCacheSizeGauge.Update(int64(c.size))
+}
+
+// remove drops the cached response of a key.
+func (c *ResponseCache) remove(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if entry, ok := c.entries.Peek(key); ok {
+		c.entries.Remove(key)
+		c.size -= entry.size
+		responseCacheSizeGauge.Update(int64(c.size))
+	}
+}
+
+// CachedResponse serves the response of a key from the cache if available, or
+// computes it otherwise. The compute callback returns the response along with
+// the block anchoring it, nil if the response must not be cached. An empty key
+// disables the caching, e.g. for requests made with block tags.
+func CachedResponse[T any](ctx context.Context, c *ResponseCache, key string, compute func() (T, *types.Header, error)) (T, error) {
+	if c == nil || key == "" {
+		result, _, err := compute()
+		return result, err
+	}
+	if result, ok := c.get(ctx, key); ok {
+		responseCacheHitMeter.Mark(1)
+		return result.(T), nil
+	}
+	responseCacheMissMeter.Mark(1)
+
+	result, anchor, err := compute()
+	if err == nil && anchor != nil {
+		c.add(ctx, key, result, anchor)
+	}
+	return result, err
+}
+
+// numberCacheKey returns the cache key of a method called with a block number,
+// empty for the block tags whose blocks move along with the chain.
+func numberCacheKey(method string, number rpc.BlockNumber) string {
+	if number < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", method, number)
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testCacheChain is a canonical chain of headers with a movable finalized block.
+type testCacheChain struct {
+	headers []*types.Header
+	final   uint64
+}
+
+func newTestCacheChain(n int) *testCacheChain {
+	chain := new(testCacheChain)
+	for i := 0; i < n; i++ {
+		chain.headers = append(chain.headers, &types.Header{Number: big.NewInt(int64(i))})
+	}
+	return chain
+}
+
+func (c *testCacheChain) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.FinalizedBlockNumber {
+		number = rpc.BlockNumber(c.final)
+	}
+	if number < 0 || int(number) >= len(c.headers) {
+		return nil, nil
+	}
+	return c.headers[number], nil
+}
+
+// reorg replaces the canonical header at the given number.
+func (c *testCacheChain) reorg(number int) {
+	c.headers[number] = &types.Header{Number: big.NewInt(int64(number)), Extra: []byte("reorged")}
+}
+
+// Tests that only the responses anchored to finalized canonical blocks are
+// cached, and that they are dropped when their anchors leave the chain.
+func TestResponseCache(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		chain = newTestCacheChain(10)
+		cache = NewResponseCache(chain, 1024)
+		calls int
+	)
+	chain.final = 5
+
+	call := func(key string, number int) string {
+		result, err := CachedResponse(ctx, cache, key, func() (string, *types.Header, error) {
+			calls++
+			header, _ := chain.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			return key, header, nil
+		})
+		if err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+		return result
+	}
+	check := func(key string, number int, want int) {
+		t.Helper()
+
+		calls = 0
+		if result := call(key, number); result != key {
+			t.Fatalf("%s: result mismatch: have %s, want %s", key, result, key)
+		}
+		if calls != want {
+			t.Errorf("%s: computation count mismatch: have %d, want %d", key, calls, want)
+		}
+	}
+	// Finalized responses are served from the cache after the first call
+	check("final", 5, 1)
+	check("final", 5, 0)
+
+	// Unfinalized responses and tag requests are always computed
+	check("unfinal", 6, 1)
+	check("unfinal", 6, 1)
+	check("", 1, 1)
+	check("", 1, 1)
+
+	// Responses becoming finalized are cached from then on
+	chain.final = 6
+	check("unfinal", 6, 1)
+	check("unfinal", 6, 0)
+
+	// Responses whose anchors are replaced are dropped
+	chain.reorg(5)
+	check("final", 5, 1)
+	check("final", 5, 0)
+
+	// Purging drops everything
+	cache.Purge()
+	check("final", 5, 1)
+	check("unfinal", 6, 1)
+}
+
+// Tests that the cache is kept within its size limit, evicting the least
+// recently used responses.
+func TestResponseCacheLimit(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		chain = newTestCacheChain(10)
+		cache = NewResponseCache(chain, 100)
+	)
+	chain.final = 9
+	anchor := chain.headers[1]
+
+	for _, key := range []string{"a", "b", "c"} {
+		cache.add(ctx, key, strings.Repeat("x", 30), anchor)
+	}
+	if cache.size > cache.limit {
+		t.Fatalf("cache size exceeds limit: have %d, limit %d", cache.size, cache.limit)
+	}
+	// Touch the oldest entry and insert another, evicting the second one
+	if _, ok := cache.get(ctx, "a"); !ok {
+		t.Fatal("entry missing before eviction")
+	}
+	cache.add(ctx, "d", strings.Repeat("x", 30), anchor)
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if _, ok := cache.get(ctx, key); ok != want {
+			t.Errorf("entry %s: presence mismatch: have %v, want %v", key, ok, want)
+		}
+	}
+	// Responses larger than the entire cache are never stored
+	cache.add(ctx, "huge", strings.Repeat("x", 200), anchor)
+	if _, ok := cache.get(ctx, "huge"); ok {
+		t.Error("oversized response cached")
+	}
+}
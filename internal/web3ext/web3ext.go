@@ -27,6 +27,7 @@ var Modules = map[string]string{
 	"rpc":    RpcJs,
 	"txpool": TxpoolJs,
 	"dev":    DevJs,
+	"trace":  TraceJs,
 }
 
 const ParliaJs = `
@@ -144,6 +145,16 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'startRequestLog',
+			call: 'admin_startRequestLog',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'stopRequestLog',
+			call: 'admin_stopRequestLog'
+		}),
 		new web3._extend.Method({
 			name: 'setBidBlockPermission',
 			call: 'admin_setBidBlockPermission',
@@ -160,6 +171,14 @@ web3._extend({
 			name: 'peers',
 			getter: 'admin_peers'
 		}),
+		new web3._extend.Property({
+			name: 'peerBook',
+			getter: 'admin_peerBook'
+		}),
+		new web3._extend.Property({
+			name: 'traffic',
+			getter: 'admin_traffic'
+		}),
 		new web3._extend.Property({
 			name: 'datadir',
 			getter: 'admin_datadir'
@@ -367,6 +386,22 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'startTraceExport',
+			call: 'debug_startTraceExport',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'traceExportStatus',
+			call: 'debug_traceExportStatus',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'cancelTraceExport',
+			call: 'debug_cancelTraceExport',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'traceBlockByNumber',
 			call: 'debug_traceBlockByNumber',
@@ -546,6 +581,11 @@ web3._extend({
 			call: 'eth_getRawTransactionByHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionLifecycle',
+			call: 'eth_getTransactionLifecycle',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransactionFromBlock',
 			call: function(args) {
@@ -684,6 +724,10 @@ web3._extend({
 			call: 'miner_setRecommitInterval',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'voteTimings',
+			call: 'miner_voteTimings'
+		}),
 	],
 	properties: []
 });
@@ -729,6 +773,10 @@ web3._extend({
 			name: 'inspect',
 			getter: 'txpool_inspect'
 		}),
+		new web3._extend.Property({
+			name: 'inspectScores',
+			getter: 'txpool_inspectScores'
+		}),
 		new web3._extend.Property({
 			name: 'status',
 			getter: 'txpool_status',
@@ -765,3 +813,17 @@ web3._extend({
 	],
 });
 `
+
+const TraceJs = `
+web3._extend({
+	property: 'trace',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'filter',
+			call: 'trace_filter',
+			params: 1
+		}),
+	],
+});
+`
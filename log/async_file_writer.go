@@ -12,6 +12,10 @@ import (
 
 const backupTimeFormat = "2006-01-02_15"
 
+// ErrBufferFull is returned by AsyncFileWriter.Write when the message is dropped
+// because the write buffer is full.
+var ErrBufferFull = errors.New("write buffer full")
+
 type TimeTicker struct {
 	stop chan struct{}
 	C    <-chan time.Time
@@ -207,6 +211,7 @@ func (w *AsyncFileWriter) Write(msg []byte) (n int, err error) {
 	select {
 	case w.buf <- buf:
 	default:
+		return 0, ErrBufferFull
 	}
 	return 0, nil
 }
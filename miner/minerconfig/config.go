@@ -71,6 +71,7 @@ type Config struct {
 	VoteEnable             bool           // Whether to vote when mining
 	MaxWaitProposalInSecs  *uint64        `toml:",omitempty"` // The maximum time to wait for the proposal to be done, it's aimed to prevent validator being slashed when restarting
 	DisableVoteAttestation bool           // Whether to skip assembling vote attestation
+	VoteTargetedRelay      bool           // Whether to push own votes to the peers of the upcoming proposers first
 
 	Mev MevConfig // Mev configuration
 }
@@ -914,6 +914,15 @@ LOOP:
 			txs.Pop()
 			continue
 		}
+		// Check whether the conditions attached to the transaction hold for the
+		// block being built. If not, start ignoring the sender until they do.
+		if opts := tx.Conditions(); opts != nil {
+			if err := txpool.ValidateConditions(opts, env.header.Number.Uint64(), env.header.Time, env.state); err != nil {
+				log.Trace("Ignoring conditional transaction", "hash", ltx.Hash, "err", err)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/gopool"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -182,6 +183,7 @@ func (api *adminAPI) StartHTTP(host *string, port *int, cors *string, apis *stri
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			requestLog:             api.node.requestLog,
 		},
 	}
 	if cors != nil {
@@ -261,6 +263,7 @@ func (api *adminAPI) StartWS(host *string, port *int, allowedOrigins *string, ap
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			requestLog:             api.node.requestLog,
 		},
 	}
 	if apis != nil {
@@ -299,6 +302,38 @@ func (api *adminAPI) StopWS() (bool, error) {
 	return true, nil
 }
 
+// StartRequestLog starts recording the calls served on the HTTP and WebSocket
+// endpoints, or updates the selection of the recorded calls if already started.
+// The slow threshold is a duration string, such as "500ms".
+func (api *adminAPI) StartRequestLog(sampleRate *float64, slowThreshold *string) (bool, error) {
+	config, ok := api.node.requestLog.Config()
+	if !ok {
+		config = api.node.requestLogConfig()
+	}
+	if sampleRate != nil {
+		config.SampleRate = *sampleRate
+	}
+	if slowThreshold != nil {
+		threshold, err := time.ParseDuration(*slowThreshold)
+		if err != nil {
+			return false, err
+		}
+		config.SlowThreshold = threshold
+	}
+	if err := api.node.requestLog.Start(config); err != nil {
+		return false, err
+	}
+	api.node.log.Info("RPC request log started", "samplerate", config.SampleRate, "slow", config.SlowThreshold)
+	return true, nil
+}
+
+// StopRequestLog stops recording the served calls.
+func (api *adminAPI) StopRequestLog() bool {
+	api.node.requestLog.Stop()
+	api.node.log.Info("RPC request log stopped")
+	return true
+}
+
 // Peers retrieves all the information we know about each individual peer at the
 // protocol granularity.
 func (api *adminAPI) Peers() ([]*p2p.PeerInfo, error) {
@@ -309,6 +344,26 @@ func (api *adminAPI) Peers() ([]*p2p.PeerInfo, error) {
 	return server.PeersInfo(), nil
 }
 
+// PeerBook retrieves the recorded connection history of the peers seen across
+// restarts, from the best scoring peer to the worst.
+func (api *adminAPI) PeerBook() ([]*p2p.PeerBookEntry, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.PeerBook(), nil
+}
+
+// Traffic retrieves the traffic exchanged over each subprotocol, along with the
+// current transfer rates and the bandwidth budget.
+func (api *adminAPI) Traffic() (*p2p.TrafficInfo, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.Traffic(), nil
+}
+
 // NodeInfo retrieves all the information we know about the host node at the
 // protocol granularity.
 func (api *adminAPI) NodeInfo() (*p2p.NodeInfo, error) {
@@ -0,0 +1,57 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	apiKeyHeader = "X-API-Key" // Header carrying the API key
+	apiKeyQuery  = "apikey"    // Query parameter carrying the API key, for clients unable to set headers
+)
+
+type apiKeyHandler struct {
+	keys *apiKeyStore
+	next http.Handler
+}
+
+// newAPIKeyHandler creates a http.Handler requiring a known API key on every
+// request, and enforcing the policy of the key on the RPC calls made.
+func newAPIKeyHandler(keys *apiKeyStore, next http.Handler) http.Handler {
+	return &apiKeyHandler{keys: keys, next: next}
+}
+
+// ServeHTTP implements http.Handler
+func (handler *apiKeyHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(apiKeyHeader)
+	if key == "" {
+		key = r.URL.Query().Get(apiKeyQuery)
+	}
+	if key == "" {
+		http.Error(out, "missing API key", http.StatusUnauthorized)
+		return
+	}
+	if handler.keys.gate(key) == nil {
+		http.Error(out, "invalid API key", http.StatusUnauthorized)
+		return
+	}
+	gate := &apiKeyClient{keys: handler.keys, key: key}
+	handler.next.ServeHTTP(out, r.WithContext(rpc.WithCallGate(r.Context(), gate)))
+}
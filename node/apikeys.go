@@ -0,0 +1,480 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// apiKeysReloadInterval is the interval at which the API keys file is
+	// checked for modifications.
+	apiKeysReloadInterval = 5 * time.Second
+
+	// apiKeysTagRefresh is the time the resolved block tags of log queries are
+	// cached for.
+	apiKeysTagRefresh = time.Second
+
+	// defaultComputeUnits is the cost of the methods missing from methodComputeUnits.
+	defaultComputeUnits = 10
+
+	errcodeKeyRevoked       = -32001
+	errcodeMethodNotAllowed = -32601
+	errcodeLimitExceeded    = -32005
+)
+
+// errAPIKeyRevoked is returned for the calls of connections opened with an API
+// key which was removed since.
+var errAPIKeyRevoked = &apiKeyError{errcodeKeyRevoked, "API key revoked"}
+
+// methodComputeUnits is the cost of the methods in compute units, roughly
+// reflecting the resources needed to serve them.
+var methodComputeUnits = map[string]int{
+	"eth_chainId":                       1,
+	"eth_blockNumber":                   1,
+	"net_version":                       1,
+	"web3_clientVersion":                1,
+	"eth_gasPrice":                      5,
+	"eth_maxPriorityFeePerGas":          5,
+	"eth_getBalance":                    10,
+	"eth_getCode":                       10,
+	"eth_getStorageAt":                  10,
+	"eth_getTransactionCount":           10,
+	"eth_getHeaderByNumber":             10,
+	"eth_getHeaderByHash":               10,
+	"eth_getTransactionLifecycle":       10,
+	"eth_getBlockByNumber":              15,
+	"eth_getBlockByHash":                15,
+	"eth_getFinalizedHeader":            15,
+	"eth_getFinalizedBlock":             15,
+	"eth_getTransactionByHash":          15,
+	"eth_getTransactionReceipt":         15,
+	"eth_getTransactionDataAndReceipt":  15,
+	"eth_call":                          25,
+	"eth_getBlobSidecarByTxHash":        25,
+	"eth_getBlockReceipts":              50,
+	"eth_getBlobSidecars":               50,
+	"eth_getTransactionsByBlockNumber":  50,
+	"eth_estimateGas":                   50,
+	"eth_sendRawTransaction":            50,
+	"eth_sendRawTransactionConditional": 50,
+	"eth_getLogs":                       75,
+	"eth_getFilterLogs":                 75,
+	"eth_getProof":                      75,
+	"debug_traceCall":                   300,
+	"debug_traceTransaction":            300,
+	"debug_traceBlockByNumber":          500,
+	"debug_traceBlockByHash":            500,
+	"trace_filter":                      500,
+}
+
+// APIKey is the policy of an API key, as configured in the API keys file. The
+// zero value of the limits means unlimited.
+type APIKey struct {
+	Key          string   `json:"key"`
+	Name         string   `json:"name"`                   // Label of the key in logs and metrics
+	Methods      []string `json:"methods,omitempty"`      // Methods allowed to call, all if empty
+	RequestRate  float64  `json:"requestRate,omitempty"`  // Method calls per second
+	ComputeRate  float64  `json:"computeRate,omitempty"`  // Compute units per second
+	MaxLogsRange uint64   `json:"maxLogsRange,omitempty"` // Maximum block range of eth_getLogs
+	MaxBatchSize int      `json:"maxBatchSize,omitempty"` // Maximum number of items in a batch
+}
+
+// loadAPIKeys reads and validates the API keys file, a JSON list of APIKey.
+func loadAPIKeys(path string) ([]APIKey, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(blob, &keys); err != nil {
+		return nil, fmt.Errorf("invalid API keys file %s: %v", path, err)
+	}
+	var (
+		seen  = make(map[string]struct{}, len(keys))
+		names = make(map[string]struct{}, len(keys))
+	)
+	for i, key := range keys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("API key #%d has no key", i)
+		}
+		if key.Name == "" {
+			return nil, fmt.Errorf("API key #%d has no name", i)
+		}
+		if _, ok := seen[key.Key]; ok {
+			return nil, fmt.Errorf("API key %q is duplicated", key.Name)
+		}
+		// The names label the metrics of the keys, which must not be shared
+		if _, ok := names[key.Name]; ok {
+			return nil, fmt.Errorf("API key name %q is duplicated", key.Name)
+		}
+		if key.RequestRate < 0 || key.ComputeRate < 0 || key.MaxBatchSize < 0 {
+			return nil, fmt.Errorf("API key %q has negative limits", key.Name)
+		}
+		seen[key.Key] = struct{}{}
+		names[key.Name] = struct{}{}
+	}
+	return keys, nil
+}
+
+// apiKeyStore maintains the gates of the API keys configured in a file, picking
+// up the modifications of the file without restart.
+type apiKeyStore struct {
+	path   string
+	chain  func(rpc.BlockNumber) (uint64, error) // Resolves a block tag into a block number
+	gates  map[string]*apiKeyGate
+	loaded time.Time // Modification time of the loaded file
+	lock   sync.RWMutex
+
+	tags     map[rpc.BlockNumber]resolvedTag // Recently resolved block tags
+	tagsLock sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// resolvedTag is the block number of a block tag at the time of resolution.
+type resolvedTag struct {
+	number uint64
+	time   time.Time
+}
+
+// newAPIKeyStore creates a store of the API keys configured in the given file.
+// The chain callback is used to resolve the block tags of log queries.
+func newAPIKeyStore(path string, chain func(rpc.BlockNumber) (uint64, error)) (*apiKeyStore, error) {
+	s := &apiKeyStore{
+		path:  path,
+		chain: chain,
+		gates: make(map[string]*apiKeyGate),
+		tags:  make(map[rpc.BlockNumber]resolvedTag),
+		quit:  make(chan struct{}),
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// start launches the loop reloading the keys file on modification.
+func (s *apiKeyStore) start() {
+	s.wg.Add(1)
+	go s.loop()
+}
+
+// stop terminates the reload loop.
+func (s *apiKeyStore) stop() {
+	close(s.quit)
+	s.wg.Wait()
+}
+
+func (s *apiKeyStore) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(apiKeysReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			info, err := os.Stat(s.path)
+			if err != nil {
+				log.Warn("Failed to check RPC API keys file", "path", s.path, "err", err)
+				continue
+			}
+			s.lock.RLock()
+			modified := !info.ModTime().Equal(s.loaded)
+			s.lock.RUnlock()
+
+			if modified {
+				if err := s.reload(); err != nil {
+					log.Error("Failed to reload RPC API keys, keeping the previous ones", "path", s.path, "err", err)
+
+					// Don't retry until the file is modified again
+					s.lock.Lock()
+					s.loaded = info.ModTime()
+					s.lock.Unlock()
+				}
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// reload replaces the keys with the current content of the file. The quota
+// states of the keys with an unchanged policy are carried over.
+func (s *apiKeyStore) reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	keys, err := loadAPIKeys(s.path)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	gates := make(map[string]*apiKeyGate, len(keys))
+	for _, key := range keys {
+		if gate, ok := s.gates[key.Key]; ok && reflect.DeepEqual(gate.policy, key) {
+			gates[key.Key] = gate
+			continue
+		}
+		gates[key.Key] = newAPIKeyGate(key, s.blockNumber)
+	}
+	s.gates, s.loaded = gates, info.ModTime()
+
+	log.Info("Loaded RPC API keys", "path", s.path, "keys", len(gates))
+	return nil
+}
+
+// gate retrieves the gate of an API key, nil if the key is unknown.
+func (s *apiKeyStore) gate(key string) *apiKeyGate {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.gates[key]
+}
+
+// blockNumber resolves a block tag into a block number, cached for a short while.
+func (s *apiKeyStore) blockNumber(tag rpc.BlockNumber) (uint64, error) {
+	s.tagsLock.Lock()
+	defer s.tagsLock.Unlock()
+
+	if resolved, ok := s.tags[tag]; ok && time.Since(resolved.time) < apiKeysTagRefresh {
+		return resolved.number, nil
+	}
+	number, err := s.chain(tag)
+	if err != nil {
+		return 0, err
+	}
+	s.tags[tag] = resolvedTag{number: number, time: time.Now()}
+	return number, nil
+}
+
+// apiKeyClient is the rpc.CallGate of the requests made with an API key. The
+// gate of the key is looked up on every call, so the long-lived WebSocket
+// connections follow the reloads of the keys file.
+type apiKeyClient struct {
+	keys *apiKeyStore
+	key  string
+}
+
+// AdmitBatch implements rpc.CallGate.
+func (c *apiKeyClient) AdmitBatch(size int) error {
+	gate := c.keys.gate(c.key)
+	if gate == nil {
+		return errAPIKeyRevoked
+	}
+	return gate.AdmitBatch(size)
+}
+
+// AdmitCall implements rpc.CallGate.
+func (c *apiKeyClient) AdmitCall(method string, params json.RawMessage) error {
+	gate := c.keys.gate(c.key)
+	if gate == nil {
+		return errAPIKeyRevoked
+	}
+	return gate.AdmitCall(method, params)
+}
+
+// apiKeyError is the error returned to the clients exceeding the permissions
+// or quotas of their API key.
+type apiKeyError struct {
+	code int
+	msg  string
+}
+
+func (e *apiKeyError) Error() string  { return e.msg }
+func (e *apiKeyError) ErrorCode() int { return e.code }
+
+var _ rpc.CallGate = (*apiKeyGate)(nil)
+
+// apiKeyGate enforces the policy of an API key on the requests made with it.
+type apiKeyGate struct {
+	policy  APIKey
+	methods map[string]struct{}                   // Allowed methods, nil if all are
+	resolve func(rpc.BlockNumber) (uint64, error) // Resolves the block tags of log queries
+
+	requests *rate.Limiter // Method call rate limiter, nil if unlimited
+	compute  *rate.Limiter // Compute unit rate limiter, nil if unlimited
+
+	callMeter    *metrics.Meter
+	computeMeter *metrics.Meter
+	rejectMeter  *metrics.Meter
+}
+
+func newAPIKeyGate(policy APIKey, resolve func(rpc.BlockNumber) (uint64, error)) *apiKeyGate {
+	g := &apiKeyGate{
+		policy:       policy,
+		resolve:      resolve,
+		callMeter:    metrics.GetOrRegisterMeter("rpc/apikey/"+policy.Name+"/calls", nil),
+		computeMeter: metrics.GetOrRegisterMeter("rpc/apikey/"+policy.Name+"/compute", nil),
+		rejectMeter:  metrics.GetOrRegisterMeter("rpc/apikey/"+policy.Name+"/rejected", nil),
+	}
+	if len(policy.Methods) > 0 {
+		g.methods = make(map[string]struct{}, len(policy.Methods))
+		for _, method := range policy.Methods {
+			g.methods[method] = struct{}{}
+		}
+	}
+	// The limiters allow bursts of one second worth of quota
+	if policy.RequestRate > 0 {
+		g.requests = rate.NewLimiter(rate.Limit(policy.RequestRate), int(math.Ceil(policy.RequestRate)))
+	}
+	if policy.ComputeRate > 0 {
+		burst := int(math.Ceil(policy.ComputeRate))
+		for _, units := range methodComputeUnits {
+			burst = max(burst, units) // every method must be callable eventually
+		}
+		g.compute = rate.NewLimiter(rate.Limit(policy.ComputeRate), burst)
+	}
+	return g
+}
+
+// AdmitBatch implements rpc.CallGate, enforcing the batch size limit.
+func (g *apiKeyGate) AdmitBatch(size int) error {
+	if g.policy.MaxBatchSize > 0 && size > g.policy.MaxBatchSize {
+		g.rejectMeter.Mark(1)
+		return &apiKeyError{errcodeLimitExceeded, fmt.Sprintf("batch too large for API key: %d > %d", size, g.policy.MaxBatchSize)}
+	}
+	return nil
+}
+
+// AdmitCall implements rpc.CallGate, enforcing the method allowlist, the rate
+// limits and the log query range limit.
+func (g *apiKeyGate) AdmitCall(method string, params json.RawMessage) error {
+	if err := g.admitCall(method, params); err != nil {
+		g.rejectMeter.Mark(1)
+		return err
+	}
+	return nil
+}
+
+func (g *apiKeyGate) admitCall(method string, params json.RawMessage) error {
+	// Subscriptions are always allowed to be cancelled
+	if strings.HasSuffix(method, "_unsubscribe") {
+		return nil
+	}
+	if g.methods != nil {
+		if _, ok := g.methods[method]; !ok {
+			return &apiKeyError{errcodeMethodNotAllowed, fmt.Sprintf("method %s is not allowed for API key", method)}
+		}
+	}
+	if g.policy.MaxLogsRange > 0 {
+		if err := g.checkLogsRange(method, params); err != nil {
+			return err
+		}
+	}
+	if g.requests != nil && !g.requests.Allow() {
+		return &apiKeyError{errcodeLimitExceeded, "request rate limit exceeded for API key"}
+	}
+	units, ok := methodComputeUnits[method]
+	if !ok {
+		units = defaultComputeUnits
+	}
+	if g.compute != nil && !g.compute.AllowN(time.Now(), units) {
+		return &apiKeyError{errcodeLimitExceeded, "compute unit limit exceeded for API key"}
+	}
+	g.callMeter.Mark(1)
+	g.computeMeter.Mark(int64(units))
+	return nil
+}
+
+// checkLogsRange verifies that the block range of a log query is within the
+// limit of the key. Besides eth_getLogs, the range is checked on the creation
+// of log filters and subscriptions, which are queried over it later. Malformed
+// queries are let through for the method itself to reject them.
+func (g *apiKeyGate) checkLogsRange(method string, params json.RawMessage) error {
+	var (
+		args []json.RawMessage
+		crit json.RawMessage
+	)
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil
+	}
+	switch method {
+	case "eth_getLogs", "eth_newFilter":
+		if len(args) > 0 {
+			crit = args[0]
+		}
+	case "eth_subscribe":
+		var kind string
+		if len(args) > 1 && json.Unmarshal(args[0], &kind) == nil && kind == "logs" {
+			crit = args[1]
+		}
+	}
+	if crit == nil {
+		return nil
+	}
+	var query struct {
+		BlockHash *string `json:"blockHash"`
+		FromBlock *string `json:"fromBlock"`
+		ToBlock   *string `json:"toBlock"`
+	}
+	if err := json.Unmarshal(crit, &query); err != nil || query.BlockHash != nil {
+		return nil
+	}
+	from, ok, err := g.resolveBlock(query.FromBlock)
+	if !ok || err != nil {
+		return err
+	}
+	to, ok, err := g.resolveBlock(query.ToBlock)
+	if !ok || err != nil {
+		return err
+	}
+	if to > from && to-from > g.policy.MaxLogsRange {
+		return &apiKeyError{errcodeLimitExceeded, fmt.Sprintf("block range too large for API key: %d > %d", to-from, g.policy.MaxLogsRange)}
+	}
+	return nil
+}
+
+// resolveBlock converts a block number or tag of a log query into a number,
+// defaulting to the latest block. The tags are resolved against the chain. The
+// boolean is false if the block is malformed.
+func (g *apiKeyGate) resolveBlock(block *string) (uint64, bool, error) {
+	number := rpc.LatestBlockNumber
+	if block != nil {
+		if err := number.UnmarshalJSON([]byte(*block)); err != nil {
+			return 0, false, nil
+		}
+	}
+	switch {
+	case number >= 0:
+		return uint64(number), true, nil
+	case number == rpc.EarliestBlockNumber:
+		return 0, true, nil
+	}
+	resolved, err := g.resolve(number)
+	if err != nil {
+		return 0, true, &apiKeyError{errcodeLimitExceeded, "unable to verify block range for API key"}
+	}
+	return resolved, true, nil
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+// writeAPIKeys writes the given keys into the keys file at path.
+func writeAPIKeys(t *testing.T, path string, keys []APIKey) {
+	t.Helper()
+
+	blob, err := json.Marshal(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, blob, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestAPIKeyStore(t *testing.T, keys []APIKey) (*apiKeyStore, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "apikeys.json")
+	writeAPIKeys(t, path, keys)
+
+	store, err := newAPIKeyStore(path, func(tag rpc.BlockNumber) (uint64, error) {
+		switch tag {
+		case rpc.SafeBlockNumber:
+			return 9995, nil
+		case rpc.FinalizedBlockNumber:
+			return 9990, nil
+		case rpc.LatestBlockNumber:
+			return 10000, nil
+		}
+		if _, ok := tag.FinalizedByValidators(); ok {
+			return 9980, nil
+		}
+		return 0, errors.New("unknown tag")
+	})
+	if err != nil {
+		t.Fatalf("failed to load keys: %v", err)
+	}
+	return store, path
+}
+
+// Tests that the HTTP and WebSocket endpoints require a known API key, and
+// enforce the method allowlist and the batch limit of the key.
+func TestAPIKeyHandler(t *testing.T) {
+	store, _ := newTestAPIKeyStore(t, []APIKey{
+		{Key: "open", Name: "open"},
+		{Key: "greeter", Name: "greeter", Methods: []string{"test_greet"}, MaxBatchSize: 2},
+	})
+	srv := newHTTPServer(testlog.Logger(t, log.LvlDebug), rpc.DefaultHTTPTimeouts)
+	if err := srv.enableRPC(apis(), httpConfig{rpcEndpointConfig: rpcEndpointConfig{apiKeys: store}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.enableWS(apis(), wsConfig{Origins: []string{"*"}, rpcEndpointConfig: rpcEndpointConfig{apiKeys: store}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.setListenAddr("localhost", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.stop()
+
+	url := "http://" + srv.listenAddr()
+
+	// Requests without valid keys are refused
+	if resp := rpcRequest(t, url, "test_greet"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("missing key: status mismatch: have %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+	if resp := rpcRequest(t, url, "test_greet", apiKeyHeader, "unknown"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unknown key: status mismatch: have %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+	// Method allowlists are enforced per key
+	call := func(key string, methods ...string) string {
+		t.Helper()
+
+		var resp *http.Response
+		if len(methods) == 1 {
+			resp = rpcRequest(t, url+"?"+apiKeyQuery+"="+key, methods[0])
+		} else {
+			resp = batchRpcRequest(t, url+"?"+apiKeyQuery+"="+key, methods)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status mismatch: have %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+	if body := call("greeter", "test_greet"); !strings.Contains(body, `"result":"Hello"`) {
+		t.Errorf("allowed method rejected: %s", body)
+	}
+	if body := call("greeter", "rpc_modules"); !strings.Contains(body, "not allowed for API key") {
+		t.Errorf("disallowed method served: %s", body)
+	}
+	if body := call("open", "rpc_modules"); !strings.Contains(body, `"result"`) {
+		t.Errorf("unrestricted key rejected: %s", body)
+	}
+	// Batch limits are enforced per key
+	if body := call("greeter", "test_greet", "test_greet", "test_greet"); !strings.Contains(body, "batch too large for API key") {
+		t.Errorf("oversized batch served: %s", body)
+	}
+	if body := call("open", "test_greet", "test_greet", "test_greet"); strings.Count(body, `"result":"Hello"`) != 3 {
+		t.Errorf("unrestricted batch rejected: %s", body)
+	}
+	// The policies apply to all calls of WebSocket connections
+	wsURL := "ws://" + srv.listenAddr()
+	if err := wsRequest(t, wsURL); err == nil {
+		t.Error("websocket connected without API key")
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{apiKeyHeader: {"greeter"}})
+	if err != nil {
+		t.Fatalf("failed to connect websocket: %v", err)
+	}
+	defer conn.Close()
+
+	for method, want := range map[string]string{"test_greet": `"result":"Hello"`, "rpc_modules": "not allowed for API key"} {
+		if err := conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method}); err != nil {
+			t.Fatal(err)
+		}
+		_, body, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(body), want) {
+			t.Errorf("websocket %s: response mismatch: have %s, want %s", method, body, want)
+		}
+	}
+}
+
+// Tests that the rate limits and the log range limit of the keys are enforced.
+func TestAPIKeyQuotas(t *testing.T) {
+	store, _ := newTestAPIKeyStore(t, []APIKey{
+		{Key: "requests", Name: "requests", RequestRate: 2},
+		{Key: "compute", Name: "compute", ComputeRate: 500},
+		{Key: "logs", Name: "logs", MaxLogsRange: 100},
+	})
+	var rpcErr rpc.Error
+
+	gate := store.gate("requests")
+	for i := 0; i < 2; i++ {
+		if err := gate.AdmitCall("eth_chainId", nil); err != nil {
+			t.Fatalf("call %d rejected: %v", i, err)
+		}
+	}
+	if err := gate.AdmitCall("eth_chainId", nil); !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeLimitExceeded {
+		t.Errorf("request rate limit not enforced: %v", err)
+	}
+	// A trace costs the entire compute budget, leaving none for further calls
+	gate = store.gate("compute")
+	if err := gate.AdmitCall("debug_traceBlockByNumber", nil); err != nil {
+		t.Fatalf("trace rejected: %v", err)
+	}
+	if err := gate.AdmitCall("eth_call", nil); err == nil {
+		t.Error("compute unit limit not enforced")
+	}
+	gate = store.gate("logs")
+	for _, tt := range []struct {
+		method string
+		params string
+		ok     bool
+	}{
+		{"eth_getLogs", `[{"fromBlock":"0x0","toBlock":"0x64"}]`, true},
+		{"eth_getLogs", `[{"fromBlock":"0x0","toBlock":"0x65"}]`, false},
+		{"eth_getLogs", `[{"fromBlock":"0x2700"}]`, true}, // 9984 to the 10000 head
+		{"eth_getLogs", `[{"fromBlock":"0x2000","toBlock":"latest"}]`, false},
+		{"eth_getLogs", `[{"fromBlock":"earliest","toBlock":"0x10"}]`, true},
+		{"eth_getLogs", `[{"blockHash":"0x0000000000000000000000000000000000000000000000000000000000000001"}]`, true},
+		{"eth_getLogs", `[{}]`, true},                                           // latest to latest
+		{"eth_getLogs", `[{"fromBlock":"safe","toBlock":"0x2774"}]`, false},     // 9995 to 10100
+		{"eth_getLogs", `[{"fromBlock":"finalized","toBlock":"0x2756"}]`, true}, // 9990 to 10070
+		{"eth_getLogs", `[{"fromBlock":"finalized:2/3","toBlock":"latest"}]`, true},
+		{"eth_getLogs", `[{"fromBlock":"0x2000","toBlock":"finalized:3"}]`, false},
+		{"eth_getLogs", `[{"fromBlock":"finalized:zero","toBlock":"latest"}]`, true}, // malformed, rejected by the method
+		{"eth_newFilter", `[{"fromBlock":"0x0","toBlock":"0x65"}]`, false},
+		{"eth_newFilter", `[{"fromBlock":"0x0","toBlock":"0x64"}]`, true},
+		{"eth_subscribe", `["logs",{"fromBlock":"0x0","toBlock":"0x65"}]`, false},
+		{"eth_subscribe", `["logs",{"address":"0x0000000000000000000000000000000000000001"}]`, true},
+		{"eth_subscribe", `["newHeads"]`, true},
+	} {
+		err := gate.AdmitCall(tt.method, json.RawMessage(tt.params))
+		if (err == nil) != tt.ok {
+			t.Errorf("%s %s: admission mismatch: have %v, want ok %v", tt.method, tt.params, err, tt.ok)
+		}
+	}
+}
+
+// Tests that modifications of the keys file are picked up, keeping the quota
+// state of the unchanged keys.
+func TestAPIKeyReload(t *testing.T) {
+	keys := []APIKey{{Key: "kept", Name: "kept", RequestRate: 1}, {Key: "removed", Name: "removed"}}
+	store, path := newTestAPIKeyStore(t, keys)
+
+	kept := store.gate("kept")
+	if kept == nil || store.gate("removed") == nil {
+		t.Fatal("keys not loaded")
+	}
+	// Clients of open connections look up their key on every call
+	client := &apiKeyClient{keys: store, key: "removed"}
+	if err := client.AdmitCall("eth_chainId", nil); err != nil {
+		t.Fatalf("call rejected: %v", err)
+	}
+	writeAPIKeys(t, path, []APIKey{keys[0], {Key: "added", Name: "added"}})
+	if err := os.Chtimes(path, time.Now(), time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.reload(); err != nil {
+		t.Fatalf("failed to reload keys: %v", err)
+	}
+	if store.gate("kept") != kept {
+		t.Error("unchanged key state not carried over")
+	}
+	if store.gate("removed") != nil {
+		t.Error("removed key still accepted")
+	}
+	if err := client.AdmitCall("eth_chainId", nil); err != errAPIKeyRevoked {
+		t.Errorf("call with removed key: error mismatch: have %v, want %v", err, errAPIKeyRevoked)
+	}
+	if store.gate("added") == nil {
+		t.Error("added key not accepted")
+	}
+	// Invalid files are rejected, keeping the current keys
+	for _, blob := range []string{
+		`[{"name":"nokey"}]`,
+		`[{"key":"a","name":"same"},{"key":"b","name":"same"}]`,
+	} {
+		if err := os.WriteFile(path, []byte(blob), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.reload(); err == nil {
+			t.Errorf("invalid keys file accepted: %s", blob)
+		}
+	}
+	if store.gate("added") == nil {
+		t.Error("keys dropped after failed reload")
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -39,6 +40,7 @@ const (
 	datadirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
+	datadirRequestLog      = "rpcrequests.log"    // Path within the datadir to the default RPC request log
 )
 
 // Config represents a small collection of configuration values to fine tune the
@@ -247,6 +249,23 @@ type Config struct {
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
+	// RPCAPIKeys is the path to the JSON file of API keys required on the HTTP and
+	// WebSocket RPC endpoints, along with the method allowlists and quotas of the
+	// keys. The file is reloaded on modification. Empty disables API keys.
+	RPCAPIKeys string `toml:",omitempty"`
+
+	// RPCRequestLog is the path of the file recording the calls served on the HTTP
+	// and WebSocket RPC endpoints. If set, the calls are recorded from startup,
+	// otherwise recording can be started at runtime into the instance directory.
+	RPCRequestLog string `toml:",omitempty"`
+
+	// RPCRequestLogSampleRate is the fraction of the calls recorded in the request log.
+	RPCRequestLogSampleRate float64 `toml:",omitempty"`
+
+	// RPCRequestLogSlow is the duration above which calls are always recorded in
+	// the request log (0 = disabled).
+	RPCRequestLogSlow time.Duration `toml:",omitempty"`
+
 	// EnablePersonal enables the deprecated personal namespace.
 	EnablePersonal bool `toml:"-"`
 
@@ -21,6 +21,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -82,6 +83,9 @@ var DefaultConfig = Config{
 	LogConfig: &LogConfig{
 		TimeFormat: &DefaultTimeFormat,
 	},
+
+	RPCRequestLogSampleRate: 0.01,
+	RPCRequestLogSlow:       time.Second,
 }
 
 // DefaultDataDir is the default data directory to use for the databases and other
@@ -68,6 +68,9 @@ type Node struct {
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
 
 	databases map[*closeTrackingDB]struct{} // All open databases
+
+	apiKeys    *apiKeyStore    // API keys required on the public HTTP and WebSocket endpoints, optional
+	requestLog *rpc.RequestLog // Log of the calls served on the public HTTP and WebSocket endpoints
 }
 
 const (
@@ -151,6 +154,11 @@ func New(conf *Config) (*Node, error) {
 		server:        &p2p.Server{Config: conf.P2P},
 		databases:     make(map[*closeTrackingDB]struct{}),
 	}
+	requestLogPath := conf.RPCRequestLog
+	if requestLogPath == "" {
+		requestLogPath = conf.ResolvePath(datadirRequestLog)
+	}
+	node.requestLog = rpc.NewRequestLog(requestLogPath)
 
 	// Register built-in APIs.
 	node.rpcAPIs = append(node.rpcAPIs, node.apis()...)
@@ -430,6 +438,20 @@ func (n *Node) startRPC() error {
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		requestLog:             n.requestLog,
+	}
+	if n.config.RPCRequestLog != "" {
+		if err := n.requestLog.Start(n.requestLogConfig()); err != nil {
+			return fmt.Errorf("failed to start RPC request log: %v", err)
+		}
+	}
+	if n.config.RPCAPIKeys != "" && (n.config.HTTPHost != "" || n.config.WSHost != "") {
+		keys, err := newAPIKeyStore(n.config.RPCAPIKeys, n.blockNumber)
+		if err != nil {
+			return fmt.Errorf("failed to load RPC API keys: %v", err)
+		}
+		keys.start()
+		n.apiKeys, rpcConfig.apiKeys = keys, keys
 	}
 
 	initHttp := func(server *httpServer, port int) error {
@@ -559,6 +581,40 @@ func (n *Node) stopRPC() {
 	n.wsAuth.stop()
 	n.ipc.stop()
 	n.stopInProc()
+
+	if n.apiKeys != nil {
+		n.apiKeys.stop()
+		n.apiKeys = nil
+	}
+	n.requestLog.Stop()
+}
+
+// requestLogConfig returns the configured selection of the calls recorded in
+// the RPC request log.
+func (n *Node) requestLogConfig() rpc.RequestLogConfig {
+	return rpc.RequestLogConfig{
+		SampleRate:    n.config.RPCRequestLogSampleRate,
+		SlowThreshold: n.config.RPCRequestLogSlow,
+	}
+}
+
+// blockNumber resolves a block tag into the number of the block through the
+// in-process RPC handler, used to resolve the block tags of queries limited by
+// API keys.
+func (n *Node) blockNumber(tag rpc.BlockNumber) (uint64, error) {
+	client := rpc.DialInProc(n.inprocHandler)
+	defer client.Close()
+
+	var header *struct {
+		Number hexutil.Uint64 `json:"number"`
+	}
+	if err := client.Call(&header, "eth_getHeaderByNumber", tag); err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block %v not found", tag)
+	}
+	return uint64(header.Number), nil
 }
 
 // startInProc registers all RPC APIs on the inproc server.
@@ -617,6 +673,15 @@ func (n *Node) RegisterAPIs(apis []rpc.API) {
 	n.rpcAPIs = append(n.rpcAPIs, apis...)
 }
 
+// APIs returns all the APIs registered on the node, including the ones
+// requiring authentication.
+func (n *Node) APIs() []rpc.API {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return slices.Clone(n.rpcAPIs)
+}
+
 // getAPIs return two sets of APIs, both the ones that do not require
 // authentication, and the complete set
 func (n *Node) getAPIs() (unauthenticated, all []rpc.API) {
@@ -59,6 +59,8 @@ type rpcEndpointConfig struct {
 	batchItemLimit         int
 	batchResponseSizeLimit int
 	httpBodyLimit          int
+	apiKeys                *apiKeyStore    // optional API keys required on requests
+	requestLog             *rpc.RequestLog // optional log of the served calls
 }
 
 type rpcHandler struct {
@@ -305,15 +307,20 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetRequestLog(config.requestLog)
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
+	var handler http.Handler = srv
+	if config.apiKeys != nil {
+		handler = newAPIKeyHandler(config.apiKeys, srv)
+	}
 	h.httpConfig = config
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
+		Handler: NewHTTPHandlerStack(handler, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
 		prefix:  config.prefix,
 		server:  srv,
 	})
@@ -341,15 +348,20 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetRequestLog(config.requestLog)
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
+	handler := srv.WebsocketHandler(config.Origins, config.messageSizeLimit)
+	if config.apiKeys != nil {
+		handler = newAPIKeyHandler(config.apiKeys, handler)
+	}
 	h.wsConfig = config
 	h.wsHandler.Store(&rpcHandler{
-		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins, config.messageSizeLimit), config.jwtSecret),
+		Handler: NewWSHandlerStack(handler, config.jwtSecret),
 		prefix:  config.prefix,
 		server:  srv,
 	})
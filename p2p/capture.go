@@ -0,0 +1,243 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// captureBuffer is the number of records buffered for writing, the records
+	// captured while the buffer is full are dropped.
+	captureBuffer = 16384
+
+	// captureRotateHours is the number of hours after which the capture file
+	// is rotated.
+	captureRotateHours = 1
+
+	// captureMaxBackups is the number of rotated capture files kept.
+	captureMaxBackups = 24
+
+	// captureFileTimeFormat is the time suffix the writer appends to the names
+	// of the rotated capture files.
+	captureFileTimeFormat = "2006-01-02_15"
+
+	// captureDropWarnInterval is the minimum time between the warnings about
+	// dropped records.
+	captureDropWarnInterval = time.Minute
+)
+
+var captureDroppedMeter = metrics.NewRegisteredMeter("p2p/capture/dropped", nil)
+
+// Capture record directions.
+const (
+	CaptureInbound  = "in"
+	CaptureOutbound = "out"
+)
+
+// CaptureProtocol is a subprotocol run in a captured session.
+type CaptureProtocol struct {
+	Name    string `json:"name"`
+	Version uint   `json:"version"`
+	Length  uint64 `json:"length"`
+}
+
+// CaptureRecord is a line of a message capture. The first record of a session
+// lists the protocols run with the peer, the following ones describe the
+// subprotocol messages exchanged, in the order they were sent or received.
+type CaptureRecord struct {
+	Time      time.Time         `json:"time"`
+	Peer      enode.ID          `json:"peer"`
+	Protocols []CaptureProtocol `json:"protocols,omitempty"` // Session start only
+
+	Direction string        `json:"dir,omitempty"`
+	Protocol  string        `json:"proto,omitempty"`
+	Code      uint64        `json:"code"`              // Message code within the protocol
+	Size      uint32        `json:"size"`              // Size of the decompressed payload
+	Elapsed   time.Duration `json:"elapsed,omitempty"` // Time to write an outbound message
+	Payload   hexutil.Bytes `json:"payload,omitempty"` // RLP payload, if payloads are captured
+}
+
+// MsgCapture records the subprotocol messages exchanged with the peers into a
+// rotated file, one JSON record per line. Records captured faster than they can
+// be written are dropped, and counted.
+type MsgCapture struct {
+	payloads bool
+	writer   *log.AsyncFileWriter
+
+	dropped  atomic.Uint64
+	lastWarn atomic.Int64 // Unix nanoseconds of the last drop warning
+}
+
+// NewMsgCapture starts capturing into the given file, with or without the
+// message payloads.
+func NewMsgCapture(path string, payloads bool) (*MsgCapture, error) {
+	writer := log.NewAsyncFileWriter(path, captureBuffer, captureMaxBackups, captureRotateHours)
+	if err := writer.Start(); err != nil {
+		return nil, err
+	}
+	return &MsgCapture{payloads: payloads, writer: writer}, nil
+}
+
+// Close stops capturing and closes the capture file.
+func (c *MsgCapture) Close() {
+	c.writer.Stop()
+	if dropped := c.dropped.Load(); dropped > 0 {
+		log.Warn("Message capture incomplete", "dropped", dropped)
+	}
+}
+
+// Dropped returns the number of records dropped because the write buffer was
+// full.
+func (c *MsgCapture) Dropped() uint64 {
+	return c.dropped.Load()
+}
+
+func (c *MsgCapture) write(record *CaptureRecord) {
+	blob, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	if _, err := c.writer.Write(append(blob, '\n')); err != nil {
+		dropped := c.dropped.Add(1)
+		captureDroppedMeter.Mark(1)
+
+		now, last := time.Now().UnixNano(), c.lastWarn.Load()
+		if now-last >= int64(captureDropWarnInterval) && c.lastWarn.CompareAndSwap(last, now) {
+			log.Warn("Dropping captured messages, write buffer full", "dropped", dropped)
+		}
+	}
+}
+
+// session records the start of a session with a peer.
+func (c *MsgCapture) session(p *Peer) {
+	if c == nil {
+		return
+	}
+	record := &CaptureRecord{Time: time.Now(), Peer: p.ID()}
+	for _, proto := range p.running {
+		record.Protocols = append(record.Protocols, CaptureProtocol{Name: proto.Name, Version: proto.Version, Length: proto.Length})
+	}
+	c.write(record)
+}
+
+// buffer reads the payload of a message if payloads are captured, returning
+// the message to pass on instead of the original along with the payload.
+func (c *MsgCapture) buffer(msg Msg) (Msg, []byte) {
+	if c == nil || !c.payloads {
+		return msg, nil
+	}
+	payload, err := io.ReadAll(msg.Payload)
+	if err != nil {
+		payload = nil
+	}
+	msg.Payload = bytes.NewReader(payload)
+	return msg, payload
+}
+
+// message records a message exchanged with a peer.
+func (c *MsgCapture) message(id enode.ID, dir string, proto Cap, code uint64, size uint32, payload []byte, elapsed time.Duration) {
+	if c == nil {
+		return
+	}
+	c.write(&CaptureRecord{
+		Time:      time.Now(),
+		Peer:      id,
+		Direction: dir,
+		Protocol:  proto.String(),
+		Code:      code,
+		Size:      size,
+		Elapsed:   elapsed,
+		Payload:   payload,
+	})
+}
+
+// ReadCapture reads the records of a capture. The capture path links to the
+// current file, the records of the rotated files kept beside it are read too,
+// from the oldest file to the newest.
+func ReadCapture(path string) ([]*CaptureRecord, error) {
+	files, err := captureFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []*CaptureRecord
+	for _, file := range files {
+		if records, err = readCaptureFile(file, records); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// captureFiles lists the rotated files of a capture in time order, or the
+// capture path itself if it was never rotated.
+func captureFiles(path string) ([]string, error) {
+	dir, name := filepath.Split(path)
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name(), name+".")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(captureFileTimeFormat, suffix); err == nil {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return []string{path}, nil
+	}
+	slices.Sort(files) // the time format sorts chronologically
+	return files, nil
+}
+
+func readCaptureFile(path string, records []*CaptureRecord) ([]*CaptureRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024) // fits the largest payloads
+	for line := 1; scanner.Scan(); line++ {
+		record := new(CaptureRecord)
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return nil, fmt.Errorf("invalid record in %s on line %d: %v", filepath.Base(path), line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
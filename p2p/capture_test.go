@@ -0,0 +1,143 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Tests that the messages exchanged with a peer are captured with their
+// protocol relative codes and payloads.
+func TestMsgCapture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.log")
+	capture, err := NewMsgCapture(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proto := Protocol{
+		Name:    "a",
+		Version: 1,
+		Length:  5,
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			msg, err := rw.ReadMsg()
+			if err != nil {
+				return err
+			}
+			var content []uint
+			if err := msg.Decode(&content); err != nil {
+				return err
+			}
+			return Send(rw, 3, content)
+		},
+	}
+	var (
+		fd1, fd2   = net.Pipe()
+		key1, key2 = newkey(), newkey()
+		c1         = &conn{fd: fd1, node: newNode(uintID(1), ""), transport: newTestTransport(&key2.PublicKey, fd1, nil), caps: []Cap{proto.cap()}}
+		c2         = &conn{fd: fd2, node: newNode(uintID(2), ""), transport: newTestTransport(&key1.PublicKey, fd2, &key1.PublicKey), caps: []Cap{proto.cap()}}
+	)
+	peer := newPeer(log.Root(), c1, []Protocol{proto})
+	peer.capture = capture
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := peer.run()
+		errc <- err
+	}()
+	if err := Send(c2, baseProtocolLength+2, []uint{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExpectMsg(c2, baseProtocolLength+3, []uint{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	c2.close(errors.New("test done"))
+	<-errc
+	capture.Close()
+
+	records, err := ReadCapture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("record count mismatch: have %d, want 3", len(records))
+	}
+	if records[0].Peer != peer.ID() || len(records[0].Protocols) != 1 || records[0].Protocols[0] != (CaptureProtocol{Name: "a", Version: 1, Length: 5}) {
+		t.Errorf("session record mismatch: %+v", records[0])
+	}
+	payload, _ := rlp.EncodeToBytes([]uint{1, 2})
+	for i, want := range []struct {
+		dir  string
+		code uint64
+	}{{CaptureInbound, 2}, {CaptureOutbound, 3}} {
+		record := records[i+1]
+		if record.Direction != want.dir || record.Protocol != "a/1" || record.Code != want.code {
+			t.Errorf("record %d mismatch: %+v", i+1, record)
+		}
+		if int(record.Size) != len(payload) || !bytes.Equal(record.Payload, payload) {
+			t.Errorf("record %d payload mismatch: have %x, want %x", i+1, record.Payload, payload)
+		}
+	}
+}
+
+// Tests that the records dropped because of a full write buffer are counted.
+func TestMsgCaptureDropped(t *testing.T) {
+	capture := &MsgCapture{writer: log.NewAsyncFileWriter(filepath.Join(t.TempDir(), "capture.log"), 1, 1, 0)}
+	for i := 0; i < 3; i++ {
+		capture.message(enode.ID{}, CaptureInbound, Cap{"a", 1}, 0, 0, nil, 0)
+	}
+	if dropped := capture.Dropped(); dropped != 2 {
+		t.Errorf("dropped count mismatch: have %d, want 2", dropped)
+	}
+}
+
+// Tests that the records of the rotated capture files are read in time order.
+func TestReadCaptureRotated(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		path = filepath.Join(dir, "capture.log")
+	)
+	files := map[string]string{
+		"capture.log.2025-01-02_00": `{"peer":"0000000000000000000000000000000000000000000000000000000000000000","code":3}`,
+		"capture.log.2025-01-01_23": `{"peer":"0000000000000000000000000000000000000000000000000000000000000000","code":2}`,
+		"capture.log.old":           `invalid`,
+		"other.log.2025-01-01_22":   `invalid`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dir, "capture.log.2025-01-02_00"), path); err != nil {
+		t.Fatal(err)
+	}
+	records, err := ReadCapture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Code != 2 || records[1].Code != 3 {
+		t.Fatalf("records mismatch: %+v", records)
+	}
+}
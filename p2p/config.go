@@ -148,6 +148,33 @@ type Config struct {
 
 	PeerFilterPatterns []string
 
+	// MessageCapture is the file into which the subprotocol messages exchanged
+	// with the peers are recorded. Nothing is recorded if empty.
+	MessageCapture string `toml:",omitempty"`
+
+	// MessageCapturePayloads includes the message payloads in the capture, as
+	// needed to replay it.
+	MessageCapturePayloads bool `toml:",omitempty"`
+
+	// Sentry runs the node as a sentry of the validators listed in ProxyedNodeIds.
+	// The protected validators are never served over discovery and are always
+	// allowed to connect.
+	Sentry bool `toml:",omitempty"`
+
+	// BehindSentry runs the node as a validator only reachable through the
+	// sentries in SentryNodes. Discovery is disabled, only the sentries are
+	// dialed and connections from any other node are refused.
+	BehindSentry bool `toml:",omitempty"`
+
+	// SentryNodes are the sentries of a node running behind sentries.
+	SentryNodes []*enode.Node `toml:",omitempty"`
+
+	// UploadBudget and DownloadBudget are the bandwidth budgets of the node in
+	// bytes per second. The eth handler cuts back the propagation fan-out and the
+	// peer count when the traffic nears them. Zero means no budget.
+	UploadBudget   uint64 `toml:",omitempty"`
+	DownloadBudget uint64 `toml:",omitempty"`
+
 	clock mclock.Clock
 }
 
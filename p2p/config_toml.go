@@ -45,6 +45,13 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		EnableMsgEvents           bool
 		Logger                    log.Logger `toml:"-"`
 		PeerFilterPatterns        []string
+		MessageCapture            string        `toml:",omitempty"`
+		MessageCapturePayloads    bool          `toml:",omitempty"`
+		Sentry                    bool          `toml:",omitempty"`
+		BehindSentry              bool          `toml:",omitempty"`
+		SentryNodes               []*enode.Node `toml:",omitempty"`
+		UploadBudget              uint64        `toml:",omitempty"`
+		DownloadBudget            uint64        `toml:",omitempty"`
 	}
 	var enc Config
 	enc.PrivateKey = c.PrivateKey
@@ -75,6 +82,13 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.EnableMsgEvents = c.EnableMsgEvents
 	enc.Logger = c.Logger
 	enc.PeerFilterPatterns = c.PeerFilterPatterns
+	enc.MessageCapture = c.MessageCapture
+	enc.MessageCapturePayloads = c.MessageCapturePayloads
+	enc.Sentry = c.Sentry
+	enc.BehindSentry = c.BehindSentry
+	enc.SentryNodes = c.SentryNodes
+	enc.UploadBudget = c.UploadBudget
+	enc.DownloadBudget = c.DownloadBudget
 	return &enc, nil
 }
 
@@ -109,6 +123,13 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		EnableMsgEvents           *bool
 		Logger                    log.Logger `toml:"-"`
 		PeerFilterPatterns        []string
+		MessageCapture            *string       `toml:",omitempty"`
+		MessageCapturePayloads    *bool         `toml:",omitempty"`
+		Sentry                    *bool         `toml:",omitempty"`
+		BehindSentry              *bool         `toml:",omitempty"`
+		SentryNodes               []*enode.Node `toml:",omitempty"`
+		UploadBudget              *uint64       `toml:",omitempty"`
+		DownloadBudget            *uint64       `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -198,5 +219,26 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.PeerFilterPatterns != nil {
 		c.PeerFilterPatterns = dec.PeerFilterPatterns
 	}
+	if dec.MessageCapture != nil {
+		c.MessageCapture = *dec.MessageCapture
+	}
+	if dec.MessageCapturePayloads != nil {
+		c.MessageCapturePayloads = *dec.MessageCapturePayloads
+	}
+	if dec.Sentry != nil {
+		c.Sentry = *dec.Sentry
+	}
+	if dec.BehindSentry != nil {
+		c.BehindSentry = *dec.BehindSentry
+	}
+	if dec.SentryNodes != nil {
+		c.SentryNodes = dec.SentryNodes
+	}
+	if dec.UploadBudget != nil {
+		c.UploadBudget = *dec.UploadBudget
+	}
+	if dec.DownloadBudget != nil {
+		c.DownloadBudget = *dec.DownloadBudget
+	}
 	return nil
 }
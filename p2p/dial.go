@@ -105,6 +105,7 @@ type dialScheduler struct {
 	remStaticCh   chan *enode.Node
 	addPeerCh     chan *conn
 	remPeerCh     chan *conn
+	limitCh       chan int
 
 	// Everything below here belongs to loop and
 	// should only be accessed by code on the loop goroutine.
@@ -177,6 +178,7 @@ func newDialScheduler(config dialConfig, it enode.Iterator, setupFunc dialSetupF
 		remStaticCh:   make(chan *enode.Node),
 		addPeerCh:     make(chan *conn),
 		remPeerCh:     make(chan *conn),
+		limitCh:       make(chan int),
 	}
 	d.lastStatsLog = d.clock.Now()
 	d.ctx, d.cancel = context.WithCancel(context.Background())
@@ -229,6 +231,15 @@ func (d *dialScheduler) peerRemoved(c *conn) {
 	}
 }
 
+// setMaxDialPeers changes the maximum number of dialed peers. Connected peers
+// above the limit are kept, no new dials are launched until below it.
+func (d *dialScheduler) setMaxDialPeers(n int) {
+	select {
+	case d.limitCh <- n:
+	case <-d.ctx.Done():
+	}
+}
+
 // loop is the main loop of the dialer.
 func (d *dialScheduler) loop(it enode.Iterator) {
 	var (
@@ -306,6 +317,10 @@ loop:
 				}
 			}
 
+		case n := <-d.limitCh:
+			d.log.Debug("Changed dialed peer limit", "old", d.maxDialPeers, "new", n)
+			d.maxDialPeers = n
+
 		case <-d.historyTimer.C():
 			d.expireHistory()
 
@@ -117,6 +117,40 @@ func TestDialSchedDynDial(t *testing.T) {
 	})
 }
 
+// This test checks that lowering the dialed peer limit stops dialing, and that
+// dialing resumes once it is raised again.
+func TestDialSchedPeerLimit(t *testing.T) {
+	t.Parallel()
+
+	config := dialConfig{
+		maxActiveDials: 5,
+		maxDialPeers:   4,
+	}
+	runDialTest(t, config, []dialTestRound{
+		// 2 out of 4 peers are connected, but the limit is lowered to 2, so none
+		// of the discovered nodes is dialed.
+		{
+			peersAdded: []*conn{
+				{flags: dynDialedConn, node: newNode(uintID(0x01), "")},
+				{flags: dynDialedConn, node: newNode(uintID(0x02), "")},
+			},
+			update: func(d *dialScheduler) { d.setMaxDialPeers(2) },
+			discovered: []*enode.Node{
+				newNode(uintID(0x03), "127.0.0.1:30303"),
+				newNode(uintID(0x04), "127.0.0.1:30303"),
+			},
+		},
+		// Raising the limit again frees 4 dial slots.
+		{
+			update: func(d *dialScheduler) { d.setMaxDialPeers(4) },
+			wantNewDials: []*enode.Node{
+				newNode(uintID(0x03), "127.0.0.1:30303"),
+				newNode(uintID(0x04), "127.0.0.1:30303"),
+			},
+		},
+	})
+}
+
 // This test checks that candidates that do not match the netrestrict list are not dialed.
 func TestDialSchedNetRestrict(t *testing.T) {
 	t.Parallel()
@@ -114,6 +114,10 @@ type Config struct {
 	ValidSchemes   enr.IdentityScheme // allowed identity schemes
 	Clock          mclock.Clock
 	IsBootnode     bool // defines if it's bootnode
+
+	// ExcludeNode reports nodes that are never added to the table, and thus
+	// never served to other nodes.
+	ExcludeNode func(enode.ID) bool
 }
 
 func (cfg Config) withDefaults() Config {
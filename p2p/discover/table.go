@@ -88,6 +88,7 @@ type Table struct {
 	closed          chan struct{}
 
 	enrFilter NodeFilterFunc
+	exclude   func(enode.ID) bool
 
 	nodeFeed        event.FeedOf[*enode.Node]
 	nodeAddedHook   func(*bucket, *tableNode)
@@ -142,6 +143,7 @@ func newTable(t transport, db *enode.DB, cfg Config) (*Table, error) {
 		closed:          make(chan struct{}),
 		ips:             netutil.DistinctNetSet{Subnet: tableSubnet, Limit: tableIPLimit},
 		enrFilter:       cfg.FilterFunction,
+		exclude:         cfg.ExcludeNode,
 		bucketSize:      bucketSize,
 	}
 	if cfg.IsBootnode {
@@ -557,6 +559,9 @@ func (tab *Table) handleAddNode(req addNodeOp) bool {
 	if req.node.ID() == tab.self().ID() {
 		return false
 	}
+	if tab.exclude != nil && tab.exclude(req.node.ID()) {
+		return false
+	}
 
 	if tab.filterNode(req.node) {
 		return false
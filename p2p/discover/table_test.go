@@ -340,6 +340,25 @@ func TestTable_addFoundNode(t *testing.T) {
 	checkBucketContent(t, tab, []*enode.Node{n1, n2v2})
 }
 
+// This test checks that excluded nodes are never added to the table.
+func TestTable_addFoundNodeExcluded(t *testing.T) {
+	var excluded enode.ID
+	tab, db := newTestTable(newPingRecorder(), Config{
+		ExcludeNode: func(id enode.ID) bool { return id == excluded },
+	})
+	<-tab.initDone
+	defer db.Close()
+	defer tab.close()
+
+	n1 := nodeAtDistance(tab.self().ID(), 256, net.IP{88, 77, 66, 1})
+	n2 := nodeAtDistance(tab.self().ID(), 256, net.IP{88, 77, 66, 2})
+	excluded = n2.ID()
+	tab.addFoundNode(n1, false)
+	tab.addFoundNode(n2, false)
+	tab.addInboundNodeSync(n2)
+	checkBucketContent(t, tab, []*enode.Node{n1})
+}
+
 // This test checks that discv4 nodes can update their own endpoint via PING.
 func TestTable_addInboundNodeUpdateV4Accept(t *testing.T) {
 	tab, db := newTestTable(newPingRecorder(), Config{})
@@ -42,6 +42,7 @@ const (
 	dbVersionKey   = "version" // Version of the database to flush if changes
 	dbNodePrefix   = "n:"      // Identifier to prefix node entries with
 	dbLocalPrefix  = "local:"
+	dbBookPrefix   = "book:" // Identifier to prefix peer book entries with
 	dbDiscoverRoot = "v4"
 	dbDiscv5Root   = "v5"
 
@@ -447,6 +448,50 @@ func (db *DB) storeLocalSeq(id ID, n uint64) {
 	db.storeUint64(localItemKey(id, dbLocalSeq), n)
 }
 
+// bookKey returns the database key of the peer book entry of a node.
+func bookKey(id ID) []byte {
+	return append([]byte(dbBookPrefix), id[:]...)
+}
+
+// PeerBookEntry retrieves the encoded peer book entry of a node, nil if the
+// node is not in the peer book. The peer book entries are maintained by the
+// p2p server and are not subject to the expiration of discovered nodes.
+func (db *DB) PeerBookEntry(id ID) []byte {
+	blob, err := db.lvl.Get(bookKey(id), nil)
+	if err != nil {
+		return nil
+	}
+	return blob
+}
+
+// UpdatePeerBookEntry stores the encoded peer book entry of a node.
+func (db *DB) UpdatePeerBookEntry(id ID, blob []byte) error {
+	return db.lvl.Put(bookKey(id), blob, nil)
+}
+
+// DeletePeerBookEntry removes the peer book entry of a node.
+func (db *DB) DeletePeerBookEntry(id ID) error {
+	return db.lvl.Delete(bookKey(id), nil)
+}
+
+// PeerBookEntries calls fn with every encoded entry of the peer book, until fn
+// returns false.
+func (db *DB) PeerBookEntries(fn func(id ID, blob []byte) bool) {
+	it := db.lvl.NewIterator(util.BytesPrefix([]byte(dbBookPrefix)), nil)
+	defer it.Release()
+
+	for it.Next() {
+		var id ID
+		if len(it.Key()) != len(dbBookPrefix)+len(id) {
+			continue
+		}
+		copy(id[:], it.Key()[len(dbBookPrefix):])
+		if !fn(id, it.Value()) {
+			return
+		}
+	}
+}
+
 // QuerySeeds retrieves random nodes to be used as potential seed nodes
 // for bootstrapping.
 func (db *DB) QuerySeeds(n int, maxAge time.Duration) []*Node {
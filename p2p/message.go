@@ -42,9 +42,10 @@ type Msg struct {
 	Payload    io.Reader
 	ReceivedAt time.Time
 
-	meterCap  Cap    // Protocol name and version for egress metering
-	meterCode uint64 // Message within protocol for egress metering
-	meterSize uint32 // Compressed message size for ingress metering
+	meterCap     Cap           // Protocol name and version for egress metering
+	meterCode    uint64        // Message within protocol for egress metering
+	meterSize    uint32        // Compressed message size for ingress metering
+	meterTraffic *trafficStats // Traffic counters of the sending server for egress accounting
 }
 
 // Decode parses the RLP content of a message into
@@ -168,6 +168,14 @@ func (t *Tracker) Capacity(kind uint64, targetRTT time.Duration) int {
 	return roundCapacity(1 + capacityOverestimation*throughput)
 }
 
+// RoundTrip returns the estimated time the peer takes to respond to data requests.
+func (t *Tracker) RoundTrip() time.Duration {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.roundtrip
+}
+
 // roundCapacity gives the integer value of a capacity.
 // The result fits int32, and is guaranteed to be positive.
 func roundCapacity(cap float64) int {
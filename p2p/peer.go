@@ -124,8 +124,18 @@ type Peer struct {
 	testPipe       *MsgPipeRW // for testing
 	testRemoteAddr string     // for testing
 
+	// capture records the exchanged subprotocol messages if set
+	capture *MsgCapture
+
+	// traffic counts the exchanged subprotocol traffic if set
+	traffic *trafficStats
+
 	latency atomic.Int64 // mill second latency, estimated by ping msg
 
+	// Quality measurements reported by the subprotocols, recorded in the peer book.
+	roundtrip    atomic.Int64  // Request round trip time measured by the subprotocols
+	usefulBlocks atomic.Uint64 // Number of new blocks first delivered by the peer
+
 	// it indicates the peer is in the validator network, it will directly broadcast when miner/sentry broadcast mined block,
 	// and won't broadcast any txs between EVN peers.
 	EVNPeerFlag atomic.Bool
@@ -282,6 +292,19 @@ func (p *Peer) StaticDialed() bool {
 	return p.rw.is(staticDialedConn)
 }
 
+// RecordRoundTrip reports the round trip time of the requests served by the
+// peer, as measured by a subprotocol. It is recorded in the peer book when the
+// peer disconnects.
+func (p *Peer) RecordRoundTrip(rtt time.Duration) {
+	p.roundtrip.Store(int64(rtt))
+}
+
+// RecordUsefulBlock reports that the peer was the first to deliver a new block.
+// The count is recorded in the peer book when the peer disconnects.
+func (p *Peer) RecordUsefulBlock() {
+	p.usefulBlocks.Add(1)
+}
+
 // Lifetime returns the time since peer creation.
 func (p *Peer) Lifetime() mclock.AbsTime {
 	return mclock.Now() - p.created
@@ -448,11 +471,16 @@ func (p *Peer) handle(msg Msg) error {
 		if err != nil {
 			return fmt.Errorf("msg code out of range: %v", msg.Code)
 		}
+		p.traffic.markIngress(proto.cap(), msg.meterSize)
 		if metrics.Enabled() {
 			m := fmt.Sprintf("%s/%s/%d/%#02x", ingressMeterName, proto.Name, proto.Version, msg.Code-proto.offset)
 			metrics.GetOrRegisterMeter(m, nil).Mark(int64(msg.meterSize))
 			metrics.GetOrRegisterMeter(m+"/packets", nil).Mark(1)
 		}
+		var payload []byte
+		msg, payload = p.capture.buffer(msg)
+		p.capture.message(p.ID(), CaptureInbound, proto.cap(), msg.Code-proto.offset, msg.Size, payload, 0)
+
 		select {
 		case proto.in <- msg:
 			return nil
@@ -523,10 +551,14 @@ outer:
 
 func (p *Peer) startProtocols(writeStart <-chan struct{}, writeErr chan<- error) {
 	p.wg.Add(len(p.running))
+	p.capture.session(p)
 	for _, proto := range p.running {
 		proto.closed = p.closed
 		proto.wstart = writeStart
 		proto.werr = writeErr
+		proto.capture = p.capture
+		proto.traffic = p.traffic
+		proto.peer = p.ID()
 		var rw MsgReadWriter = proto
 		if p.events != nil {
 			rw = newMsgEventer(rw, p.events, p.ID(), proto.Name, p.Info().Network.RemoteAddress, p.Info().Network.LocalAddress)
@@ -565,6 +597,10 @@ type protoRW struct {
 	werr   chan<- error    // for write results
 	offset uint64
 	w      MsgWriter
+
+	capture *MsgCapture   // records the written messages if set
+	traffic *trafficStats // counts the written traffic if set
+	peer    enode.ID
 }
 
 func (rw *protoRW) WriteMsg(msg Msg) (err error) {
@@ -573,12 +609,20 @@ func (rw *protoRW) WriteMsg(msg Msg) (err error) {
 	}
 	msg.meterCap = rw.cap()
 	msg.meterCode = msg.Code
+	msg.meterTraffic = rw.traffic
 
 	msg.Code += rw.offset
 
+	var payload []byte
+	msg, payload = rw.capture.buffer(msg)
+
 	select {
 	case <-rw.wstart:
+		start := time.Now()
 		err = rw.w.WriteMsg(msg)
+		if err == nil {
+			rw.capture.message(rw.peer, CaptureOutbound, rw.cap(), msg.meterCode, msg.Size, payload, time.Since(start))
+		}
 		// Report write status back to Peer.run. It will initiate
 		// shutdown if the error is non-nil and unblock the next write
 		// otherwise. The calling protocol code should exit for errors
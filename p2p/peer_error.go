@@ -70,6 +70,7 @@ const (
 	DiscSelf
 	DiscReadTimeout
 	DiscSubprotocolError = DiscReason(0x10)
+	DiscBandwidthBudget  = DiscReason(0x11)
 
 	DiscInvalid = 0xff
 )
@@ -88,6 +89,7 @@ var discReasonToString = [...]string{
 	DiscSelf:                "connected to self",
 	DiscReadTimeout:         "read timeout",
 	DiscSubprotocolError:    "subprotocol error",
+	DiscBandwidthBudget:     "bandwidth budget exceeded",
 	DiscInvalid:             "invalid disconnect reason",
 }
 
@@ -0,0 +1,378 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// peerBookLimit is the number of peers kept in the peer book. When exceeded
+	// by a tenth, the lowest scoring peers are pruned.
+	peerBookLimit = 1000
+
+	// peerBookLatencyImpact is the impact of the latency of a new session on the
+	// recorded latency of a peer.
+	peerBookLatencyImpact = 0.3
+
+	// peerBookQueueSize is the number of finished sessions waiting to be written
+	// to the peer book. Sessions ending while the queue is full are not recorded.
+	peerBookQueueSize = 256
+)
+
+// PeerBookDisconnect counts the disconnects of a peer for a reason.
+type PeerBookDisconnect struct {
+	Reason string `json:"reason"`
+	Count  uint64 `json:"count"`
+}
+
+// PeerBookEntry is the connection history of a peer, as recorded in the peer
+// book across restarts.
+type PeerBookEntry struct {
+	ID           enode.ID             `json:"id"`
+	Enode        string               `json:"enode,omitempty"` // Dialable node URL, if the peer was ever dialed
+	Protocols    []string             `json:"protocols"`       // Protocols of the last session
+	FirstSeen    time.Time            `json:"firstSeen"`
+	LastSeen     time.Time            `json:"lastSeen"`
+	Sessions     uint64               `json:"sessions"`
+	Uptime       time.Duration        `json:"uptime"`       // Total connection time over all sessions
+	Latency      time.Duration        `json:"latency"`      // Average request round trip time, 0 if unknown
+	UsefulBlocks uint64               `json:"usefulBlocks"` // New blocks first delivered by the peer
+	Failures     uint64               `json:"failures"`     // Sessions ended by peer misbehaviour or network errors
+	Disconnects  []PeerBookDisconnect `json:"disconnects"`
+	Score        float64              `json:"score"`
+
+	node *enode.Node
+}
+
+// score rates the history of the peer, higher is better. Connection time and
+// useful blocks increase the score, latency and failures decrease it.
+func (e *PeerBookEntry) score() float64 {
+	score := math.Log1p(e.Uptime.Hours()) + math.Log1p(float64(e.UsefulBlocks))
+	score -= e.Latency.Seconds()
+	score -= math.Log1p(float64(e.Failures))
+	return score
+}
+
+// storedPeerBookEntry is the database encoding of a peer book entry.
+type storedPeerBookEntry struct {
+	Node         string // Dialable node URL, empty if the peer was never dialed
+	Protocols    []string
+	FirstSeen    uint64
+	LastSeen     uint64
+	Sessions     uint64
+	Uptime       uint64
+	Latency      uint64
+	UsefulBlocks uint64
+	Failures     uint64
+	Disconnects  []PeerBookDisconnect
+}
+
+// peerBookSession is a finished session of a peer, captured when the peer is
+// removed so the peer book can record it in the background.
+type peerBookSession struct {
+	id           enode.ID
+	node         *enode.Node // Dialable record, nil for inbound sessions
+	protocols    []string
+	uptime       time.Duration
+	usefulBlocks uint64
+	rtt          time.Duration
+	reason       DiscReason
+}
+
+// peerBook maintains the connection history of the peers in the node database,
+// to prefer the historically good peers when dialing after a restart. The
+// database writes happen on a background goroutine, off the server loop.
+type peerBook struct {
+	db    *enode.DB
+	count int // Number of entries in the book, estimated since the last prune
+	log   log.Logger
+
+	queue chan peerBookSession
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newPeerBook(db *enode.DB, log log.Logger) *peerBook {
+	book := &peerBook{
+		db:    db,
+		log:   log,
+		queue: make(chan peerBookSession, peerBookQueueSize),
+		quit:  make(chan struct{}),
+	}
+	book.wg.Add(1)
+	go book.loop()
+	return book
+}
+
+// close stops the background writer after recording the queued sessions.
+func (b *peerBook) close() {
+	close(b.quit)
+	b.wg.Wait()
+}
+
+// loop writes the finished sessions to the database and prunes the book when
+// it grows beyond its limit.
+func (b *peerBook) loop() {
+	defer b.wg.Done()
+
+	b.prune()
+	for {
+		select {
+		case s := <-b.queue:
+			b.store(s)
+		case <-b.quit:
+			for {
+				select {
+				case s := <-b.queue:
+					b.store(s)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// entry retrieves the recorded history of a peer, nil if unknown.
+func (b *peerBook) entry(id enode.ID) *PeerBookEntry {
+	blob := b.db.PeerBookEntry(id)
+	if blob == nil {
+		return nil
+	}
+	return decodePeerBookEntry(id, blob)
+}
+
+func decodePeerBookEntry(id enode.ID, blob []byte) *PeerBookEntry {
+	var stored storedPeerBookEntry
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		return nil
+	}
+	entry := &PeerBookEntry{
+		ID:           id,
+		Protocols:    stored.Protocols,
+		FirstSeen:    time.Unix(int64(stored.FirstSeen), 0),
+		LastSeen:     time.Unix(int64(stored.LastSeen), 0),
+		Sessions:     stored.Sessions,
+		Uptime:       time.Duration(stored.Uptime) * time.Second,
+		Latency:      time.Duration(stored.Latency) * time.Millisecond,
+		UsefulBlocks: stored.UsefulBlocks,
+		Failures:     stored.Failures,
+		Disconnects:  stored.Disconnects,
+	}
+	if stored.Node != "" {
+		if node, err := enode.Parse(enode.ValidSchemes, stored.Node); err == nil && node.ID() == id {
+			entry.node, entry.Enode = node, node.String()
+		}
+	}
+	entry.Score = entry.score()
+	return entry
+}
+
+func (b *peerBook) write(entry *PeerBookEntry) error {
+	stored := storedPeerBookEntry{
+		Protocols:    entry.Protocols,
+		FirstSeen:    uint64(entry.FirstSeen.Unix()),
+		LastSeen:     uint64(entry.LastSeen.Unix()),
+		Sessions:     entry.Sessions,
+		Uptime:       uint64(entry.Uptime / time.Second),
+		Latency:      uint64(entry.Latency / time.Millisecond),
+		UsefulBlocks: entry.UsefulBlocks,
+		Failures:     entry.Failures,
+		Disconnects:  entry.Disconnects,
+	}
+	if entry.node != nil {
+		stored.Node = entry.node.String()
+	}
+	blob, err := rlp.EncodeToBytes(&stored)
+	if err != nil {
+		return err
+	}
+	return b.db.UpdatePeerBookEntry(entry.ID, blob)
+}
+
+// record queues a finished session of a peer for adding to its history. The
+// session is dropped if the queue is full.
+func (b *peerBook) record(p *Peer, err error) {
+	s := peerBookSession{
+		id:           p.ID(),
+		uptime:       time.Duration(mclock.Now() - p.created),
+		usefulBlocks: p.usefulBlocks.Load(),
+		rtt:          time.Duration(p.roundtrip.Load()),
+		reason:       peerBookReason(err),
+	}
+	// Only dialed sessions carry a record with the listening endpoint
+	if !p.Inbound() {
+		s.node = p.Node()
+	}
+	for _, c := range p.Caps() {
+		s.protocols = append(s.protocols, c.String())
+	}
+	if s.rtt == 0 {
+		s.rtt = time.Duration(p.latency.Load()) * time.Millisecond
+	}
+	select {
+	case b.queue <- s:
+	default:
+		b.log.Debug("Peer book queue full, dropping session", "id", s.id)
+	}
+}
+
+// store adds a finished session of a peer to its history.
+func (b *peerBook) store(s peerBookSession) {
+	now := time.Now()
+	entry := b.entry(s.id)
+	if entry == nil {
+		entry = &PeerBookEntry{ID: s.id, FirstSeen: now}
+		b.count++
+	}
+	if s.node != nil {
+		entry.node = s.node
+	}
+	entry.Protocols = s.protocols
+	entry.LastSeen = now
+	entry.Sessions++
+	entry.Uptime += s.uptime
+	entry.UsefulBlocks += s.usefulBlocks
+
+	if s.rtt > 0 {
+		if entry.Latency == 0 {
+			entry.Latency = s.rtt
+		} else {
+			entry.Latency = time.Duration((1-peerBookLatencyImpact)*float64(entry.Latency) + peerBookLatencyImpact*float64(s.rtt))
+		}
+	}
+	switch s.reason {
+	case DiscRequested, DiscTooManyPeers, DiscAlreadyConnected, DiscQuitting, DiscSelf, DiscBandwidthBudget:
+	default:
+		entry.Failures++
+	}
+	if i := slices.IndexFunc(entry.Disconnects, func(d PeerBookDisconnect) bool { return d.Reason == s.reason.String() }); i >= 0 {
+		entry.Disconnects[i].Count++
+	} else {
+		entry.Disconnects = append(entry.Disconnects, PeerBookDisconnect{Reason: s.reason.String(), Count: 1})
+	}
+	if err := b.write(entry); err != nil {
+		b.log.Warn("Failed to record peer in peer book", "id", s.id, "err", err)
+	}
+	if b.count > peerBookLimit+peerBookLimit/10 {
+		b.prune()
+	}
+}
+
+// peerBookReason classifies the error ending a session into a disconnect reason.
+func peerBookReason(err error) DiscReason {
+	var (
+		reason DiscReason
+		netErr net.Error
+	)
+	switch {
+	case errors.As(err, &reason):
+		return reason
+	case errors.Is(err, io.EOF) || errors.As(err, &netErr):
+		return DiscNetworkError
+	default:
+		return discReasonForError(err)
+	}
+}
+
+// entries returns the recorded peers, from the best scoring to the worst.
+func (b *peerBook) entries() []*PeerBookEntry {
+	var entries []*PeerBookEntry
+	b.db.PeerBookEntries(func(id enode.ID, blob []byte) bool {
+		if entry := decodePeerBookEntry(id, blob); entry != nil {
+			entries = append(entries, entry)
+		}
+		return true
+	})
+	slices.SortStableFunc(entries, func(a, b *PeerBookEntry) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return entries
+}
+
+// bestNodes returns the dialable records of the n best scoring peers.
+func (b *peerBook) bestNodes(n int) []*enode.Node {
+	var nodes []*enode.Node
+	for _, entry := range b.entries() {
+		if len(nodes) >= n {
+			break
+		}
+		if entry.node != nil && entry.Failures < entry.Sessions {
+			nodes = append(nodes, entry.node)
+		}
+	}
+	return nodes
+}
+
+// prune drops the lowest scoring peers exceeding the size limit of the book.
+func (b *peerBook) prune() {
+	entries := b.entries()
+	for _, entry := range entries[min(len(entries), peerBookLimit):] {
+		b.db.DeletePeerBookEntry(entry.ID)
+	}
+	b.count = min(len(entries), peerBookLimit)
+}
+
+// prependIter yields the nodes of a first iterator, then those of another.
+type prependIter struct {
+	first enode.Iterator // nil once exhausted
+	rest  enode.Iterator
+}
+
+func (it *prependIter) Next() bool {
+	if it.first != nil {
+		if it.first.Next() {
+			return true
+		}
+		it.first.Close()
+		it.first = nil
+	}
+	return it.rest.Next()
+}
+
+func (it *prependIter) Node() *enode.Node {
+	if it.first != nil {
+		return it.first.Node()
+	}
+	return it.rest.Node()
+}
+
+func (it *prependIter) Close() {
+	if it.first != nil {
+		it.first.Close()
+	}
+	it.rest.Close()
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// newPeerBookTestPeer creates a peer connected for the given duration.
+func newPeerBookTestPeer(t *testing.T, inbound bool, uptime time.Duration) *Peer {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &conn{
+		node: enode.NewV4(&key.PublicKey, net.IP{10, 0, 0, 1}, 30303, 30303),
+		caps: []Cap{{"eth", 68}},
+	}
+	if inbound {
+		c.set(inboundConn, true)
+	}
+	p := newPeer(log.Root(), c, nil)
+	p.created = mclock.Now() - mclock.AbsTime(uptime)
+	return p
+}
+
+// Tests that the peer book records the history of the peers across restarts,
+// and ranks the peers by their history.
+func TestPeerBook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes")
+	db, err := enode.OpenDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	book := newPeerBook(db, log.Root())
+
+	var (
+		good    = newPeerBookTestPeer(t, false, 2*time.Hour)
+		bad     = newPeerBookTestPeer(t, false, time.Minute)
+		inbound = newPeerBookTestPeer(t, true, time.Hour)
+	)
+	good.RecordRoundTrip(100 * time.Millisecond)
+	good.RecordUsefulBlock()
+	good.RecordUsefulBlock()
+	book.record(good, DiscRequested)
+
+	bad.RecordRoundTrip(2 * time.Second)
+	book.record(bad, DiscProtocolError)
+	book.record(bad, io.EOF)
+
+	book.record(inbound, errors.New("unknown"))
+	book.close()
+	db.Close()
+
+	// Reopen the database and check the recorded history
+	if db, err = enode.OpenDB(path); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	book = newPeerBook(db, log.Root())
+	defer book.close()
+
+	entries := book.entries()
+	if len(entries) != 3 {
+		t.Fatalf("entry count mismatch: have %d, want 3", len(entries))
+	}
+	if entries[0].ID != good.ID() || entries[2].ID != bad.ID() {
+		t.Errorf("ranking mismatch: have %v, %v, %v", entries[0].ID, entries[1].ID, entries[2].ID)
+	}
+	entry := entries[0]
+	if entry.Sessions != 1 || entry.Failures != 0 || entry.UsefulBlocks != 2 || entry.Latency != 100*time.Millisecond {
+		t.Errorf("good peer history mismatch: %+v", entry)
+	}
+	if entry.Uptime < 2*time.Hour || entry.Enode != good.Node().String() || len(entry.Protocols) != 1 || entry.Protocols[0] != "eth/68" {
+		t.Errorf("good peer session mismatch: %+v", entry)
+	}
+	entry = entries[2]
+	if entry.Sessions != 2 || entry.Failures != 2 {
+		t.Errorf("bad peer history mismatch: %+v", entry)
+	}
+	if len(entry.Disconnects) != 2 || entry.Disconnects[0].Reason != DiscProtocolError.String() || entry.Disconnects[1].Reason != DiscNetworkError.String() {
+		t.Errorf("bad peer disconnects mismatch: %+v", entry.Disconnects)
+	}
+	// Only the reliable dialed peers are redialed
+	if entries[1].Enode != "" {
+		t.Errorf("inbound peer has dialable record: %s", entries[1].Enode)
+	}
+	nodes := book.bestNodes(10)
+	if len(nodes) != 1 || nodes[0].ID() != good.ID() {
+		t.Errorf("redialed nodes mismatch: %v", nodes)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// sentryResolveInterval is the minimum time between two DNS lookups of the
+	// sentry hostnames, made when an inbound connection comes from an unknown
+	// address.
+	sentryResolveInterval = 30 * time.Second
+
+	// sentryResolveTimeout bounds the DNS lookup of a sentry hostname.
+	sentryResolveTimeout = 5 * time.Second
+)
+
+var (
+	errNotSentry        = errors.New("not a sentry node")
+	errNotSentryAddress = errors.New("not a sentry address")
+)
+
+// sentryMode holds the peer restrictions of the sentry modes. The server
+// configuration is left untouched, the restrictions are derived from it.
+type sentryMode struct {
+	sentries  map[enode.ID]struct{}   // Sentries of a node behind sentries
+	addrs     map[netip.Addr]struct{} // Addresses of the sentries with an IP
+	hostnames []string                // DNS names of the sentries without an IP
+	protected map[enode.ID]struct{}   // Validators protected by a sentry
+
+	lookup       func(ctx context.Context, network string, name string) ([]netip.Addr, error)
+	clock        mclock.Clock
+	resolveLock  sync.Mutex
+	resolved     map[netip.Addr]struct{} // Addresses resolved from the hostnames
+	resolveAfter mclock.AbsTime          // Time of the next allowed lookup
+}
+
+// setupSentryMode validates the sentry mode configuration. A node behind
+// sentries runs without discovery and only dials and accepts its sentries, a
+// sentry hides the validators it protects from discovery.
+func (srv *Server) setupSentryMode() error {
+	switch {
+	case srv.Sentry && srv.BehindSentry:
+		return errors.New("sentry and behind-sentry modes are mutually exclusive")
+
+	case srv.BehindSentry:
+		if len(srv.SentryNodes) == 0 {
+			return errors.New("behind-sentry mode needs at least one sentry node")
+		}
+		mode := &sentryMode{
+			sentries: make(map[enode.ID]struct{}),
+			addrs:    make(map[netip.Addr]struct{}),
+			lookup:   net.DefaultResolver.LookupNetIP,
+			clock:    srv.clock,
+		}
+		for _, n := range srv.SentryNodes {
+			mode.sentries[n.ID()] = struct{}{}
+			switch {
+			case n.IPAddr().IsValid():
+				mode.addrs[n.IPAddr()] = struct{}{}
+			case n.Hostname() != "":
+				mode.hostnames = append(mode.hostnames, n.Hostname())
+			}
+		}
+		for _, n := range srv.StaticNodes {
+			if _, ok := mode.sentries[n.ID()]; !ok {
+				srv.log.Warn("Ignoring static node behind sentries", "id", n.ID())
+			}
+		}
+		srv.sentry = mode
+		srv.log.Info("Running behind sentries", "sentries", len(mode.sentries))
+
+	case srv.Sentry:
+		mode := &sentryMode{protected: make(map[enode.ID]struct{})}
+		for _, id := range srv.ProxyedNodeIds {
+			mode.protected[id] = struct{}{}
+		}
+		if len(mode.protected) == 0 {
+			srv.log.Warn("Running as sentry without protected validators, list them in ProxyedNodeIds")
+		}
+		srv.sentry = mode
+		srv.log.Info("Running as sentry", "protected", len(mode.protected))
+	}
+	return nil
+}
+
+// staticNodes returns the nodes to keep connected: the sentries of a node
+// behind sentries, or the configured static nodes.
+func (srv *Server) staticNodes() []*enode.Node {
+	if srv.sentry.behind() {
+		return srv.SentryNodes
+	}
+	return srv.StaticNodes
+}
+
+// behind reports whether the node runs behind sentries.
+func (m *sentryMode) behind() bool {
+	return m != nil && m.sentries != nil
+}
+
+// trusted returns the nodes always allowed to connect: the sentries of a node
+// behind sentries, or the validators protected by a sentry.
+func (m *sentryMode) trusted() []enode.ID {
+	if m == nil {
+		return nil
+	}
+	ids := make([]enode.ID, 0, len(m.sentries)+len(m.protected))
+	for id := range m.sentries {
+		ids = append(ids, id)
+	}
+	for id := range m.protected {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// hidden reports whether a node must not be served over discovery.
+func (m *sentryMode) hidden(id enode.ID) bool {
+	_, ok := m.protected[id]
+	return ok
+}
+
+// checkAddr checks whether an inbound connection may come from the address.
+// Nodes behind sentries only accept connections from the sentry addresses,
+// the sentries named by DNS are resolved when needed.
+func (m *sentryMode) checkAddr(ip netip.Addr) error {
+	if !m.behind() {
+		return nil
+	}
+	ip = ip.Unmap()
+	if _, ok := m.addrs[ip]; ok {
+		return nil
+	}
+	if len(m.hostnames) == 0 {
+		return errNotSentryAddress
+	}
+	m.resolveLock.Lock()
+	defer m.resolveLock.Unlock()
+
+	if _, ok := m.resolved[ip]; !ok && m.clock.Now() >= m.resolveAfter {
+		m.resolve()
+	}
+	if _, ok := m.resolved[ip]; !ok {
+		return errNotSentryAddress
+	}
+	return nil
+}
+
+// resolve looks up the addresses of the sentries named by DNS. If a lookup
+// fails, the previously resolved addresses are kept as well.
+func (m *sentryMode) resolve() {
+	m.resolveAfter = m.clock.Now().Add(sentryResolveInterval)
+
+	var (
+		resolved = make(map[netip.Addr]struct{})
+		failed   bool
+	)
+	for _, name := range m.hostnames {
+		ctx, cancel := context.WithTimeout(context.Background(), sentryResolveTimeout)
+		ips, err := m.lookup(ctx, "ip", name)
+		cancel()
+		if err != nil {
+			failed = true
+			continue
+		}
+		for _, ip := range ips {
+			resolved[ip.Unmap()] = struct{}{}
+		}
+	}
+	if failed {
+		for ip := range m.resolved {
+			resolved[ip] = struct{}{}
+		}
+	}
+	m.resolved = resolved
+}
+
+// checkPeer checks whether a node may become a peer. Nodes behind sentries only
+// accept their sentries as peers.
+func (m *sentryMode) checkPeer(id enode.ID) error {
+	if !m.behind() {
+		return nil
+	}
+	if _, ok := m.sentries[id]; !ok {
+		return errNotSentry
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"net"
+	"net/netip"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// This test checks that a node behind sentries only peers with its sentries.
+func TestServerBehindSentry(t *testing.T) {
+	sentryKey, otherKey := newkey(), newkey()
+	sentry := enode.NewV4(&sentryKey.PublicKey, net.ParseIP("10.0.0.1"), 30303, 30303)
+	other := enode.NewV4(&otherKey.PublicKey, net.ParseIP("10.0.0.2"), 30303, 30303)
+
+	var tp *setupTransport
+	srv := &Server{
+		Config: Config{
+			PrivateKey:   newkey(),
+			MaxPeers:     10,
+			NoDial:       true,
+			BehindSentry: true,
+			SentryNodes:  []*enode.Node{sentry},
+			StaticNodes:  []*enode.Node{other},
+			DiscoveryV4:  true,
+			Protocols:    []Protocol{discard},
+			Logger:       testlog.Logger(t, log.LvlTrace),
+		},
+		newTransport: func(fd net.Conn, dialDest *ecdsa.PublicKey) transport { return tp },
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("couldn't start server: %v", err)
+	}
+	defer srv.Stop()
+
+	if srv.DiscoveryV4() != nil {
+		t.Error("discovery not disabled behind sentries")
+	}
+	if static := srv.staticNodes(); len(static) != 1 || static[0].ID() != sentry.ID() {
+		t.Errorf("static nodes mismatch: %v", static)
+	}
+	// The configuration is left as given
+	if srv.NoDiscovery || !srv.Config.DiscoveryV4 || len(srv.StaticNodes) != 1 || srv.StaticNodes[0].ID() != other.ID() {
+		t.Errorf("configuration rewritten: %+v", srv.Config)
+	}
+	// Connections from anyone but the sentries are rejected.
+	for _, test := range []struct {
+		key  *ecdsa.PrivateKey
+		node *enode.Node
+		want error
+	}{
+		{key: otherKey, node: other, want: errNotSentry},
+		{key: sentryKey, node: sentry, want: DiscUselessPeer},
+	} {
+		tp = &setupTransport{
+			pubkey: &test.key.PublicKey,
+			phs:    protoHandshake{ID: crypto.FromECDSAPub(&test.key.PublicKey)[1:]},
+		}
+		conn, _ := net.Pipe()
+		srv.SetupConn(conn, dynDialedConn, test.node)
+		if tp.closeErr != test.want {
+			t.Errorf("node %v: close error mismatch: have %q, want %q", test.node.ID(), tp.closeErr, test.want)
+		}
+		conn.Close()
+	}
+	if err := srv.checkInboundConn(netip.MustParseAddr("10.0.0.2")); err != errNotSentryAddress {
+		t.Errorf("inbound from non-sentry address: have %v, want %v", err, errNotSentryAddress)
+	}
+	if err := srv.checkInboundConn(netip.MustParseAddr("10.0.0.1")); err != nil {
+		t.Errorf("inbound from sentry address rejected: %v", err)
+	}
+}
+
+// This test checks that a sentry trusts and hides the validators it protects.
+func TestServerSentry(t *testing.T) {
+	validator, other := randomID(), randomID()
+	srv := &Server{
+		Config: Config{
+			PrivateKey:     newkey(),
+			MaxPeers:       10,
+			NoDial:         true,
+			NoDiscovery:    true,
+			Sentry:         true,
+			ProxyedNodeIds: []enode.ID{validator},
+			Logger:         testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("couldn't start server: %v", err)
+	}
+	defer srv.Stop()
+
+	if !slices.Contains(srv.sentry.trusted(), validator) {
+		t.Error("protected validator not trusted")
+	}
+	if !srv.sentry.hidden(validator) || srv.sentry.hidden(other) {
+		t.Error("hidden nodes mismatch")
+	}
+	if err := srv.sentry.checkPeer(other); err != nil {
+		t.Errorf("sentry rejected peer: %v", err)
+	}
+}
+
+func TestServerSentryConfig(t *testing.T) {
+	sentry := enode.NewV4(&newkey().PublicKey, net.ParseIP("10.0.0.1"), 30303, 30303)
+	for _, cfg := range []Config{
+		{Sentry: true, BehindSentry: true, SentryNodes: []*enode.Node{sentry}},
+		{BehindSentry: true},
+	} {
+		cfg.PrivateKey = newkey()
+		cfg.MaxPeers = 10
+		cfg.NoDial = true
+		cfg.Logger = testlog.Logger(t, log.LvlTrace)
+		srv := &Server{Config: cfg}
+		if err := srv.Start(); err == nil {
+			srv.Stop()
+			t.Errorf("config %+v: expected error", cfg)
+		}
+	}
+}
+
+// This test checks that the inbound connections of the sentries named by DNS
+// are accepted, resolving the names again when a sentry address is unknown.
+func TestSentryCheckAddrDNS(t *testing.T) {
+	var (
+		clock   = new(mclock.Simulated)
+		lookups int
+		addrs   = []netip.Addr{netip.MustParseAddr("10.0.0.1")}
+	)
+	mode := &sentryMode{
+		sentries:  map[enode.ID]struct{}{{1}: {}},
+		hostnames: []string{"sentry.example.org"},
+		clock:     clock,
+		lookup: func(ctx context.Context, network string, name string) ([]netip.Addr, error) {
+			if name != "sentry.example.org" {
+				return nil, errors.New("unknown host")
+			}
+			lookups++
+			return addrs, nil
+		},
+	}
+	if err := mode.checkAddr(netip.MustParseAddr("10.0.0.1")); err != nil {
+		t.Fatalf("inbound from resolved sentry address rejected: %v", err)
+	}
+	if err := mode.checkAddr(netip.MustParseAddr("::ffff:10.0.0.1")); err != nil {
+		t.Fatalf("inbound from mapped sentry address rejected: %v", err)
+	}
+	if lookups != 1 {
+		t.Fatalf("lookup count mismatch: have %d, want 1", lookups)
+	}
+	// The sentry moves, its new address is only resolved after the interval
+	addrs = []netip.Addr{netip.MustParseAddr("10.0.0.2")}
+	if err := mode.checkAddr(netip.MustParseAddr("10.0.0.2")); err != errNotSentryAddress {
+		t.Fatalf("inbound before re-resolving: have %v, want %v", err, errNotSentryAddress)
+	}
+	clock.Run(sentryResolveInterval)
+	if err := mode.checkAddr(netip.MustParseAddr("10.0.0.2")); err != nil {
+		t.Fatalf("inbound from moved sentry rejected: %v", err)
+	}
+	if err := mode.checkAddr(netip.MustParseAddr("10.0.0.1")); err != errNotSentryAddress {
+		t.Fatalf("inbound from old sentry address: have %v, want %v", err, errNotSentryAddress)
+	}
+	if lookups != 2 {
+		t.Fatalf("lookup count mismatch: have %d, want 2", lookups)
+	}
+}
+
+// relayNode runs a protocol which records the messages it receives and, on a
+// sentry, relays them to all other peers.
+type relayNode struct {
+	relay bool
+	lock  sync.Mutex
+	peers map[enode.ID]MsgReadWriter
+	recv  chan string
+}
+
+func newRelayNode(relay bool) *relayNode {
+	return &relayNode{relay: relay, peers: make(map[enode.ID]MsgReadWriter), recv: make(chan string, 10)}
+}
+
+func (n *relayNode) protocol() Protocol {
+	return Protocol{
+		Name:    "relay",
+		Version: 1,
+		Length:  1,
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			n.lock.Lock()
+			n.peers[peer.ID()] = rw
+			n.lock.Unlock()
+
+			defer func() {
+				n.lock.Lock()
+				delete(n.peers, peer.ID())
+				n.lock.Unlock()
+			}()
+			for {
+				msg, err := rw.ReadMsg()
+				if err != nil {
+					return err
+				}
+				var text string
+				if err := msg.Decode(&text); err != nil {
+					return err
+				}
+				n.recv <- text
+				if n.relay {
+					n.lock.Lock()
+					for id, other := range n.peers {
+						if id != peer.ID() {
+							go Send(other, 0, text)
+						}
+					}
+					n.lock.Unlock()
+				}
+			}
+		},
+	}
+}
+
+func (n *relayNode) send(t *testing.T, id enode.ID, text string) {
+	t.Helper()
+
+	n.lock.Lock()
+	rw := n.peers[id]
+	n.lock.Unlock()
+	if rw == nil {
+		t.Fatalf("not connected to %v", id)
+	}
+	if err := Send(rw, 0, text); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+}
+
+func (n *relayNode) expect(t *testing.T, text string) {
+	t.Helper()
+
+	select {
+	case got := <-n.recv:
+		if got != text {
+			t.Fatalf("received message mismatch: have %q, want %q", got, text)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("message %q not received", text)
+	}
+}
+
+// connectPipe connects a dialing server to a listening one over an in-memory
+// pipe, returning the errors of both ends.
+func connectPipe(dialer, listener *Server) (dialErr, listenErr error) {
+	fd1, fd2 := net.Pipe()
+	errc := make(chan error, 1)
+	go func() { errc <- listener.SetupConn(fd2, inboundConn, nil) }()
+	dialErr = dialer.SetupConn(fd1, staticDialedConn, listener.Self())
+	listenErr = <-errc
+	if dialErr != nil || listenErr != nil {
+		fd1.Close()
+		fd2.Close()
+	}
+	return dialErr, listenErr
+}
+
+func waitPeers(t *testing.T, srv *Server, n int) {
+	t.Helper()
+
+	for start := time.Now(); srv.PeerCount() != n; time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("peer count mismatch: have %d, want %d", srv.PeerCount(), n)
+		}
+	}
+}
+
+// This test runs a validator behind a sentry and an outsider over in-memory
+// pipes. The outsider only reaches the validator through the sentry, and the
+// sentry keeps a slot for its validator when full.
+func TestSentryTopology(t *testing.T) {
+	var (
+		validatorKey = newkey()
+		validatorID  = enode.PubkeyToIDV4(&validatorKey.PublicKey)
+		sentryKey    = newkey()
+
+		validatorRelay = newRelayNode(false)
+		sentryRelay    = newRelayNode(true)
+		outsiderRelay  = newRelayNode(false)
+	)
+	start := func(cfg Config, relay *relayNode) *Server {
+		if cfg.MaxPeers == 0 {
+			cfg.MaxPeers = 10
+		}
+		cfg.NoDial = true
+		cfg.NoDiscovery = true
+		cfg.Protocols = []Protocol{relay.protocol()}
+		cfg.Logger = testlog.Logger(t, log.LvlTrace)
+		srv := &Server{Config: cfg}
+		if err := srv.Start(); err != nil {
+			t.Fatalf("couldn't start server: %v", err)
+		}
+		t.Cleanup(srv.Stop)
+		return srv
+	}
+	sentry := start(Config{PrivateKey: sentryKey, Sentry: true, ProxyedNodeIds: []enode.ID{validatorID}, MaxPeers: 1}, sentryRelay)
+	validator := start(Config{PrivateKey: validatorKey, BehindSentry: true, SentryNodes: []*enode.Node{sentry.Self()}}, validatorRelay)
+	outsider := start(Config{PrivateKey: newkey()}, outsiderRelay)
+
+	// The outsider takes the only slot of the sentry, the validator still gets in
+	if dialErr, listenErr := connectPipe(outsider, sentry); dialErr != nil || listenErr != nil {
+		t.Fatalf("outsider failed to connect to sentry: %v, %v", dialErr, listenErr)
+	}
+	waitPeers(t, sentry, 1)
+	if dialErr, listenErr := connectPipe(validator, sentry); dialErr != nil || listenErr != nil {
+		t.Fatalf("validator failed to connect to full sentry: %v, %v", dialErr, listenErr)
+	}
+	waitPeers(t, sentry, 2)
+	waitPeers(t, validator, 1)
+
+	// The validator refuses the outsider, whichever side dials
+	if _, listenErr := connectPipe(outsider, validator); listenErr != errNotSentry {
+		t.Errorf("validator accepted outsider: have %v, want %v", listenErr, errNotSentry)
+	}
+	if dialErr, _ := connectPipe(validator, outsider); dialErr != errNotSentry {
+		t.Errorf("validator dialed outsider: have %v, want %v", dialErr, errNotSentry)
+	}
+	if validator.PeerCount() != 1 {
+		t.Errorf("validator peer count mismatch: have %d, want 1", validator.PeerCount())
+	}
+	// Messages reach the other side through the sentry
+	outsiderRelay.send(t, sentry.Self().ID(), "from outsider")
+	sentryRelay.expect(t, "from outsider")
+	validatorRelay.expect(t, "from outsider")
+
+	validatorRelay.send(t, sentry.Self().ID(), "from validator")
+	sentryRelay.expect(t, "from validator")
+	outsiderRelay.expect(t, "from validator")
+}
@@ -104,6 +104,11 @@ type Server struct {
 	discv5    *discover.UDPv5
 	discmix   *enode.FairMix
 	dialsched *dialScheduler
+	peerbook  *peerBook
+	capture   *MsgCapture
+	sentry    *sentryMode
+	traffic   *trafficStats
+	peerLimit atomic.Int64 // Peer limit lowered below MaxPeers at runtime, zero if not
 
 	forkFilter     forkid.Filter
 	peerNameFilter []*regexp.Regexp
@@ -426,7 +431,11 @@ func (srv *Server) Start() (err error) {
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
 	srv.disconnectEnodeSet = make(map[enode.ID]struct{})
+	srv.traffic = newTrafficStats()
 
+	if err := srv.setupSentryMode(); err != nil {
+		return err
+	}
 	if err := srv.setupLocalNode(); err != nil {
 		return err
 	}
@@ -440,6 +449,14 @@ func (srv *Server) Start() (err error) {
 	if err := srv.setupDiscovery(); err != nil {
 		return err
 	}
+	if srv.MessageCapture != "" {
+		capture, err := NewMsgCapture(srv.MessageCapture, srv.MessageCapturePayloads)
+		if err != nil {
+			return err
+		}
+		srv.capture = capture
+		srv.log.Info("Capturing p2p messages", "file", srv.MessageCapture, "payloads", srv.MessageCapturePayloads)
+	}
 	srv.setupDialScheduler()
 
 	if srv.PeerFilterPatterns != nil {
@@ -471,6 +488,7 @@ func (srv *Server) setupLocalNode() error {
 		return err
 	}
 	srv.nodedb = db
+	srv.peerbook = newPeerBook(db, srv.log)
 	srv.localnode = enode.NewLocalNode(db, srv.PrivateKey)
 	srv.localnode.SetFallbackIP(net.IP{127, 0, 0, 1})
 	// TODO: check conflicts
@@ -487,8 +505,9 @@ func (srv *Server) setupDiscovery() error {
 	// fairness of the mix, it's just for putting the
 	srv.discmix = enode.NewFairMix(0)
 
-	// Don't listen on UDP endpoint if DHT is disabled.
-	if srv.NoDiscovery {
+	// Don't listen on UDP endpoint if DHT is disabled. Nodes behind sentries
+	// are never discoverable.
+	if srv.NoDiscovery || srv.sentry.behind() {
 		return nil
 	}
 	conn, err := srv.setupUDPListening()
@@ -514,6 +533,12 @@ func (srv *Server) setupDiscovery() error {
 		}
 	}
 
+	// Sentries keep the validators they protect out of discovery
+	var exclude func(enode.ID) bool
+	if srv.sentry != nil {
+		exclude = srv.sentry.hidden
+	}
+
 	var (
 		sconn     discover.UDPConn = conn
 		unhandled chan discover.ReadPacket
@@ -534,6 +559,7 @@ func (srv *Server) setupDiscovery() error {
 			Unhandled:      unhandled,
 			Log:            srv.log,
 			FilterFunction: f,
+			ExcludeNode:    exclude,
 		}
 		ntab, err := discover.ListenV4(conn, srv.localnode, cfg)
 		if err != nil {
@@ -548,6 +574,7 @@ func (srv *Server) setupDiscovery() error {
 			Bootnodes:      srv.BootstrapNodesV5,
 			Log:            srv.log,
 			FilterFunction: f,
+			ExcludeNode:    exclude,
 		}
 		srv.discv5, err = discover.ListenV5(sconn, srv.localnode, cfg)
 		if err != nil {
@@ -600,14 +627,57 @@ func (srv *Server) setupDialScheduler() {
 	if config.dialer == nil {
 		config.dialer = tcpDialer{&net.Dialer{Timeout: defaultDialTimeout}}
 	}
-	srv.dialsched = newDialScheduler(config, srv.discmix, srv.SetupConn)
-	for _, n := range srv.StaticNodes {
+	// Redial the historically good peers first after a restart, before the
+	// discovered candidates. Nodes behind sentries only dial their sentries.
+	var candidates enode.Iterator = srv.discmix
+	if best := srv.peerbook.bestNodes(2 * config.maxDialPeers); len(best) > 0 && !srv.sentry.behind() {
+		srv.log.Debug("Dialing peers from peer book", "count", len(best))
+		candidates = &prependIter{first: enode.IterNodes(best), rest: srv.discmix}
+	}
+	srv.dialsched = newDialScheduler(config, candidates, srv.SetupConn)
+	for _, n := range srv.staticNodes() {
 		srv.dialsched.addStatic(n)
 	}
 }
 
 func (srv *Server) MaxInboundConns() int {
-	return srv.MaxPeers - srv.MaxDialedConns()
+	return srv.maxInboundConns(srv.MaxPeers)
+}
+
+// maxInboundConns returns the share of inbound connections of the given peer limit.
+func (srv *Server) maxInboundConns(maxPeers int) int {
+	if srv.NoDial {
+		return maxPeers - len(srv.staticNodes())
+	}
+	return maxPeers - srv.maxDialedConns(maxPeers)
+}
+
+// SetPeerLimit lowers the number of peers the server connects to below MaxPeers,
+// for protocols adapting it at runtime. The dialer and the inbound connections
+// are limited accordingly, connected peers above the limit are not dropped.
+// A limit of zero, or not below MaxPeers, restores the configured one.
+func (srv *Server) SetPeerLimit(limit int) {
+	if limit <= 0 || limit >= srv.MaxPeers {
+		limit = 0
+	}
+	if srv.peerLimit.Swap(int64(limit)) == int64(limit) {
+		return
+	}
+	srv.lock.Lock()
+	dialsched := srv.dialsched
+	srv.lock.Unlock()
+
+	if dialsched != nil && !srv.NoDial {
+		dialsched.setMaxDialPeers(srv.maxDialedConns(srv.maxPeers()))
+	}
+}
+
+// maxPeers returns the current peer limit, MaxPeers unless lowered at runtime.
+func (srv *Server) maxPeers() int {
+	if limit := srv.peerLimit.Load(); limit > 0 {
+		return int(limit)
+	}
+	return srv.MaxPeers
 }
 
 func (srv *Server) SetFilter(f forkid.Filter) {
@@ -616,15 +686,20 @@ func (srv *Server) SetFilter(f forkid.Filter) {
 
 func (srv *Server) MaxDialedConns() (limit int) {
 	if srv.NoDial {
-		return len(srv.StaticNodes)
+		return len(srv.staticNodes())
 	}
-	if srv.MaxPeers == 0 {
+	return srv.maxDialedConns(srv.MaxPeers)
+}
+
+// maxDialedConns returns the share of dialed connections of the given peer limit.
+func (srv *Server) maxDialedConns(maxPeers int) (limit int) {
+	if maxPeers == 0 {
 		return 0
 	}
 	if srv.DialRatio == 0 {
-		limit = srv.MaxPeers / defaultDialRatio
+		limit = maxPeers / defaultDialRatio
 	} else {
-		limit = srv.MaxPeers / srv.DialRatio
+		limit = maxPeers / srv.DialRatio
 	}
 	if limit == 0 {
 		limit = 1
@@ -703,6 +778,10 @@ func (srv *Server) run() {
 	srv.log.Info("Started P2P networking", "self", srv.localnode.Node().URLv4())
 	defer srv.loopWG.Done()
 	defer srv.nodedb.Close()
+	defer srv.peerbook.close()
+	if srv.capture != nil {
+		defer srv.capture.Close()
+	}
 	defer srv.discmix.Close()
 	defer srv.dialsched.stop()
 
@@ -716,6 +795,9 @@ func (srv *Server) run() {
 	for _, n := range srv.TrustedNodes {
 		trusted[n.ID()] = true
 	}
+	for _, id := range srv.sentry.trusted() {
+		trusted[id] = true
+	}
 
 running:
 	for {
@@ -788,6 +870,7 @@ running:
 			delete(peers, pd.ID())
 			srv.log.Debug("Removing p2p peer", "peercount", len(peers), "id", pd.ID(), "duration", d, "req", pd.requested, "err", pd.err)
 			srv.dialsched.peerRemoved(pd.rw)
+			srv.peerbook.record(pd.Peer, pd.err)
 			if pd.Inbound() {
 				inboundCount--
 				activeInboundPeerGauge.Dec(1)
@@ -817,15 +900,20 @@ running:
 	for len(peers) > 0 {
 		p := <-srv.delpeer
 		p.log.Trace("<-delpeer (spindown)")
+		srv.peerbook.record(p.Peer, p.err)
 		delete(peers, p.ID())
 	}
 }
 
 func (srv *Server) postHandshakeChecks(peers map[enode.ID]*Peer, inboundCount int, c *conn) error {
+	if err := srv.sentry.checkPeer(c.node.ID()); err != nil {
+		return err
+	}
+	maxPeers := srv.maxPeers()
 	switch {
-	case !c.is(trustedConn) && len(peers) >= srv.MaxPeers:
+	case !c.is(trustedConn) && len(peers) >= maxPeers:
 		return DiscTooManyPeers
-	case !c.is(trustedConn) && c.is(inboundConn) && inboundCount >= srv.MaxInboundConns():
+	case !c.is(trustedConn) && c.is(inboundConn) && inboundCount >= srv.maxInboundConns(maxPeers):
 		return DiscTooManyPeers
 	case peers[c.node.ID()] != nil:
 		return DiscAlreadyConnected
@@ -935,6 +1023,10 @@ func (srv *Server) checkInboundConn(remoteIP netip.Addr) error {
 		// This case happens for internal test connections without remote address.
 		return nil
 	}
+	// Reject connections from anyone but the sentries of a node behind sentries.
+	if err := srv.sentry.checkAddr(remoteIP); err != nil {
+		return err
+	}
 
 	// Reject connections that do not match NetRestrict.
 	if srv.NetRestrict != nil && !srv.NetRestrict.ContainsAddr(remoteIP) {
@@ -1053,6 +1145,8 @@ func (srv *Server) checkpoint(c *conn, stage chan<- *conn) error {
 
 func (srv *Server) launchPeer(c *conn) *Peer {
 	p := newPeer(srv.log, c, srv.Protocols)
+	p.capture = srv.capture
+	p.traffic = srv.traffic
 	if srv.EnableMsgEvents {
 		// If message events are enabled, pass the peerFeed
 		// to the peer.
@@ -1158,6 +1252,18 @@ func (srv *Server) PeersInfo() []*PeerInfo {
 	return infos
 }
 
+// PeerBook returns the recorded connection history of the peers, from the best
+// scoring to the worst.
+func (srv *Server) PeerBook() []*PeerBookEntry {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	if !srv.running || srv.peerbook == nil {
+		return nil
+	}
+	return srv.peerbook.entries()
+}
+
 func compilePeerFilterPatterns(pat []string) ([]*regexp.Regexp, error) {
 	var filters []*regexp.Regexp
 	for _, filter := range pat {
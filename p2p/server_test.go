@@ -333,6 +333,44 @@ func TestServerAtCap(t *testing.T) {
 	}
 }
 
+// Tests that the peer limit lowered at runtime rejects connections beyond it,
+// and that restoring it admits them again.
+func TestServerSetPeerLimit(t *testing.T) {
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  newkey(),
+			MaxPeers:    10,
+			NoDial:      true,
+			NoDiscovery: true,
+			Logger:      testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	newconn := func() *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(&newkey().PublicKey, fd, nil)
+		node := enode.SignNull(new(enr.Record), randomID())
+		return &conn{fd: fd, transport: tx, flags: inboundConn, node: node, cont: make(chan error)}
+	}
+	for i := 0; i < 5; i++ {
+		if err := srv.checkpoint(newconn(), srv.checkpointAddPeer); err != nil {
+			t.Fatalf("could not add conn %d: %v", i, err)
+		}
+	}
+	srv.SetPeerLimit(5)
+	if err := srv.checkpoint(newconn(), srv.checkpointPostHandshake); err != DiscTooManyPeers {
+		t.Errorf("wrong error above lowered limit: %v", err)
+	}
+	srv.SetPeerLimit(0)
+	if err := srv.checkpoint(newconn(), srv.checkpointPostHandshake); err != nil {
+		t.Errorf("unexpected error after restoring limit: %v", err)
+	}
+}
+
 func TestServerPeerLimits(t *testing.T) {
 	srvkey := newkey()
 	clientkey := newkey()
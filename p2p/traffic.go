@@ -0,0 +1,191 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// trafficRateWindow is the time constant of the moving average of the traffic
+// rates. The rates are only updated once at least a second has passed.
+const trafficRateWindow = 10 * time.Second
+
+// ProtocolTraffic is the traffic exchanged over a subprotocol since startup.
+// The byte counts are the compressed sizes on the wire.
+type ProtocolTraffic struct {
+	Protocol       string `json:"protocol"` // Protocol name and version, e.g. eth/68
+	IngressBytes   uint64 `json:"ingressBytes"`
+	IngressPackets uint64 `json:"ingressPackets"`
+	EgressBytes    uint64 `json:"egressBytes"`
+	EgressPackets  uint64 `json:"egressPackets"`
+}
+
+// TrafficInfo is the traffic of the node along with its bandwidth budget.
+type TrafficInfo struct {
+	IngressRate    float64            `json:"ingressRate"`              // Download rate in bytes per second
+	EgressRate     float64            `json:"egressRate"`               // Upload rate in bytes per second
+	DownloadBudget uint64             `json:"downloadBudget,omitempty"` // Download budget in bytes per second
+	UploadBudget   uint64             `json:"uploadBudget,omitempty"`   // Upload budget in bytes per second
+	Protocols      []*ProtocolTraffic `json:"protocols"`
+}
+
+// protocolCounters is the traffic of one subprotocol.
+type protocolCounters struct {
+	ingressBytes, ingressPackets atomic.Uint64
+	egressBytes, egressPackets   atomic.Uint64
+}
+
+// trafficStats counts the subprotocol traffic of the peers of a server and
+// tracks its rate. Unlike the per-packet meters, it is maintained even if the
+// metrics system is disabled, as the bandwidth budget relies on it.
+type trafficStats struct {
+	lock      sync.RWMutex
+	protocols map[Cap]*protocolCounters
+
+	ingress atomic.Uint64 // Total bytes received over all subprotocols
+	egress  atomic.Uint64 // Total bytes sent over all subprotocols
+
+	rateLock                sync.Mutex
+	sampled                 time.Time // Time of the last rate update
+	lastIngress, lastEgress uint64    // Total bytes at the last rate update
+	ingressRate, egressRate float64   // Moving averages in bytes per second
+}
+
+func newTrafficStats() *trafficStats {
+	return &trafficStats{
+		protocols: make(map[Cap]*protocolCounters),
+		sampled:   time.Now(),
+	}
+}
+
+// counters returns the counters of a subprotocol, creating them if needed.
+func (t *trafficStats) counters(cap Cap) *protocolCounters {
+	t.lock.RLock()
+	c := t.protocols[cap]
+	t.lock.RUnlock()
+	if c != nil {
+		return c
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if c = t.protocols[cap]; c == nil {
+		c = new(protocolCounters)
+		t.protocols[cap] = c
+	}
+	return c
+}
+
+// markIngress records a message received over a subprotocol.
+func (t *trafficStats) markIngress(cap Cap, size uint32) {
+	if t == nil {
+		return
+	}
+	c := t.counters(cap)
+	c.ingressBytes.Add(uint64(size))
+	c.ingressPackets.Add(1)
+	t.ingress.Add(uint64(size))
+}
+
+// markEgress records a message sent over a subprotocol.
+func (t *trafficStats) markEgress(cap Cap, size uint32) {
+	if t == nil {
+		return
+	}
+	c := t.counters(cap)
+	c.egressBytes.Add(uint64(size))
+	c.egressPackets.Add(1)
+	t.egress.Add(uint64(size))
+}
+
+// rates updates and returns the moving averages of the download and upload
+// rates in bytes per second.
+func (t *trafficStats) rates(now time.Time) (ingress, egress float64) {
+	t.rateLock.Lock()
+	defer t.rateLock.Unlock()
+
+	elapsed := now.Sub(t.sampled)
+	if elapsed < time.Second {
+		return t.ingressRate, t.egressRate
+	}
+	in, out := t.ingress.Load(), t.egress.Load()
+	alpha := 1 - math.Exp(-elapsed.Seconds()/trafficRateWindow.Seconds())
+	t.ingressRate += alpha * (float64(in-t.lastIngress)/elapsed.Seconds() - t.ingressRate)
+	t.egressRate += alpha * (float64(out-t.lastEgress)/elapsed.Seconds() - t.egressRate)
+	t.sampled, t.lastIngress, t.lastEgress = now, in, out
+
+	return t.ingressRate, t.egressRate
+}
+
+// list returns the traffic of each subprotocol, ordered by protocol.
+func (t *trafficStats) list() []*ProtocolTraffic {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	list := make([]*ProtocolTraffic, 0, len(t.protocols))
+	for cap, c := range t.protocols {
+		list = append(list, &ProtocolTraffic{
+			Protocol:       cap.String(),
+			IngressBytes:   c.ingressBytes.Load(),
+			IngressPackets: c.ingressPackets.Load(),
+			EgressBytes:    c.egressBytes.Load(),
+			EgressPackets:  c.egressPackets.Load(),
+		})
+	}
+	slices.SortFunc(list, func(a, b *ProtocolTraffic) int {
+		return strings.Compare(a.Protocol, b.Protocol)
+	})
+	return list
+}
+
+// trafficStats returns the traffic counters of the server, nil if the server
+// was never started.
+func (srv *Server) trafficStats() *trafficStats {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	return srv.traffic
+}
+
+// TrafficRates returns the moving averages of the download and upload rates of
+// the subprotocol traffic, in bytes per second.
+func (srv *Server) TrafficRates() (ingress, egress float64) {
+	stats := srv.trafficStats()
+	if stats == nil {
+		return 0, 0
+	}
+	return stats.rates(time.Now())
+}
+
+// Traffic returns the subprotocol traffic of the node and its bandwidth budget.
+func (srv *Server) Traffic() *TrafficInfo {
+	info := &TrafficInfo{
+		DownloadBudget: srv.DownloadBudget,
+		UploadBudget:   srv.UploadBudget,
+		Protocols:      []*ProtocolTraffic{},
+	}
+	if stats := srv.trafficStats(); stats != nil {
+		info.IngressRate, info.EgressRate = stats.rates(time.Now())
+		info.Protocols = stats.list()
+	}
+	return info
+}
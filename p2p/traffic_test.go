@@ -0,0 +1,72 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrafficStats(t *testing.T) {
+	stats := newTrafficStats()
+	start := stats.sampled
+
+	eth, snap := Cap{"eth", 68}, Cap{"snap", 1}
+	stats.markIngress(eth, 100)
+	stats.markIngress(eth, 200)
+	stats.markEgress(eth, 50)
+	stats.markEgress(snap, 1000)
+
+	want := []*ProtocolTraffic{
+		{Protocol: "eth/68", IngressBytes: 300, IngressPackets: 2, EgressBytes: 50, EgressPackets: 1},
+		{Protocol: "snap/1", EgressBytes: 1000, EgressPackets: 1},
+	}
+	if have := stats.list(); !reflect.DeepEqual(have, want) {
+		t.Fatalf("traffic mismatch:\nhave %+v\nwant %+v", have, want)
+	}
+	// Rates are not updated within a second.
+	if in, out := stats.rates(start.Add(500 * time.Millisecond)); in != 0 || out != 0 {
+		t.Fatalf("rates updated early: %v %v", in, out)
+	}
+	// A sustained rate is converged to.
+	var in, out float64
+	for i := 1; i <= 100; i++ {
+		stats.markIngress(eth, 3000)
+		stats.markEgress(snap, 1000)
+		in, out = stats.rates(start.Add(time.Duration(i) * time.Second))
+	}
+	if math.Abs(in-3000) > 10 || math.Abs(out-1000) > 10 {
+		t.Fatalf("rate mismatch: have %v/%v, want 3000/1000", in, out)
+	}
+}
+
+func TestServerTrafficNotStarted(t *testing.T) {
+	srv := &Server{Config: Config{UploadBudget: 1000}}
+	if in, out := srv.TrafficRates(); in != 0 || out != 0 {
+		t.Fatalf("rates of server not started: %v %v", in, out)
+	}
+	info := srv.Traffic()
+	if info.UploadBudget != 1000 || len(info.Protocols) != 0 {
+		t.Fatalf("traffic of server not started: %+v", info)
+	}
+	// Peers created outside of a server have no counters.
+	var stats *trafficStats
+	stats.markIngress(Cap{"eth", 68}, 100)
+	stats.markEgress(Cap{"eth", 68}, 100)
+}
@@ -88,6 +88,7 @@ type Client struct {
 	// config fields
 	batchItemLimit       int
 	batchResponseMaxSize int
+	requestLog           *RequestLog
 
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
@@ -119,7 +120,11 @@ func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
+	if wc, ok := conn.(*websocketCodec); ok && wc.gate != nil {
+		ctx = WithCallGate(ctx, wc.gate)
+	}
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize)
+	handler.requestLog = c.requestLog
 	return &clientConn{conn, handler}
 }
 
@@ -247,6 +252,7 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig)
 		idgen:                cfg.idgen,
 		batchItemLimit:       cfg.batchItemLimit,
 		batchResponseMaxSize: cfg.batchResponseLimit,
+		requestLog:           cfg.requestLog,
 		writeConn:            conn,
 		close:                make(chan struct{}),
 		closing:              make(chan struct{}),
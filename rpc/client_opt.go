@@ -42,6 +42,7 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	requestLog         *RequestLog
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -0,0 +1,53 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// CallGate admits or rejects the requests of a client connection before they
+// are executed, e.g. to enforce per-client permissions and quotas. Rejections
+// are reported to the client as the error responses of the requests, so the
+// returned errors should implement Error to carry a meaningful code.
+type CallGate interface {
+	// AdmitBatch is consulted once for every batch with the number of items.
+	AdmitBatch(size int) error
+
+	// AdmitCall is consulted for every method call, including the calls of
+	// batches and subscription requests.
+	AdmitCall(method string, params json.RawMessage) error
+}
+
+type callGateContextKey struct{}
+
+// WithCallGate returns a context holding the gate of the requests served on
+// it. Setting the gate on the context of an HTTP request passed to a Server
+// makes it apply to the calls of the request, or to all the calls sent over
+// the connection in case of a WebSocket upgrade request. As the gate is held
+// for the lifetime of the connection, it should look up the permissions it
+// enforces on every call for their changes to apply to open connections.
+func WithCallGate(ctx context.Context, gate CallGate) context.Context {
+	return context.WithValue(ctx, callGateContextKey{}, gate)
+}
+
+// callGateFromContext retrieves the gate set on the context, if any.
+func callGateFromContext(ctx context.Context) CallGate {
+	gate, _ := ctx.Value(callGateContextKey{}).(CallGate)
+	return gate
+}
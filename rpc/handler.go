@@ -72,6 +72,8 @@ type handler struct {
 	allowSubscribe       bool
 	batchRequestLimit    int
 	batchResponseMaxSize int
+	gate                 CallGate    // admits the requests of the connection, optional
+	requestLog           *RequestLog // records the served calls, optional
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -97,6 +99,7 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 		log:                  log.Root(),
 		batchRequestLimit:    batchRequestLimit,
 		batchResponseMaxSize: batchResponseMaxSize,
+		gate:                 callGateFromContext(connCtx),
 	}
 	if conn.remoteAddr() != "" {
 		h.log = h.log.New("conn", conn.remoteAddr())
@@ -191,6 +194,14 @@ func (h *handler) handleBatch(ctx context.Context, msgs []*jsonrpcMessage) {
 		})
 		return
 	}
+	if h.gate != nil {
+		if err := h.gate.AdmitBatch(len(msgs)); err != nil {
+			h.startCallProc(func(cp *callProc) {
+				h.respondWithBatchError(cp, msgs, err)
+			})
+			return
+		}
+	}
 
 	// Handle non-call messages first.
 	// Here we need to find the requestOp that sent the request batch.
@@ -258,7 +269,12 @@ func (h *handler) handleBatch(ctx context.Context, msgs []*jsonrpcMessage) {
 }
 
 func (h *handler) respondWithBatchTooLarge(cp *callProc, batch []*jsonrpcMessage) {
-	resp := errorMessage(&invalidRequestError{errMsgBatchTooLarge})
+	h.respondWithBatchError(cp, batch, &invalidRequestError{errMsgBatchTooLarge})
+}
+
+// respondWithBatchError rejects the entire batch with the given error.
+func (h *handler) respondWithBatchError(cp *callProc, batch []*jsonrpcMessage, err error) {
+	resp := errorMessage(err)
 	// Find the first call and add its "id" field to the error.
 	// This is the best we can do, given that the protocol doesn't have a way
 	// of reporting an error for the entire batch.
@@ -509,6 +525,19 @@ func (h *handler) handleCallMsg(ctx *callProc, reqCtx context.Context, msg *json
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	start := time.Now()
+
+	// reject answers calls failing before execution, recording them too
+	reject := func(err error) *jsonrpcMessage {
+		answer := msg.errorResponse(err)
+		h.requestLog.record(cp.ctx, msg, answer, time.Since(start))
+		return answer
+	}
+	if h.gate != nil {
+		if err := h.gate.AdmitCall(msg.Method, msg.Params); err != nil {
+			return reject(err)
+		}
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
@@ -518,19 +547,19 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	} else {
 		// Check method name length
 		if len(msg.Method) > maxMethodNameLength {
-			return msg.errorResponse(&invalidRequestError{fmt.Sprintf("method name too long: %d > %d", len(msg.Method), maxMethodNameLength)})
+			return reject(&invalidRequestError{fmt.Sprintf("method name too long: %d > %d", len(msg.Method), maxMethodNameLength)})
 		}
 		callb = h.reg.callback(msg.Method)
 	}
 	if callb == nil {
-		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
+		return reject(&methodNotFoundError{method: msg.Method})
 	}
 
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
-		return msg.errorResponse(&invalidParamsError{err.Error()})
+		return reject(&invalidParamsError{err.Error()})
 	}
-	start := time.Now()
+	start = time.Now()
 	answer := h.runMethod(cp.ctx, msg, callb, args)
 
 	// Collect the statistics for RPC calls if metrics is enabled.
@@ -545,6 +574,7 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		RpcServingTimer.UpdateSince(start)
 		newRPCRequestGauge(msg.Method).Inc(1)
 		updateServeTimeHistogram(msg.Method, answer.Error == nil, time.Since(start))
+		h.requestLog.record(cp.ctx, msg, answer, time.Since(start))
 	}
 
 	return answer
@@ -0,0 +1,280 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// openRPCVersion is the version of the OpenRPC specification the discovery
+// documents conform to.
+const openRPCVersion = "1.2.6"
+
+var (
+	jsonMarshalerType     = reflect.TypeFor[json.Marshaler]()
+	jsonUnmarshalerType   = reflect.TypeFor[json.Unmarshaler]()
+	textMarshalerType     = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType   = reflect.TypeFor[encoding.TextUnmarshaler]()
+	invalidComponentChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// OpenRPCDocument is an OpenRPC description of the methods served by a server.
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []OpenRPCMethod   `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+// OpenRPCInfo is the metadata of an OpenRPC document.
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCMethod describes a method, with its parameters in positional order.
+type OpenRPCMethod struct {
+	Name   string                     `json:"name"`
+	Params []OpenRPCContentDescriptor `json:"params"`
+	Result OpenRPCContentDescriptor   `json:"result"`
+}
+
+// OpenRPCContentDescriptor describes a parameter or the result of a method.
+type OpenRPCContentDescriptor struct {
+	Name     string      `json:"name"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *JSONSchema `json:"schema"`
+}
+
+// OpenRPCComponents holds the schemas of the struct types shared by methods.
+type OpenRPCComponents struct {
+	Schemas map[string]*JSONSchema `json:"schemas"`
+}
+
+// JSONSchema is the subset of JSON schema needed to describe the Go types of
+// the method parameters and results. The titles hold the Go type names, so the
+// types with custom JSON encodings can be told apart.
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+}
+
+// Discover returns the OpenRPC document describing all the methods served.
+func (s *RPCService) Discover() *OpenRPCDocument {
+	return s.server.OpenRPCDocument()
+}
+
+// OpenRPCDocument generates the OpenRPC document describing all the methods of
+// the registered services. The parameter and result schemas are derived from
+// the Go types of the method callbacks.
+func (s *Server) OpenRPCDocument() *OpenRPCDocument {
+	s.services.mu.Lock()
+	defer s.services.mu.Unlock()
+
+	gen := &openRPCGenerator{schemas: make(map[string]*JSONSchema)}
+	doc := &OpenRPCDocument{
+		OpenRPC: openRPCVersion,
+		Info:    OpenRPCInfo{Title: "JSON-RPC API", Version: "1.0"},
+	}
+	for namespace, svc := range s.services.services {
+		for name, cb := range svc.callbacks {
+			doc.Methods = append(doc.Methods, gen.method(namespace+serviceMethodSeparator+name, cb))
+		}
+		if len(svc.subscriptions) > 0 {
+			doc.Methods = append(doc.Methods, gen.subscriptionMethods(namespace, svc.subscriptions)...)
+		}
+	}
+	slices.SortFunc(doc.Methods, func(a, b OpenRPCMethod) int { return strings.Compare(a.Name, b.Name) })
+	doc.Components.Schemas = gen.schemas
+	return doc
+}
+
+// openRPCGenerator derives the schemas of Go types, collecting the schemas of
+// the struct types as shared components.
+type openRPCGenerator struct {
+	schemas map[string]*JSONSchema
+}
+
+// method describes a method callback.
+func (g *openRPCGenerator) method(name string, cb *callback) OpenRPCMethod {
+	method := OpenRPCMethod{Name: name, Params: make([]OpenRPCContentDescriptor, 0, len(cb.argTypes))}
+
+	// Trailing pointer arguments may be omitted by the callers
+	optional := len(cb.argTypes)
+	for optional > 0 && cb.argTypes[optional-1].Kind() == reflect.Ptr {
+		optional--
+	}
+	used := make(map[string]bool)
+	for i, typ := range cb.argTypes {
+		pname := paramName(typ)
+		if used[pname] {
+			pname = fmt.Sprintf("%s%d", pname, i)
+		}
+		used[pname] = true
+
+		method.Params = append(method.Params, OpenRPCContentDescriptor{
+			Name:     pname,
+			Required: i < optional,
+			Schema:   g.schema(typ),
+		})
+	}
+	method.Result = OpenRPCContentDescriptor{Name: "result", Schema: &JSONSchema{Type: "null"}}
+	if fntype := cb.fn.Type(); fntype.NumOut() > 0 && cb.errPos != 0 {
+		method.Result.Schema = g.schema(fntype.Out(0))
+	}
+	return method
+}
+
+// subscriptionMethods describes the subscribe and unsubscribe methods of a
+// namespace, listing the available subscriptions as the values of the first
+// parameter of the former.
+func (g *openRPCGenerator) subscriptionMethods(namespace string, subscriptions map[string]*callback) []OpenRPCMethod {
+	names := make([]string, 0, len(subscriptions))
+	for name := range subscriptions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return []OpenRPCMethod{
+		{
+			Name:   namespace + subscribeMethodSuffix,
+			Params: []OpenRPCContentDescriptor{{Name: "subscription", Required: true, Schema: &JSONSchema{Type: "string", Enum: names}}},
+			Result: OpenRPCContentDescriptor{Name: "subscriptionId", Schema: &JSONSchema{Type: "string"}},
+		},
+		{
+			Name:   namespace + unsubscribeMethodSuffix,
+			Params: []OpenRPCContentDescriptor{{Name: "subscriptionId", Required: true, Schema: &JSONSchema{Type: "string"}}},
+			Result: OpenRPCContentDescriptor{Name: "result", Schema: &JSONSchema{Type: "boolean"}},
+		},
+	}
+}
+
+// schema derives the JSON schema of a Go type, as encoded by encoding/json.
+func (g *openRPCGenerator) schema(typ reflect.Type) *JSONSchema {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	// Types with custom encodings are described by their Go type only, unless
+	// they are known to be encoded as strings
+	ptr := reflect.PointerTo(typ)
+	switch {
+	case typ.Implements(jsonMarshalerType) || ptr.Implements(jsonMarshalerType):
+		return &JSONSchema{Title: typ.String()}
+	case typ.Implements(textMarshalerType) || ptr.Implements(textMarshalerType):
+		return &JSONSchema{Title: typ.String(), Type: "string"}
+	case ptr.Implements(jsonUnmarshalerType):
+		return &JSONSchema{Title: typ.String()}
+	case ptr.Implements(textUnmarshalerType):
+		return &JSONSchema{Title: typ.String(), Type: "string"}
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string"} // base64 encoded
+		}
+		return &JSONSchema{Type: "array", Items: g.schema(typ.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.schema(typ.Elem())}
+	case reflect.Struct:
+		return g.structSchema(typ)
+	default:
+		return &JSONSchema{} // interfaces and unencodable types
+	}
+}
+
+// structSchema describes a struct type as a shared component, referenced from
+// the schemas using it.
+func (g *openRPCGenerator) structSchema(typ reflect.Type) *JSONSchema {
+	if typ.Name() == "" {
+		schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+		g.addFields(schema, typ)
+		return schema
+	}
+	name := invalidComponentChars.ReplaceAllString(typ.String(), "_")
+	if _, ok := g.schemas[name]; !ok {
+		// Register the component before the fields, for recursive types
+		schema := &JSONSchema{Title: typ.String(), Type: "object", Properties: make(map[string]*JSONSchema)}
+		g.schemas[name] = schema
+		g.addFields(schema, typ)
+	}
+	return &JSONSchema{Ref: "#/components/schemas/" + name}
+}
+
+// addFields adds the encoded fields of a struct type to an object schema,
+// inlining the fields of the embedded structs as encoding/json does.
+func (g *openRPCGenerator) addFields(schema *JSONSchema, typ reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = g.schema(field.Type)
+	}
+}
+
+// paramName derives the name of a parameter from its type.
+func paramName(typ reflect.Type) string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	name := typ.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i] // generic type arguments
+	}
+	if name == "" {
+		return "param"
+	}
+	return formatName(name)
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// Tests that the discovery document describes the registered methods with the
+// schemas of their parameters and results.
+func TestDiscover(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	var doc OpenRPCDocument
+	if err := client.Call(&doc, "rpc_discover"); err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+	if doc.OpenRPC != openRPCVersion {
+		t.Errorf("version mismatch: have %s, want %s", doc.OpenRPC, openRPCVersion)
+	}
+	methods := make(map[string]OpenRPCMethod)
+	for _, method := range doc.Methods {
+		methods[method.Name] = method
+	}
+	for _, name := range []string{"rpc_discover", "rpc_modules", "test_echo", "test_noArgsRets", "nftest_subscribe", "nftest_unsubscribe"} {
+		if _, ok := methods[name]; !ok {
+			t.Errorf("method %s missing", name)
+		}
+	}
+	if _, ok := methods["test_invalidRets1"]; ok {
+		t.Error("invalid method described")
+	}
+	// Parameters are described in order, with the trailing pointers optional
+	echo := methods["test_echo"]
+	want := []OpenRPCContentDescriptor{
+		{Name: "string", Required: true, Schema: &JSONSchema{Type: "string"}},
+		{Name: "int", Required: true, Schema: &JSONSchema{Type: "integer"}},
+		{Name: "echoArgs", Schema: &JSONSchema{Ref: "#/components/schemas/rpc.echoArgs"}},
+	}
+	if !reflect.DeepEqual(echo.Params, want) {
+		t.Errorf("test_echo params mismatch: have %+v, want %+v", echo.Params, want)
+	}
+	if echo.Result.Schema.Ref != "#/components/schemas/rpc.echoResult" {
+		t.Errorf("test_echo result mismatch: have %+v", echo.Result.Schema)
+	}
+	result := doc.Components.Schemas["rpc.echoResult"]
+	if result == nil || result.Properties["Int"].Type != "integer" || result.Properties["Args"].Ref != "#/components/schemas/rpc.echoArgs" {
+		t.Errorf("echoResult schema mismatch: %+v", result)
+	}
+	// Types with custom encodings are described by their Go types
+	if schema := methods["test_marshalError"].Result.Schema; schema.Type != "string" || schema.Title != "rpc.MarshalErrObj" {
+		t.Errorf("text marshaled result mismatch: %+v", schema)
+	}
+	if schema := methods["test_sleep"].Params[0].Schema; schema.Type != "integer" {
+		t.Errorf("duration param mismatch: %+v", schema)
+	}
+	// Subscriptions are listed as values of the subscribe method
+	subscribe := methods["nftest_subscribe"]
+	if len(subscribe.Params) != 1 || !slices.Equal(subscribe.Params[0].Schema.Enum, []string{"hangSubscription", "someSubscription"}) {
+		t.Errorf("subscriptions mismatch: %+v", subscribe.Params)
+	}
+}
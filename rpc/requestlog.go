@@ -0,0 +1,160 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// requestLogBuffer is the number of records buffered for writing, the
+	// records logged while the buffer is full are dropped.
+	requestLogBuffer = 1024
+
+	// requestLogRotateHours is the number of hours after which the request
+	// log file is rotated.
+	requestLogRotateHours = 1
+
+	// requestLogMaxBackups is the number of rotated request log files kept.
+	requestLogMaxBackups = 24
+)
+
+// RequestLogConfig configures which calls are recorded in a request log.
+type RequestLogConfig struct {
+	SampleRate    float64       // Fraction of the calls to record, between 0 and 1
+	SlowThreshold time.Duration // Duration above which calls are always recorded, 0 = none
+}
+
+// RequestLog records the served method calls into a rotated log file, one
+// JSON line per call. The calls exceeding the slow threshold are always
+// recorded, the others are sampled.
+//
+// A request log is shared by the servers it is set on, and may be started and
+// stopped at any time while they are serving.
+type RequestLog struct {
+	path   string
+	config atomic.Pointer[RequestLogConfig] // Current configuration, nil if stopped
+
+	lock   sync.RWMutex
+	writer *log.AsyncFileWriter
+	logger log.Logger
+}
+
+// NewRequestLog creates a stopped request log, writing into the given file.
+func NewRequestLog(path string) *RequestLog {
+	return &RequestLog{path: path}
+}
+
+// Start starts recording calls with the given configuration, or updates the
+// configuration if already recording.
+func (l *RequestLog) Start(config RequestLogConfig) error {
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return errors.New("sample rate must be between 0 and 1")
+	}
+	if config.SlowThreshold < 0 {
+		return errors.New("negative slow call threshold")
+	}
+	if l.path == "" {
+		return errors.New("no request log file configured")
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.writer == nil {
+		writer := log.NewAsyncFileWriter(l.path, requestLogBuffer, requestLogMaxBackups, requestLogRotateHours)
+		if err := writer.Start(); err != nil {
+			return err
+		}
+		l.writer, l.logger = writer, log.NewLogger(log.JSONHandler(writer))
+	}
+	l.config.Store(&config)
+	return nil
+}
+
+// Stop stops recording calls and closes the log file.
+func (l *RequestLog) Stop() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.config.Store(nil)
+	if l.writer != nil {
+		l.writer.Stop()
+		l.writer, l.logger = nil, nil
+	}
+}
+
+// Config returns the current configuration, and whether calls are recorded.
+func (l *RequestLog) Config() (RequestLogConfig, bool) {
+	if config := l.config.Load(); config != nil {
+		return *config, true
+	}
+	return RequestLogConfig{}, false
+}
+
+// record logs a served call, if selected by the configuration.
+func (l *RequestLog) record(ctx context.Context, msg, answer *jsonrpcMessage, elapsed time.Duration) {
+	if l == nil {
+		return
+	}
+	config := l.config.Load()
+	if config == nil {
+		return
+	}
+	slow := config.SlowThreshold > 0 && elapsed >= config.SlowThreshold
+	if !slow && (config.SampleRate == 0 || rand.Float64() >= config.SampleRate) {
+		return
+	}
+	digest := sha256.Sum256(msg.Params)
+	peer := PeerInfoFromContext(ctx)
+	attrs := []any{
+		"method", msg.Method,
+		"params", hex.EncodeToString(digest[:8]),
+		"duration", elapsed,
+		"size", len(answer.Result),
+		"transport", peer.Transport,
+		"remote", peer.RemoteAddr,
+	}
+	if peer.HTTP.UserAgent != "" {
+		attrs = append(attrs, "agent", peer.HTTP.UserAgent)
+	}
+	if peer.HTTP.Origin != "" {
+		attrs = append(attrs, "origin", peer.HTTP.Origin)
+	}
+	if answer.Error != nil {
+		attrs = append(attrs, "err", answer.Error.Message)
+	}
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	if l.logger == nil {
+		return // stopped concurrently
+	}
+	if slow {
+		l.logger.Warn("Slow RPC call", attrs...)
+	} else {
+		l.logger.Info("RPC call", attrs...)
+	}
+}
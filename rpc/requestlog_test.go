@@ -0,0 +1,142 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readRequestLog reads the records of a stopped request log.
+func readRequestLog(t *testing.T, path string) []map[string]any {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open request log: %v", err)
+	}
+	defer file.Close()
+
+	var records []map[string]any
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		record := make(map[string]any)
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Tests that the request log records the sampled and the slow calls along with
+// the details of the calling peers, including the calls rejected before being
+// executed.
+func TestRequestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.log")
+	reqlog := NewRequestLog(path)
+
+	server := newTestServer()
+	server.SetRequestLog(reqlog)
+	defer server.Stop()
+
+	gate := &testGate{method: "test_echo", maxBatch: 1}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.ServeHTTP(w, r.WithContext(WithCallGate(r.Context(), gate)))
+	}))
+	defer ts.Close()
+
+	client, err := DialHTTP(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Calls are not recorded until the log is started
+	var result string
+	if err := client.Call(&result, "test_repeat", "x", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := reqlog.Start(RequestLogConfig{SampleRate: 1}); err != nil {
+		t.Fatalf("failed to start request log: %v", err)
+	}
+	if err := client.Call(&result, "test_repeat", "x", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "test_returnError"); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := client.Call(nil, "test_missing"); err == nil {
+		t.Fatal("expected method not found error")
+	}
+	if err := client.Call(nil, "test_repeat", "x", "y"); err == nil {
+		t.Fatal("expected invalid params error")
+	}
+	if err := client.Call(nil, "test_echo", "x", 1); err == nil {
+		t.Fatal("expected gate rejection")
+	}
+	longMethod := "test_" + strings.Repeat("x", maxMethodNameLength)
+	if err := client.Call(nil, longMethod); err == nil {
+		t.Fatal("expected method name too long error")
+	}
+	// Only the slow calls are recorded without sampling
+	if err := reqlog.Start(RequestLogConfig{SlowThreshold: 50 * time.Millisecond}); err != nil {
+		t.Fatalf("failed to reconfigure request log: %v", err)
+	}
+	if err := client.Call(nil, "test_sleep", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "test_sleep", 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	reqlog.Stop()
+
+	records := readRequestLog(t, path)
+	if len(records) != 7 {
+		t.Fatalf("record count mismatch: have %d, want 7: %v", len(records), records)
+	}
+	if records[0]["method"] != "test_repeat" || records[0]["transport"] != "http" || records[0]["size"] != float64(len(`"xxx"`)) {
+		t.Errorf("call record mismatch: %v", records[0])
+	}
+	if records[0]["params"] == "" || records[0]["remote"] == "" {
+		t.Errorf("call record incomplete: %v", records[0])
+	}
+	if records[1]["method"] != "test_returnError" || records[1]["err"] != "testError" {
+		t.Errorf("failure record mismatch: %v", records[1])
+	}
+	for i, method := range []string{"test_missing", "test_repeat", "test_echo", longMethod} {
+		if record := records[2+i]; record["method"] != method || record["err"] == nil {
+			t.Errorf("rejected call record mismatch: %v", record)
+		}
+	}
+	if records[6]["method"] != "test_sleep" || records[6]["msg"] != "Slow RPC call" {
+		t.Errorf("slow call record mismatch: %v", records[6])
+	}
+	// Invalid configurations are rejected
+	if err := reqlog.Start(RequestLogConfig{SampleRate: 2}); err == nil {
+		t.Error("invalid sample rate accepted")
+	}
+	if err := NewRequestLog("").Start(RequestLogConfig{SampleRate: 1}); err == nil {
+		t.Error("request log without file started")
+	}
+}
@@ -55,6 +55,7 @@ type Server struct {
 	batchResponseLimit int
 	httpBodyLimit      int
 	wsReadLimit        int64
+	requestLog         *RequestLog
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -98,6 +99,14 @@ func (s *Server) SetWebsocketReadLimit(limit int64) {
 	s.wsReadLimit = limit
 }
 
+// SetRequestLog sets the log recording the method calls served.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetRequestLog(log *RequestLog) {
+	s.requestLog = log
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either an RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -123,6 +132,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		requestLog:         s.requestLog,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
@@ -158,6 +168,7 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
 	h.allowSubscribe = false
+	h.requestLog = s.requestLog
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.readBatch()
@@ -20,9 +20,11 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
@@ -291,3 +293,70 @@ func TestServerWebsocketReadLimit(t *testing.T) {
 		})
 	}
 }
+
+// testGate is a CallGate rejecting a single method and batches above a size.
+type testGate struct {
+	method   string
+	maxBatch int
+}
+
+func (g *testGate) AdmitBatch(size int) error {
+	if size > g.maxBatch {
+		return &invalidRequestError{"batch rejected by gate"}
+	}
+	return nil
+}
+
+func (g *testGate) AdmitCall(method string, params json.RawMessage) error {
+	if method == g.method {
+		return &methodNotFoundError{method: method}
+	}
+	return nil
+}
+
+// Tests that the call gate set on the request context applies to the calls of
+// HTTP requests and WebSocket connections.
+func TestServerCallGate(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+
+	gate := &testGate{method: "test_echo", maxBatch: 2}
+	gated := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithCallGate(r.Context(), gate)))
+		})
+	}
+	httpsrv := httptest.NewServer(gated(server))
+	defer httpsrv.Close()
+	wssrv := httptest.NewServer(gated(server.WebsocketHandler([]string{"*"}, 0)))
+	defer wssrv.Close()
+
+	for _, url := range []string{httpsrv.URL, "ws:" + strings.TrimPrefix(wssrv.URL, "http:")} {
+		client, err := Dial(url)
+		if err != nil {
+			t.Fatalf("can't dial %s: %v", url, err)
+		}
+		defer client.Close()
+
+		var result echoResult
+		if err := client.Call(&result, "test_echo", "x", 1); err == nil {
+			t.Errorf("%s: gated method served", url)
+		}
+		if err := client.Call(nil, "test_noArgsRets"); err != nil {
+			t.Errorf("%s: admitted method failed: %v", url, err)
+		}
+		batch := make([]BatchElem, 3)
+		for i := range batch {
+			batch[i] = BatchElem{Method: "test_noArgsRets"}
+		}
+		err = client.BatchCall(batch)
+		if err == nil {
+			err = batch[0].Error
+		}
+		if err == nil || !strings.Contains(err.Error(), "batch rejected by gate") {
+			t.Errorf("%s: oversized batch not rejected: %v", url, err)
+		}
+	}
+}
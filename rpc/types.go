@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -68,10 +69,91 @@ const (
 	FinalizedBlockNumber = BlockNumber(-3)
 	LatestBlockNumber    = BlockNumber(-2)
 	PendingBlockNumber   = BlockNumber(-1)
+
+	// VotedBlockNumber is the highest block a quorum of validators has voted
+	// for, as observed by the local vote pool. It's ahead of SafeBlockNumber,
+	// which requires the votes to be aggregated into a subsequent header.
+	VotedBlockNumber = BlockNumber(-6)
+)
+
+const (
+	// finalizedByValidatorsBase is the offset of the block number range used to
+	// encode "finalized:<threshold>" tags.
+	finalizedByValidatorsBase = -100
+
+	// maxFinalizedValidators is the maximum explicit validator count accepted in
+	// a "finalized:<n>" tag.
+	maxFinalizedValidators = 1 << 16
 )
 
+// FinalizedByValidatorsBlockNumber returns the block tag resolving to the highest
+// block that is either fast finalized or has been built upon by the given number
+// of distinct validators. The threshold follows eth_getFinalizedBlock: -1, -2 and
+// -3 denote 1/2, 2/3 and all of the current validators, positive numbers denote
+// an absolute count.
+func FinalizedByValidatorsBlockNumber(verifiedValidatorNum int64) BlockNumber {
+	return BlockNumber(finalizedByValidatorsBase - verifiedValidatorNum)
+}
+
+// IsFastFinalityTag reports whether the block number is one of the fast finality
+// aware tags, which can only be resolved by a Parlia backend.
+func (bn BlockNumber) IsFastFinalityTag() bool {
+	if bn == VotedBlockNumber {
+		return true
+	}
+	_, ok := bn.FinalizedByValidators()
+	return ok
+}
+
+// FinalizedByValidators returns the validator threshold of a "finalized:<threshold>"
+// tag, and whether the block number is such a tag at all.
+func (bn BlockNumber) FinalizedByValidators() (int64, bool) {
+	n := finalizedByValidatorsBase - int64(bn)
+	if n == 0 || n < -3 || n > maxFinalizedValidators {
+		return 0, false
+	}
+	return n, true
+}
+
+// parseBlockTag parses the named block tags. The boolean reports whether the
+// input is a block tag at all, the error whether a finality threshold is invalid.
+func parseBlockTag(input string) (BlockNumber, bool, error) {
+	switch input {
+	case "earliest":
+		return EarliestBlockNumber, true, nil
+	case "latest":
+		return LatestBlockNumber, true, nil
+	case "pending":
+		return PendingBlockNumber, true, nil
+	case "finalized":
+		return FinalizedBlockNumber, true, nil
+	case "safe":
+		return SafeBlockNumber, true, nil
+	case "voted":
+		return VotedBlockNumber, true, nil
+	}
+	threshold, ok := strings.CutPrefix(input, "finalized:")
+	if !ok {
+		return 0, false, nil
+	}
+	switch threshold {
+	case "1/2":
+		return FinalizedByValidatorsBlockNumber(-1), true, nil
+	case "2/3":
+		return FinalizedByValidatorsBlockNumber(-2), true, nil
+	case "all":
+		return FinalizedByValidatorsBlockNumber(-3), true, nil
+	}
+	n, err := strconv.ParseUint(threshold, 10, 64)
+	if err != nil || n == 0 || n > maxFinalizedValidators {
+		return 0, true, fmt.Errorf("invalid finality threshold %q, want 1/2, 2/3, all or a validator count in [1,%d]", threshold, maxFinalizedValidators)
+	}
+	return FinalizedByValidatorsBlockNumber(int64(n)), true, nil
+}
+
 // UnmarshalJSON parses the given JSON fragment into a BlockNumber. It supports:
 // - "safe", "finalized", "latest", "earliest" or "pending" as string arguments
+// - "voted" or "finalized:<threshold>" (1/2, 2/3, all or a validator count)
 // - the block number
 // Returned errors:
 // - an invalid block number error when the given argument isn't a known strings
@@ -81,22 +163,11 @@ func (bn *BlockNumber) UnmarshalJSON(data []byte) error {
 	if len(input) >= 2 && input[0] == '"' && input[len(input)-1] == '"' {
 		input = input[1 : len(input)-1]
 	}
-
-	switch input {
-	case "earliest":
-		*bn = EarliestBlockNumber
-		return nil
-	case "latest":
-		*bn = LatestBlockNumber
-		return nil
-	case "pending":
-		*bn = PendingBlockNumber
-		return nil
-	case "finalized":
-		*bn = FinalizedBlockNumber
-		return nil
-	case "safe":
-		*bn = SafeBlockNumber
+	if tag, ok, err := parseBlockTag(input); ok {
+		if err != nil {
+			return err
+		}
+		*bn = tag
 		return nil
 	}
 
@@ -135,7 +206,20 @@ func (bn BlockNumber) String() string {
 		return "finalized"
 	case SafeBlockNumber:
 		return "safe"
+	case VotedBlockNumber:
+		return "voted"
 	default:
+		if n, ok := bn.FinalizedByValidators(); ok {
+			switch n {
+			case -1:
+				return "finalized:1/2"
+			case -2:
+				return "finalized:2/3"
+			case -3:
+				return "finalized:all"
+			}
+			return fmt.Sprintf("finalized:%d", n)
+		}
 		if bn < 0 {
 			return fmt.Sprintf("<invalid %d>", bn)
 		}
@@ -167,49 +251,32 @@ func (bnh *BlockNumberOrHash) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	switch input {
-	case "earliest":
-		bn := EarliestBlockNumber
-		bnh.BlockNumber = &bn
-		return nil
-	case "latest":
-		bn := LatestBlockNumber
-		bnh.BlockNumber = &bn
-		return nil
-	case "pending":
-		bn := PendingBlockNumber
-		bnh.BlockNumber = &bn
-		return nil
-	case "finalized":
-		bn := FinalizedBlockNumber
-		bnh.BlockNumber = &bn
-		return nil
-	case "safe":
-		bn := SafeBlockNumber
+	if bn, ok, err := parseBlockTag(input); ok {
+		if err != nil {
+			return err
+		}
 		bnh.BlockNumber = &bn
 		return nil
-	default:
-		if len(input) == 66 {
-			hash := common.Hash{}
-			err := hash.UnmarshalText([]byte(input))
-			if err != nil {
-				return err
-			}
-			bnh.BlockHash = &hash
-			return nil
-		} else {
-			blckNum, err := hexutil.DecodeUint64(input)
-			if err != nil {
-				return err
-			}
-			if blckNum > math.MaxInt64 {
-				return errors.New("blocknumber too high")
-			}
-			bn := BlockNumber(blckNum)
-			bnh.BlockNumber = &bn
-			return nil
+	}
+	if len(input) == 66 {
+		hash := common.Hash{}
+		err := hash.UnmarshalText([]byte(input))
+		if err != nil {
+			return err
 		}
+		bnh.BlockHash = &hash
+		return nil
+	}
+	blckNum, err := hexutil.DecodeUint64(input)
+	if err != nil {
+		return err
+	}
+	if blckNum > math.MaxInt64 {
+		return errors.New("blocknumber too high")
 	}
+	bn := BlockNumber(blckNum)
+	bnh.BlockNumber = &bn
+	return nil
 }
 
 func (bnh *BlockNumberOrHash) Number() (BlockNumber, bool) {
@@ -52,6 +52,14 @@ func TestBlockNumberJSONUnmarshal(t *testing.T) {
 		16: {`someString`, true, BlockNumber(0)},
 		17: {`""`, true, BlockNumber(0)},
 		18: {``, true, BlockNumber(0)},
+		19: {`"voted"`, false, VotedBlockNumber},
+		20: {`"finalized:1/2"`, false, FinalizedByValidatorsBlockNumber(-1)},
+		21: {`"finalized:2/3"`, false, FinalizedByValidatorsBlockNumber(-2)},
+		22: {`"finalized:all"`, false, FinalizedByValidatorsBlockNumber(-3)},
+		23: {`"finalized:15"`, false, FinalizedByValidatorsBlockNumber(15)},
+		24: {`"finalized:0"`, true, BlockNumber(0)},
+		25: {`"finalized:3/4"`, true, BlockNumber(0)},
+		26: {`"finalized:"`, true, BlockNumber(0)},
 	}
 
 	for i, test := range tests {
@@ -147,6 +155,9 @@ func TestBlockNumberOrHash_WithNumber_MarshalAndUnmarshal(t *testing.T) {
 		{"earliest", int64(EarliestBlockNumber)},
 		{"safe", int64(SafeBlockNumber)},
 		{"finalized", int64(FinalizedBlockNumber)},
+		{"voted", int64(VotedBlockNumber)},
+		{"finalized:2/3", int64(FinalizedByValidatorsBlockNumber(-2))},
+		{"finalized:7", int64(FinalizedByValidatorsBlockNumber(7))},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
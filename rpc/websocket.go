@@ -66,6 +66,7 @@ func (s *Server) WebsocketHandler(allowedOrigins []string, messageSizeLimit int6
 			limit = messageSizeLimit
 		}
 		codec := newWebsocketCodec(conn, r.Host, r.Header, limit)
+		codec.(*websocketCodec).gate = callGateFromContext(r.Context())
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -288,6 +289,7 @@ type websocketCodec struct {
 	*jsonCodec
 	conn *websocket.Conn
 	info PeerInfo
+	gate CallGate // admits the calls of the connection, set by the upgrade request
 
 	wg           sync.WaitGroup
 	pingReset    chan struct{}
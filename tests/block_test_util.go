@@ -30,8 +30,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/parlia/parliatest"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -46,11 +48,21 @@ import (
 	"github.com/ethereum/go-ethereum/triedb/pathdb"
 )
 
+// ParliaSealEngine is the seal engine of block tests executed on Parlia. The
+// blocks are not required to be sealed by a validator set, but system
+// transactions and the end of block processing are checked.
+const ParliaSealEngine = "Parlia"
+
 // A BlockTest checks handling of entire blocks.
 type BlockTest struct {
 	json btJSON
 }
 
+// SetSealEngine overrides the seal engine declared by the test.
+func (t *BlockTest) SetSealEngine(engine string) {
+	t.json.SealEngine = engine
+}
+
 // UnmarshalJSON implements json.Unmarshaler interface.
 func (t *BlockTest) UnmarshalJSON(in []byte) error {
 	return json.Unmarshal(in, &t.json)
@@ -112,7 +124,7 @@ type btHeaderMarshaling struct {
 }
 
 func (t *BlockTest) Run(snapshotter bool, scheme string, witness bool, tracer *tracing.Hooks, postCheck func(error, *core.BlockChain)) (result error) {
-	config, ok := Forks[t.json.Network]
+	config, ok := forkConfig(t.json.Network)
 	if !ok {
 		return UnsupportedForkError{t.json.Network}
 	}
@@ -148,8 +160,12 @@ func (t *BlockTest) Run(snapshotter bool, scheme string, witness bool, tracer *t
 	if gblock.Root() != t.json.Genesis.StateRoot {
 		return fmt.Errorf("genesis block state root does not match test: computed=%x, test=%x", gblock.Root().Bytes()[:6], t.json.Genesis.StateRoot[:6])
 	}
-	// Wrap the original engine within the beacon-engine
-	engine := beacon.New(ethash.NewFaker())
+	// Wrap the original engine within the beacon-engine, unless the test runs
+	// on Parlia
+	var engine consensus.Engine = beacon.New(ethash.NewFaker())
+	if t.json.SealEngine == ParliaSealEngine {
+		engine = parliatest.NewFaker(gspec.Config)
+	}
 
 	options := &core.BlockChainConfig{
 		TrieCleanLimit: 0,
@@ -740,6 +740,9 @@ func AvailableForks() []string {
 	for k := range Forks {
 		availableForks = append(availableForks, k)
 	}
+	for k := range bscForkConfigs {
+		availableForks = append(availableForks, k)
+	}
 	sort.Strings(availableForks)
 	return availableForks
 }
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// bscForks lists the BSC hard forks in activation order along with the chain
+// config fields they switch on. Each fork defines a ruleset named after it,
+// activating all BSC forks up to and including it from genesis.
+var bscForks = []struct {
+	name     string
+	blocks   func(c *params.ChainConfig) []**big.Int
+	times    func(c *params.ChainConfig) []**uint64
+	schedule func(s *params.BlobScheduleConfig)
+}{
+	{name: "Luban", blocks: func(c *params.ChainConfig) []**big.Int {
+		return []**big.Int{&c.RamanujanBlock, &c.NielsBlock, &c.MirrorSyncBlock, &c.BrunoBlock, &c.EulerBlock,
+			&c.NanoBlock, &c.MoranBlock, &c.GibbsBlock, &c.PlanckBlock, &c.LubanBlock}
+	}},
+	{name: "Plato", blocks: func(c *params.ChainConfig) []**big.Int { return []**big.Int{&c.PlatoBlock} }},
+	{name: "Hertz", blocks: func(c *params.ChainConfig) []**big.Int {
+		return []**big.Int{&c.BerlinBlock, &c.LondonBlock, &c.HertzBlock}
+	}},
+	{name: "Hertzfix", blocks: func(c *params.ChainConfig) []**big.Int { return []**big.Int{&c.HertzfixBlock} }},
+	{name: "Kepler", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.ShanghaiTime, &c.KeplerTime} }},
+	{name: "Feynman", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.FeynmanTime, &c.FeynmanFixTime} }},
+	{name: "Haber", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.CancunTime, &c.HaberTime} },
+		schedule: func(s *params.BlobScheduleConfig) { s.Cancun = params.DefaultCancunBlobConfig }},
+	{name: "HaberFix", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.HaberFixTime} }},
+	{name: "Bohr", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.BohrTime} }},
+	{name: "Pascal", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.PascalTime, &c.PragueTime} },
+		schedule: func(s *params.BlobScheduleConfig) { s.Prague = params.DefaultPragueBlobConfigBSC }},
+	{name: "Lorentz", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.LorentzTime} }},
+	{name: "Maxwell", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.MaxwellTime} }},
+	{name: "Fermi", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.FermiTime} }},
+	{name: "Mendel", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.OsakaTime, &c.MendelTime} },
+		schedule: func(s *params.BlobScheduleConfig) { s.Osaka = params.DefaultOsakaBlobConfigBSC }},
+	{name: "Pasteur", times: func(c *params.ChainConfig) []**uint64 { return []**uint64{&c.PasteurTime} }},
+}
+
+// bscForkConfigs holds the rulesets of the BSC forks. They are kept apart from
+// the upstream Forks table, which drives the Ethereum test suites.
+var bscForkConfigs = newBSCForkConfigs()
+
+// forkConfig returns the chain config of the named ruleset, looking up the
+// upstream forks first and the BSC forks next.
+func forkConfig(name string) (*params.ChainConfig, bool) {
+	if config, ok := Forks[name]; ok {
+		return config, true
+	}
+	config, ok := bscForkConfigs[name]
+	return config, ok
+}
+
+func newBSCForkConfigs() map[string]*params.ChainConfig {
+	configs := make(map[string]*params.ChainConfig, len(bscForks))
+	for i, fork := range bscForks {
+		config := &params.ChainConfig{
+			ChainID:             big.NewInt(56),
+			HomesteadBlock:      big.NewInt(0),
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: big.NewInt(0),
+			PetersburgBlock:     big.NewInt(0),
+			IstanbulBlock:       big.NewInt(0),
+			MuirGlacierBlock:    big.NewInt(0),
+			Parlia:              &params.ParliaConfig{},
+			BlobScheduleConfig:  &params.BlobScheduleConfig{},
+		}
+		for _, active := range bscForks[:i+1] {
+			if active.blocks != nil {
+				for _, field := range active.blocks(config) {
+					*field = big.NewInt(0)
+				}
+			}
+			if active.times != nil {
+				for _, field := range active.times(config) {
+					*field = u64(0)
+				}
+			}
+			if active.schedule != nil {
+				active.schedule(config.BlobScheduleConfig)
+			}
+		}
+		configs[fork.name] = config
+	}
+	return configs
+}
@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+)
+
+// Tests that the BSC rulesets resolve by name without joining the upstream
+// forks driving the Ethereum test suites.
+func TestBSCForks(t *testing.T) {
+	available := AvailableForks()
+	for _, fork := range bscForks {
+		if _, ok := Forks[fork.name]; ok {
+			t.Errorf("fork %s registered in the upstream forks", fork.name)
+		}
+		config, ok := forkConfig(fork.name)
+		if !ok || config.Parlia == nil {
+			t.Errorf("fork %s: missing Parlia ruleset", fork.name)
+		}
+		if !slices.Contains(available, fork.name) {
+			t.Errorf("fork %s not listed as available", fork.name)
+		}
+	}
+	if config, ok := forkConfig("Cancun"); !ok || config.Parlia != nil {
+		t.Error("upstream fork not resolved")
+	}
+}
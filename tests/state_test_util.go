@@ -168,7 +168,7 @@ func GetChainConfig(forkString string) (baseConfig *params.ChainConfig, eips []i
 		ok                    bool
 		baseName, eipsStrings = splitForks[0], splitForks[1:]
 	)
-	if baseConfig, ok = Forks[baseName]; !ok {
+	if baseConfig, ok = forkConfig(baseName); !ok {
 		return nil, nil, UnsupportedForkError{baseName}
 	}
 	for _, eip := range eipsStrings {